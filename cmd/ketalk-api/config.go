@@ -16,6 +16,8 @@ type Config struct {
 
 type ServerConfig struct {
 	Port int `yaml:"port" env:"PORT" env-default:"8080"`
+	// TrustedProxies may set the client ip in X-Forwarded-For, without them the remote address is used
+	TrustedProxies []string `yaml:"trustedProxies" env:"TRUSTED_PROXIES" env-separator:","`
 }
 
 func (cfg *Config) Load() error {
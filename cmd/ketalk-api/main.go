@@ -44,19 +44,27 @@ func main() {
 
 func run(ctx context.Context, cfg Config) error {
 	router := gin.Default()
+	// the client ip is used for login rate limiting, so it must not be spoofable
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return err
+	}
+
+	if err := cfg.Config.Auth.LoadKeys(); err != nil {
+		return err
+	}
 
-	redis, err := conn_redis.Init(ctx, cfg.Config.Redis)
+	redisConn, err := conn_redis.Connect(ctx, cfg.Config.Redis)
 	if err != nil {
 		return err
 	}
 
-	middleware, err := handler.NewMiddleware(ctx, &cfg.Config.DB)
+	middleware, err := handler.NewMiddleware(ctx, &cfg.Config.DB, redisConn)
 	if err != nil {
 		return err
 	}
 	router.Use(middleware.AuthMiddleware(cfg.Config.Auth))
 	router.Use(middleware.HttpMiddleware()) // not sure why i added ??
-	if err := handler.InitHandlers(ctx, middleware, redis, router, cfg.Config); err != nil {
+	if err := handler.InitHandlers(ctx, middleware, redisConn, router, cfg.Config); err != nil {
 		return err
 	}
 
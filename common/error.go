@@ -1,7 +1,10 @@
 package common
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -9,3 +12,54 @@ import (
 var ErrMoreThanOneRowUpdated = fmt.Errorf("more than one row updated")
 var ErrInvalidInput = fmt.Errorf("invalid input")
 var ErrRecordNotFound = gorm.ErrRecordNotFound
+var ErrUnauthorized = fmt.Errorf("unauthorized")
+var ErrForbidden = fmt.Errorf("forbidden")
+var ErrTooManyAttempts = fmt.Errorf("too many attempts")
+var ErrConflict = fmt.Errorf("conflict")
+var ErrNotFound = fmt.Errorf("not found")
+var ErrUserNotVerified = fmt.Errorf("%w: user is not verified", ErrForbidden)
+var ErrAccountSuspended = fmt.Errorf("%w: account is suspended", ErrForbidden)
+
+// RetryAfterError is returned when the client has to wait before trying again,
+// the wait is sent in the Retry-After header.
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v, retry after %d seconds", e.Err, RetryAfterSeconds(e.RetryAfter))
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfterSeconds rounds the wait up, so a client retrying right on time is not rejected again.
+func RetryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// StatusCode maps an error to the http status it is responded with.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrTooManyAttempts):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,126 @@
+package common_test
+
+import (
+	"fmt"
+	"ketalk-api/common"
+	"ketalk-api/password"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	conversation_manager "ketalk-api/pkg/manager/conversation"
+	item_manager "ketalk-api/pkg/manager/item"
+	moderation_manager "ketalk-api/pkg/manager/moderation"
+	"ketalk-api/pkg/manager/port"
+	review_manager "ketalk-api/pkg/manager/review"
+	user_manager "ketalk-api/pkg/manager/user"
+	"ketalk-api/pkg/provider"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{common.ErrInvalidInput, http.StatusBadRequest},
+		{common.ErrUnauthorized, http.StatusUnauthorized},
+		{common.ErrForbidden, http.StatusForbidden},
+		{common.ErrNotFound, http.StatusNotFound},
+		{common.ErrConflict, http.StatusConflict},
+		{common.ErrTooManyAttempts, http.StatusTooManyRequests},
+		{fmt.Errorf("failed to load user: %w", common.ErrNotFound), http.StatusNotFound},
+		{&common.RetryAfterError{Err: common.ErrTooManyAttempts, RetryAfter: time.Minute}, http.StatusTooManyRequests},
+		{fmt.Errorf("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := common.StatusCode(tt.err); got != tt.want {
+			t.Errorf("StatusCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestClientErrorStatusCode keeps the errors caused by the request out of the 500 response
+func TestClientErrorStatusCode(t *testing.T) {
+	clientErrors := []error{
+		common.ErrUserNotVerified,
+		common.ErrAccountSuspended,
+		password.ErrPasswordTooShort,
+		password.ErrPasswordTooWeak,
+		password.ErrPasswordCommon,
+		provider.ErrUnsupportedProvider,
+		auth_manager.ErrInvalidCredentials,
+		auth_manager.ErrInvalidEmail,
+		auth_manager.ErrEmailAlreadyRegistered,
+		auth_manager.ErrEmailAlreadyVerified,
+		auth_manager.ErrVerificationCodeNotFound,
+		auth_manager.ErrVerificationCodeExpired,
+		auth_manager.ErrVerificationCodeRecentlySent,
+		auth_manager.ErrInvalidVerificationCode,
+		auth_manager.ErrTooManyVerificationAttempts,
+		auth_manager.ErrInvalidRefreshToken,
+		auth_manager.ErrRefreshTokenExpired,
+		auth_manager.ErrRefreshTokenReused,
+		auth_manager.ErrRefreshTokenDeviceMismatch,
+		auth_manager.ErrSessionNotFound,
+		auth_manager.ErrInvalidPhone,
+		auth_manager.ErrPhoneAlreadyRegistered,
+		auth_manager.ErrLocationRequired,
+		auth_manager.ErrUnknownSession,
+		auth_manager.ErrInvalidProviderToken,
+		auth_manager.ErrIdentityLinkedToOtherUser,
+		auth_manager.ErrProviderAlreadyLinked,
+		auth_manager.ErrIdentityNotFound,
+		auth_manager.ErrTotpAlreadyEnabled,
+		auth_manager.ErrTotpNotEnrolled,
+		auth_manager.ErrTotpNotEnabled,
+		auth_manager.ErrInvalidTotpCode,
+		auth_manager.ErrInvalidMfaChallenge,
+		user_manager.ErrInvalidRole,
+		user_manager.ErrDataExportNotFound,
+		user_manager.ErrUserNotFound,
+		user_manager.ErrCannotBlockSelf,
+		user_manager.ErrBlockNotFound,
+		user_manager.ErrUnknownUpload,
+		user_manager.ErrUploadNotFound,
+		user_manager.ErrInvalidImageType,
+		user_manager.ErrImageTooLarge,
+		user_manager.ErrInvalidLocale,
+		user_manager.ErrInvalidQuietHours,
+		user_manager.ErrInvalidTimezone,
+		user_manager.ErrUnverifiedAccountExists,
+		user_manager.ErrGeofenceChangeCooldown,
+		port.ErrIdentityAlreadyLinked,
+		port.ErrLastLoginMethod,
+		item_manager.ErrInvalidItemStatus,
+		item_manager.ErrNotItemOwner,
+		item_manager.ErrInvalidBuyer,
+		item_manager.ErrBuyerNotInConversation,
+		item_manager.ErrItemAlreadyPurchased,
+		item_manager.ErrInvalidCursor,
+		item_manager.ErrItemModerated,
+		item_manager.ErrInvalidSort,
+		item_manager.ErrBumpCooldown,
+		review_manager.ErrInvalidRating,
+		review_manager.ErrReviewTooLong,
+		review_manager.ErrNotPurchased,
+		review_manager.ErrBuyerRequired,
+		review_manager.ErrAlreadyReviewed,
+		review_manager.ErrReviewNotFound,
+		review_manager.ErrReviewEditWindowClosed,
+		moderation_manager.ErrInvalidTargetType,
+		moderation_manager.ErrInvalidReason,
+		moderation_manager.ErrDetailsTooLong,
+		moderation_manager.ErrCannotReportOwn,
+		moderation_manager.ErrAlreadyReported,
+		moderation_manager.ErrReportNotFound,
+		moderation_manager.ErrTargetNotFound,
+		moderation_manager.ErrReportAlreadyResolved,
+		moderation_manager.ErrCannotSuspendModerator,
+		conversation_manager.ErrUserBlocked,
+	}
+	for _, err := range clientErrors {
+		if got := common.StatusCode(err); got < 400 || got >= 500 {
+			t.Errorf("StatusCode(%q) = %d, want a 4xx status", err, got)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package common
 
 import (
+	"errors"
 	"ketalk-api/common/response"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,12 +14,20 @@ type HandlerFunc func(ctx *gin.Context, req *http.Request) (interface{}, error)
 
 func GenericHandler(h HandlerFunc) func(ctx *gin.Context) {
 	return func(ctx *gin.Context) {
+		// anonymous requests are answered in the language of the client, HandlerWithAuth prefers the user's locale
+		if locale, ok := ParseAcceptLanguage(ctx.GetHeader("Accept-Language")); ok {
+			ctx.Request = ctx.Request.WithContext(WithLocale(ctx.Request.Context(), locale))
+		}
 		val := ctx.Request
 		resp, err := h(ctx, val)
 		var sendErr error
 		if err != nil {
 			log.Printf("failed with error: %v\n", err)
-			sendErr = response.NewError(err, http.StatusInternalServerError).Send(ctx.Writer)
+			var retryAfterErr *RetryAfterError
+			if errors.As(err, &retryAfterErr) {
+				ctx.Header("Retry-After", strconv.Itoa(RetryAfterSeconds(retryAfterErr.RetryAfter)))
+			}
+			sendErr = response.NewErrorMessage(LocalizeError(ctx.Request.Context(), err), StatusCode(err)).Send(ctx.Writer)
 		} else {
 			sendErr = response.NewSuccess(resp, http.StatusOK).Send(ctx.Writer)
 		}
@@ -0,0 +1,197 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Locale string
+
+const (
+	LocaleUz Locale = "uz"
+	LocaleRu Locale = "ru"
+	LocaleEn Locale = "en"
+)
+
+// DefaultLocale is used when neither the user nor the request asks for a supported locale
+const DefaultLocale = LocaleEn
+
+// fallbackChain is the order locales are tried in, a locale falls back to the ones after it
+var fallbackChain = []Locale{LocaleUz, LocaleRu, LocaleEn}
+
+func (l Locale) IsValid() bool {
+	for _, locale := range fallbackChain {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Fallbacks returns the locale followed by the locales to try when there is no translation for it, e.g. uz, ru, en
+func (l Locale) Fallbacks() []Locale {
+	for i, locale := range fallbackChain {
+		if l == locale {
+			return fallbackChain[i:]
+		}
+	}
+	return []Locale{DefaultLocale}
+}
+
+// Localize picks the translation for the locale following its fallbacks, ok is false when there is none
+func Localize[T any](translations map[string]T, locale Locale) (T, bool) {
+	for _, l := range locale.Fallbacks() {
+		if translation, ok := translations[string(l)]; ok {
+			return translation, true
+		}
+	}
+	var empty T
+	return empty, false
+}
+
+// ParseAcceptLanguage returns the supported locale the client prefers the most, e.g. ru for "ru-RU,ru;q=0.9,en;q=0.8"
+func ParseAcceptLanguage(header string) (Locale, bool) {
+	type weighted struct {
+		locale Locale
+		q      float64
+	}
+	var candidates []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		locale := Locale(base)
+		if !locale.IsValid() {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, weighted{locale, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale, true
+}
+
+type localeKey struct{}
+
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// GetLocale returns the locale of the request, managers are often handed the gin context
+// so the locale is read from its request
+func GetLocale(ctx context.Context) Locale {
+	if ginCtx, ok := ctx.(*gin.Context); ok && ginCtx.Request != nil {
+		ctx = ginCtx.Request.Context()
+	}
+	if locale, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+type errorMessages struct {
+	err          error
+	translations map[string]string
+}
+
+// errorCatalog holds the translations of errors, English is the message of the error itself
+var errorCatalog []errorMessages
+
+// RegisterErrorMessages adds translations for an error, it is meant to be called from init
+func RegisterErrorMessages(err error, translations map[Locale]string) {
+	messages := make(map[string]string, len(translations))
+	for locale, message := range translations {
+		messages[string(locale)] = message
+	}
+	errorCatalog = append(errorCatalog, errorMessages{err, messages})
+}
+
+// LocalizeError returns the message of the most specific error in the chain that has a translation,
+// followed by what the errors wrapping it add. The error is sent as is when there is none, also for English.
+func LocalizeError(ctx context.Context, err error) string {
+	locale := GetLocale(ctx)
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		for _, messages := range errorCatalog {
+			if messages.err != e {
+				continue
+			}
+			for _, l := range locale.Fallbacks() {
+				if l == LocaleEn {
+					return err.Error()
+				}
+				if message, ok := messages.translations[string(l)]; ok {
+					return message + errorDetail(err, e)
+				}
+			}
+		}
+	}
+	return err.Error()
+}
+
+// errorDetail is the part of the message of err added around the translated error it wraps,
+// the detail is not translated but tells e.g. which role is missing
+func errorDetail(err error, translated error) string {
+	if err == translated {
+		return ""
+	}
+	message, translatedMessage := err.Error(), translated.Error()
+	// errors are wrapped as "%w: detail" throughout
+	if strings.HasPrefix(message, translatedMessage) {
+		return strings.TrimPrefix(message, translatedMessage)
+	}
+	return fmt.Sprintf(" (%s)", message)
+}
+
+func init() {
+	RegisterErrorMessages(ErrAccountSuspended, map[Locale]string{
+		LocaleUz: "hisob to'xtatilgan",
+		LocaleRu: "аккаунт приостановлен",
+	})
+	RegisterErrorMessages(ErrUnauthorized, map[Locale]string{
+		LocaleUz: "avtorizatsiyadan o'tilmagan",
+		LocaleRu: "требуется авторизация",
+	})
+	RegisterErrorMessages(ErrForbidden, map[Locale]string{
+		LocaleUz: "ruxsat berilmagan",
+		LocaleRu: "доступ запрещён",
+	})
+	RegisterErrorMessages(ErrTooManyAttempts, map[Locale]string{
+		LocaleUz: "urinishlar soni juda ko'p",
+		LocaleRu: "слишком много попыток",
+	})
+	RegisterErrorMessages(ErrUserNotVerified, map[Locale]string{
+		LocaleUz: "foydalanuvchi tasdiqlanmagan",
+		LocaleRu: "пользователь не подтверждён",
+	})
+	RegisterErrorMessages(ErrInvalidInput, map[Locale]string{
+		LocaleUz: "noto'g'ri ma'lumot",
+		LocaleRu: "неверные данные",
+	})
+	RegisterErrorMessages(ErrNotFound, map[Locale]string{
+		LocaleUz: "topilmadi",
+		LocaleRu: "не найдено",
+	})
+	RegisterErrorMessages(ErrRecordNotFound, map[Locale]string{
+		LocaleUz: "topilmadi",
+		LocaleRu: "не найдено",
+	})
+}
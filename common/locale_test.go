@@ -0,0 +1,55 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+		wantOk bool
+	}{
+		{header: "ru-RU,ru;q=0.9,en;q=0.8", want: LocaleRu, wantOk: true},
+		{header: "uz", want: LocaleUz, wantOk: true},
+		{header: "EN-us", want: LocaleEn, wantOk: true},
+		{header: "en;q=0.5, uz;q=0.7", want: LocaleUz, wantOk: true},
+		{header: "de-DE,de;q=0.9,ru;q=0.3", want: LocaleRu, wantOk: true},
+		{header: "ru;q=0.5,uz;q=0.5", want: LocaleRu, wantOk: true},
+		{header: "ru;q=0,en;q=0.1", want: LocaleEn, wantOk: true},
+		{header: "ru;q=abc,uz;q=0.2", want: LocaleUz, wantOk: true},
+		{header: "de,fr", wantOk: false},
+		{header: "*", wantOk: false},
+		{header: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseAcceptLanguage(tt.header)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("ParseAcceptLanguage(%q) = %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
+
+func TestLocalizeError(t *testing.T) {
+	errWithDetail := fmt.Errorf("%w: requires admin role", ErrForbidden)
+	tests := []struct {
+		name   string
+		locale Locale
+		err    error
+		want   string
+	}{
+		{name: "translated", locale: LocaleRu, err: ErrForbidden, want: "доступ запрещён"},
+		{name: "detail is kept", locale: LocaleUz, err: errWithDetail, want: "ruxsat berilmagan: requires admin role"},
+		{name: "english is the error itself", locale: LocaleEn, err: errWithDetail, want: "forbidden: requires admin role"},
+		{name: "most specific translation", locale: LocaleRu, err: fmt.Errorf("%w: until tomorrow", ErrAccountSuspended), want: "аккаунт приостановлен: until tomorrow"},
+		{name: "no translation", locale: LocaleRu, err: fmt.Errorf("boom"), want: "boom"},
+	}
+	for _, tt := range tests {
+		got := LocalizeError(WithLocale(context.Background(), tt.locale), tt.err)
+		if got != tt.want {
+			t.Errorf("%s: LocalizeError = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
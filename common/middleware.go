@@ -12,4 +12,5 @@ type Middleware interface {
 	HttpMiddleware() gin.HandlerFunc
 	ValidateUserAuthorization(ctx context.Context) error
 	HandlerWithAuth(handler HandlerFunc) HandlerFunc
+	HandlerWithRole(role Role, handler HandlerFunc) HandlerFunc
 }
@@ -3,6 +3,7 @@ package common
 import (
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -11,6 +12,9 @@ type CreatedUpdated struct {
 	UpdatedAt time.Time
 }
 
+// DeletedUserID replaces the id of a deleted user in content that is kept for the other users, like messages
+var DeletedUserID = uuid.Nil
+
 type CreatedDeleted struct {
 	CreatedAt time.Time
 	DeletedAt gorm.DeletedAt
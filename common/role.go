@@ -0,0 +1,30 @@
+package common
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+var roleRanks = map[Role]int{
+	RoleUser:      1,
+	RoleModerator: 2,
+	RoleAdmin:     3,
+}
+
+func (r Role) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// Includes reports whether the role grants at least the permissions of the given one,
+// e.g. an admin can do everything a moderator can.
+func (r Role) Includes(role Role) bool {
+	rank, ok := roleRanks[r]
+	if !ok {
+		return false
+	}
+	return rank >= roleRanks[role]
+}
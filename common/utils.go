@@ -18,6 +18,19 @@ func GetUserId(ctx context.Context) (uuid.UUID, error) {
 	return uuid.Parse(tokenData.Subject)
 }
 
+// GetDeviceId returns the device of the session the access token was issued for.
+// Tokens issued before sessions were tracked have no device.
+func GetDeviceId(ctx context.Context) (string, error) {
+	tokenData, err := jwt.GetJWTToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	if tokenData.DeviceID == "" {
+		return "", fmt.Errorf("token has no device")
+	}
+	return tokenData.DeviceID, nil
+}
+
 func GetLocation(req *http.Request) (*Location, error) {
 	latitude := req.Header.Get("latitude")
 	longitude := req.Header.Get("longitude")
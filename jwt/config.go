@@ -2,19 +2,31 @@ package jwt
 
 import (
 	"time"
-
-	"github.com/golang-jwt/jwt/v4"
 )
 
 type Config struct {
-	Issuer                     string        `yaml:"issuer" env:"AUTH_JWT_ISSUER"`
+	Issuer string `yaml:"issuer" env:"AUTH_JWT_ISSUER"`
+	// Strict tells in the 401 of routes that need a user why the token was rejected, e.g. that it expired,
+	// public routes handle requests with an invalid, expired or revoked token as unauthenticated either way
+	Strict bool `yaml:"strict" env:"AUTH_STRICT" env-default:"true"`
+	// SigningKeyFile is a PEM encoded RSA or Ed25519 private key, tokens are signed with RS256 or EdDSA accordingly.
+	SigningKeyFile string `yaml:"signingKeyFile" env:"AUTH_JWT_SIGNING_KEY_FILE"`
+	// EphemeralSigningKey generates a signing key on startup when SigningKeyFile is empty, tokens don't survive restarts.
+	// It is meant for local development, the startup fails without a signing key otherwise.
+	EphemeralSigningKey bool   `yaml:"ephemeralSigningKey" env:"AUTH_JWT_EPHEMERAL_SIGNING_KEY"`
+	KeyID               string `yaml:"keyID" env:"AUTH_JWT_KEYID"`
+	// VerificationKeyFiles maps the key id of retired signing keys to their PEM encoded public key,
+	// they are kept until the tokens signed with them expire.
+	VerificationKeyFiles map[string]string `yaml:"verificationKeyFiles" env:"AUTH_JWT_VERIFICATION_KEY_FILES"`
+	// Key and LegacyKeyID are the HS256 secret used before asymmetric signing, only accepted for verification.
 	Key                        string        `yaml:"key" env:"AUTH_JWT_KEY"`
-	KeyID                      string        `yaml:"keyID" env:"AUTH_JWT_KEYID"`
+	LegacyKeyID                string        `yaml:"legacyKeyID" env:"AUTH_JWT_LEGACY_KEYID"`
 	ValidDuration              time.Duration `yaml:"validDuration" env:"AUTH_JWT_TOKEN_VALID_DURATION" env-default:"10h"`
 	RefreshTokenExpiryDuration time.Duration `yaml:"refreshTokenExpiryDuration" env:"AUTH_JWT_REFRESH_TOKEN_EXPIRY_DURATION" env-default:"100h"`
-}
 
-var JWTMethod = jwt.SigningMethodHS256
+	// keys is shared by copies of the config, it is set by LoadKeys
+	keys *keySet
+}
 
 func (c *Config) SetDefaults() {
 	if c.Issuer == "" {
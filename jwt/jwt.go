@@ -18,9 +18,18 @@ const (
 	JWTTokenContextKey
 )
 
+func init() {
+	// iat carries milliseconds, so a token issued right after a revocation is told apart
+	// from the tokens it revoked even within the same second
+	jwt.TimePrecision = time.Millisecond
+}
+
 type JWTClaims struct {
 	jwt.RegisteredClaims
 	UserID string `json:"username,omitempty"`
+	// DeviceID identifies the session the token was issued for
+	DeviceID string `json:"device_id,omitempty"`
+	Role     string `json:"role,omitempty"`
 }
 
 func GetJWTToken(ctx context.Context) (*JWTClaims, error) {
@@ -36,6 +45,8 @@ func IssueToken(cfg Config, userID uuid.UUID, additionalDetails map[string]inter
 	subject := fmt.Sprintf("%+v", userID)
 
 	jwtToken := jwt.RegisteredClaims{
+		// the id lets a single token be revoked before it expires
+		ID:        uuid.NewString(),
 		Issuer:    cfg.Issuer,
 		Subject:   subject,
 		ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.ValidDuration)),
@@ -52,24 +63,33 @@ func IssueToken(cfg Config, userID uuid.UUID, additionalDetails map[string]inter
 	for detail, value := range additionalDetails {
 		dataValues[detail] = value
 	}
-	if cfg.Key == "" || cfg.KeyID == "" {
-		return "", fmt.Errorf("should set secret key")
+	if cfg.keys == nil {
+		return "", fmt.Errorf("jwt keys are not loaded")
 	}
-	token := jwt.NewWithClaims(JWTMethod, jwt.MapClaims(dataValues))
-	token.Header["kid"] = cfg.KeyID
-	return token.SignedString([]byte(cfg.Key))
+	token := jwt.NewWithClaims(cfg.keys.signingMethod, jwt.MapClaims(dataValues))
+	token.Header["kid"] = cfg.keys.signingKeyID
+	return token.SignedString(cfg.keys.signingKey)
 }
 
 func ParseJWTToken(ctx context.Context, cfg Config, tokenString string) (context.Context, error) {
+	if cfg.keys == nil {
+		return ctx, fmt.Errorf("jwt keys are not loaded")
+	}
 	claims := JWTClaims{}
 	jwtToken, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
-		if token.Method.Alg() != JWTMethod.Name {
-			return nil, fmt.Errorf("invalid method")
+		keyID, ok := token.Header["kid"].(string)
+		if !ok || len(keyID) == 0 {
+			return nil, fmt.Errorf("invalid key id")
+		}
+		key, ok := cfg.keys.verificationKeys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("invalid key id")
 		}
-		if keyID, ok := token.Header["kid"].(string); ok && len(keyID) > 0 && cfg.KeyID == keyID {
-			return []byte(cfg.Key), nil
+		// the algorithm is bound to the key, so a token can't pick a weaker one
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("invalid method")
 		}
-		return nil, fmt.Errorf("invalid key id")
+		return key.key, nil
 	})
 	if err != nil {
 		return ctx, fmt.Errorf("failed to verify token: %+v", err)
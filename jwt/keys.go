@@ -0,0 +1,204 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+type keySet struct {
+	signingKeyID     string
+	signingMethod    jwt.SigningMethod
+	signingKey       crypto.Signer
+	verificationKeys map[string]verificationKey
+}
+
+type JSONWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// LoadKeys reads the signing and verification keys, it must be called before issuing or parsing tokens.
+func (c *Config) LoadKeys() error {
+	keys := &keySet{
+		verificationKeys: make(map[string]verificationKey),
+	}
+
+	if c.SigningKeyFile != "" {
+		if c.KeyID == "" {
+			return fmt.Errorf("should set key id of the signing key")
+		}
+		signingKey, err := readPrivateKey(c.SigningKeyFile)
+		if err != nil {
+			return err
+		}
+		keys.signingKey = signingKey
+		keys.signingKeyID = c.KeyID
+	} else if !c.EphemeralSigningKey {
+		return fmt.Errorf("should set the signing key file, or enable the ephemeral signing key for development")
+	} else {
+		_, signingKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		keyID := make([]byte, 8)
+		if _, err := rand.Read(keyID); err != nil {
+			return err
+		}
+		log.Printf("no jwt signing key configured, using an ephemeral key")
+		keys.signingKey = signingKey
+		keys.signingKeyID = fmt.Sprintf("ephemeral-%s", hex.EncodeToString(keyID))
+	}
+	method, err := signingMethod(keys.signingKey.Public())
+	if err != nil {
+		return err
+	}
+	keys.signingMethod = method
+	keys.verificationKeys[keys.signingKeyID] = verificationKey{method, keys.signingKey.Public()}
+
+	for keyID, file := range c.VerificationKeyFiles {
+		// a second key under the same id would replace the first and reject the tokens signed with it
+		if keyID == keys.signingKeyID {
+			return fmt.Errorf("verification key id %s is the id of the signing key", keyID)
+		}
+		publicKey, err := readPublicKey(file)
+		if err != nil {
+			return err
+		}
+		method, err := signingMethod(publicKey)
+		if err != nil {
+			return err
+		}
+		keys.verificationKeys[keyID] = verificationKey{method, publicKey}
+	}
+
+	if c.Key != "" && c.LegacyKeyID != "" {
+		if _, ok := keys.verificationKeys[c.LegacyKeyID]; ok {
+			return fmt.Errorf("legacy key id %s is the id of another key", c.LegacyKeyID)
+		}
+		keys.verificationKeys[c.LegacyKeyID] = verificationKey{jwt.SigningMethodHS256, []byte(c.Key)}
+	}
+
+	c.keys = keys
+	return nil
+}
+
+// JWKS returns the public verification keys, the legacy HS256 secret is never published.
+func (c Config) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{
+		Keys: []JSONWebKey{},
+	}
+	if c.keys == nil {
+		return set
+	}
+	for keyID, key := range c.keys.verificationKeys {
+		switch publicKey := key.key.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kid: keyID,
+				Kty: "RSA",
+				Alg: key.method.Alg(),
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JSONWebKey{
+				Kid: keyID,
+				Kty: "OKP",
+				Alg: key.method.Alg(),
+				Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+	return set
+}
+
+func signingMethod(publicKey crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %T", publicKey)
+	}
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", file, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid pem in key file %s", file)
+	}
+	return block, nil
+}
+
+func readPrivateKey(file string) (crypto.Signer, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", file, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key in %s", file)
+	}
+	return signer, nil
+}
+
+func readPublicKey(file string) (crypto.PublicKey, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", file, err)
+	}
+	return key, nil
+}
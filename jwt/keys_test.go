@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKeyPair writes a new Ed25519 private and public key in PEM files and returns their paths
+func writeKeyPair(t *testing.T, dir string, name string) (string, string) {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateFile := filepath.Join(dir, name+".pem")
+	publicFile := filepath.Join(dir, name+".pub.pem")
+	if err := os.WriteFile(privateFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return privateFile, publicFile
+}
+
+func TestLoadKeysKeyIDCollision(t *testing.T) {
+	dir := t.TempDir()
+	signingKeyFile, _ := writeKeyPair(t, dir, "current")
+	_, retiredKeyFile := writeKeyPair(t, dir, "retired")
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name: "distinct key ids",
+			cfg: Config{
+				SigningKeyFile:       signingKeyFile,
+				KeyID:                "current",
+				VerificationKeyFiles: map[string]string{"retired": retiredKeyFile},
+				Key:                  "legacy-secret",
+				LegacyKeyID:          "legacy",
+			},
+		},
+		{
+			name: "legacy key id of the signing key",
+			cfg: Config{
+				SigningKeyFile: signingKeyFile,
+				KeyID:          "current",
+				Key:            "legacy-secret",
+				LegacyKeyID:    "current",
+			},
+			wantErr: true,
+		},
+		{
+			name: "legacy key id of a verification key",
+			cfg: Config{
+				SigningKeyFile:       signingKeyFile,
+				KeyID:                "current",
+				VerificationKeyFiles: map[string]string{"retired": retiredKeyFile},
+				Key:                  "legacy-secret",
+				LegacyKeyID:          "retired",
+			},
+			wantErr: true,
+		},
+		{
+			name: "verification key id of the signing key",
+			cfg: Config{
+				SigningKeyFile:       signingKeyFile,
+				KeyID:                "current",
+				VerificationKeyFiles: map[string]string{"current": retiredKeyFile},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.LoadKeys()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: LoadKeys error %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
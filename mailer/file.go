@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type FileConfig struct {
+	Dir string `yaml:"dir" env:"MAILER_FILE_DIR" env-default:"./tmp/mails"`
+}
+
+// fileMailer writes every mail into a local directory instead of sending it.
+// It is meant for local development only.
+type fileMailer struct {
+	from string
+	cfg  FileConfig
+}
+
+func NewFileMailer(from string, cfg FileConfig) Mailer {
+	return &fileMailer{
+		from: from,
+		cfg:  cfg,
+	}
+}
+
+func (m *fileMailer) Send(ctx context.Context, mail Mail) error {
+	if err := os.MkdirAll(m.cfg.Dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Join(m.cfg.Dir, fmt.Sprintf("%d.eml", time.Now().UTC().UnixNano()))
+	if err := os.WriteFile(name, buildMessage(m.from, mail), 0o644); err != nil {
+		return err
+	}
+	log.Printf("mail to %s written to %s\n", mail.To, name)
+	return nil
+}
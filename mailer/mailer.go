@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	ProviderFile = "file"
+	ProviderSMTP = "smtp"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+type Config struct {
+	Provider string     `yaml:"provider" env:"MAILER_PROVIDER" env-default:"file"`
+	From     string     `yaml:"from" env:"MAILER_FROM" env-default:"no-reply@ketalk.local"`
+	File     FileConfig `yaml:"file"`
+	SMTP     SMTPConfig `yaml:"smtp"`
+}
+
+func NewMailer(cfg Config) (Mailer, error) {
+	switch cfg.Provider {
+	case ProviderFile:
+		return NewFileMailer(cfg.From, cfg.File), nil
+	case ProviderSMTP:
+		return NewSMTPMailer(cfg.From, cfg.SMTP), nil
+	default:
+		return nil, fmt.Errorf("unsupported mailer provider: %s", cfg.Provider)
+	}
+}
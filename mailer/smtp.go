@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/smtp"
+)
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"MAILER_SMTP_HOST" env-default:"localhost"`
+	Port     int    `yaml:"port" env:"MAILER_SMTP_PORT" env-default:"1025"`
+	Username string `yaml:"username" env:"MAILER_SMTP_USERNAME" env-default:""`
+	Password string `yaml:"password" env:"MAILER_SMTP_PASSWORD" env-default:""`
+}
+
+type smtpMailer struct {
+	from string
+	cfg  SMTPConfig
+}
+
+func NewSMTPMailer(from string, cfg SMTPConfig) Mailer {
+	return &smtpMailer{
+		from: from,
+		cfg:  cfg,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, mail Mail) error {
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := fmt.Sprintf("%s:%d", m.cfg.Host, m.cfg.Port)
+	return smtp.SendMail(addr, auth, m.from, []string{mail.To}, buildMessage(m.from, mail))
+}
+
+func buildMessage(from string, mail Mail) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mail.Subject)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(mail.Body)
+	return buf.Bytes()
+}
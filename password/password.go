@@ -0,0 +1,172 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"ketalk-api/common"
+	"strings"
+	"unicode"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Hashes are stored in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+// so parameters can be changed later without breaking existing hashes.
+const argon2idPrefix = "$argon2id$"
+
+const MinLength = 8
+
+var (
+	ErrInvalidHash      = fmt.Errorf("invalid password hash")
+	ErrPasswordTooShort = fmt.Errorf("%w: password must be at least %d characters long", common.ErrInvalidInput, MinLength)
+	ErrPasswordTooWeak  = fmt.Errorf("%w: password must contain both letters and digits", common.ErrInvalidInput)
+	ErrPasswordCommon   = fmt.Errorf("%w: password is too common", common.ErrInvalidInput)
+)
+
+type params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  uint32
+	keyLength   uint32
+}
+
+var defaultParams = params{
+	memory:      64 * 1024,
+	iterations:  3,
+	parallelism: 2,
+	saltLength:  16,
+	keyLength:   32,
+}
+
+// dummyHash is a hash with the default parameters of a password nobody has,
+// VerifyDummy compares against it so a failed login costs the same whether or not the account has a password
+const dummyHash = "$argon2id$v=19$m=65536,t=3,p=2$Bk9mAh1+yvEp8Hi9uoxY7Q$BtWFnNOIFJ6+hzwoJl3Vwd453fQWdaBHvbcReWPeAP4"
+
+var commonPasswords = map[string]struct{}{
+	"password":   {},
+	"password1":  {},
+	"12345678":   {},
+	"123456789":  {},
+	"1234567890": {},
+	"qwerty123":  {},
+	"qwertyuiop": {},
+	"11111111":   {},
+	"abc12345":   {},
+	"iloveyou1":  {},
+	"admin123":   {},
+	"letmein1":   {},
+}
+
+func Hash(plain string) (string, error) {
+	return hash(plain, defaultParams)
+}
+
+func hash(plain string, p params) (string, error) {
+	salt := make([]byte, p.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, p.iterations, p.memory, p.parallelism, p.keyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		p.memory,
+		p.iterations,
+		p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify compares plain against the stored value in constant time.
+// Values that are not argon2id hashes are treated as legacy plaintext passwords.
+// needsRehash reports whether the stored value should be replaced with a fresh hash.
+func Verify(plain string, stored string) (ok bool, needsRehash bool, err error) {
+	if !IsHashed(stored) {
+		ok = subtle.ConstantTimeCompare([]byte(plain), []byte(stored)) == 1
+		return ok, ok, nil
+	}
+
+	p, salt, key, err := decode(stored)
+	if err != nil {
+		return false, false, err
+	}
+	otherKey := argon2.IDKey([]byte(plain), salt, p.iterations, p.memory, p.parallelism, p.keyLength)
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return false, false, nil
+	}
+	needsRehash = p.memory != defaultParams.memory ||
+		p.iterations != defaultParams.iterations ||
+		p.parallelism != defaultParams.parallelism ||
+		p.keyLength != defaultParams.keyLength
+	return true, needsRehash, nil
+}
+
+// VerifyDummy does the work of Verify for an account without a password, it never matches.
+func VerifyDummy(plain string) {
+	Verify(plain, dummyHash)
+}
+
+func IsHashed(stored string) bool {
+	return strings.HasPrefix(stored, argon2idPrefix)
+}
+
+// Validate rejects passwords that are too weak to be used for a new account.
+func Validate(plain string) error {
+	if len([]rune(plain)) < MinLength {
+		return ErrPasswordTooShort
+	}
+	if _, ok := commonPasswords[strings.ToLower(plain)]; ok {
+		return ErrPasswordCommon
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range plain {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		} else if unicode.IsDigit(r) {
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return ErrPasswordTooWeak
+	}
+	return nil
+}
+
+func decode(stored string) (*params, []byte, []byte, error) {
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2 version: %d", version)
+	}
+
+	var p params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.Strict().DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.saltLength = uint32(len(salt))
+
+	key, err := base64.RawStdEncoding.Strict().DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+	p.keyLength = uint32(len(key))
+
+	return &p, salt, key, nil
+}
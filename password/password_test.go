@@ -0,0 +1,106 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHashParams(t *testing.T) {
+	stored, err := Hash("correct horse 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored, "$argon2id$v=19$m=65536,t=3,p=2$") {
+		t.Fatalf("unexpected hash format: %s", stored)
+	}
+	p, salt, key, err := decode(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *p != defaultParams {
+		t.Errorf("params = %+v, want %+v", *p, defaultParams)
+	}
+	if len(salt) != int(defaultParams.saltLength) || len(key) != int(defaultParams.keyLength) {
+		t.Errorf("salt length = %d, key length = %d", len(salt), len(key))
+	}
+
+	other, err := Hash("correct horse 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == stored {
+		t.Error("hashes of the same password share a salt")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	current, err := Hash("correct horse 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	weaker, err := hash("correct horse 1", params{memory: 19 * 1024, iterations: 2, parallelism: 1, saltLength: 16, keyLength: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		plain           string
+		stored          string
+		wantOk          bool
+		wantNeedsRehash bool
+		wantErr         error
+	}{
+		{name: "current params", plain: "correct horse 1", stored: current, wantOk: true},
+		{name: "wrong password", plain: "wrong horse 1", stored: current},
+		{name: "weaker params are rehashed", plain: "correct horse 1", stored: weaker, wantOk: true, wantNeedsRehash: true},
+		{name: "wrong password with weaker params", plain: "wrong horse 1", stored: weaker},
+		{name: "legacy plaintext is rehashed", plain: "legacy123", stored: "legacy123", wantOk: true, wantNeedsRehash: true},
+		{name: "wrong legacy plaintext", plain: "legacy124", stored: "legacy123"},
+		{name: "broken hash", plain: "correct horse 1", stored: "$argon2id$v=19$m=65536,t=3,p=2$salt", wantErr: ErrInvalidHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := Verify(tt.plain, tt.stored)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOk || needsRehash != tt.wantNeedsRehash {
+				t.Errorf("ok = %v, needsRehash = %v, want %v, %v", ok, needsRehash, tt.wantOk, tt.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		plain string
+		want  error
+	}{
+		{"abc123", ErrPasswordTooShort},
+		{"Password1", ErrPasswordCommon},
+		{"onlyletters", ErrPasswordTooWeak},
+		{"1234567890123", ErrPasswordTooWeak},
+		{"correct horse 1", nil},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.plain); !errors.Is(err, tt.want) {
+			t.Errorf("Validate(%q) = %v, want %v", tt.plain, err, tt.want)
+		}
+	}
+}
+
+// TestDummyHash keeps the dummy hash as slow as the hashes of real passwords
+func TestDummyHash(t *testing.T) {
+	p, _, _, err := decode(dummyHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *p != defaultParams {
+		t.Errorf("dummy hash params = %+v, want %+v", *p, defaultParams)
+	}
+	if ok, _, _ := Verify("ketalk-dummy-password1", dummyHash); ok {
+		t.Error("dummy hash matched a password")
+	}
+}
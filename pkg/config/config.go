@@ -2,18 +2,31 @@ package config
 
 import (
 	"ketalk-api/jwt"
+	"ketalk-api/mailer"
 	conn_redis "ketalk-api/pkg/manager/conversation/redis"
 	"ketalk-api/pkg/manager/conversation/ws"
+	moderation_manager "ketalk-api/pkg/manager/moderation"
+	user_manager "ketalk-api/pkg/manager/user"
+	"ketalk-api/pkg/provider/apple"
 	"ketalk-api/pkg/provider/google"
+	"ketalk-api/pkg/provider/outlook"
+	"ketalk-api/sms"
 	"ketalk-api/storage"
 )
 
 type Config struct {
 	Google           google.Config                  `yaml:"google"`
+	Outlook          outlook.Config                 `yaml:"outlook"`
+	Apple            apple.Config                   `yaml:"apple"`
 	Auth             jwt.Config                     `yaml:"auth"`
 	AzureBlobStorage storage.AzureBlobStorageConfig `yaml:"azure"`
 	R2Storage        storage.R2CloudFlareConfig     `yaml:"r2"`
 	DB               Postgres                       `yaml:"db"`
 	Redis            conn_redis.Config              `yaml:"redis"`
 	WebSocketServer  ws.Config                      `yaml:"ws"`
+	Mailer           mailer.Config                  `yaml:"mailer"`
+	Sms              sms.Config                     `yaml:"sms"`
+	UserExport       user_manager.ExportConfig      `yaml:"userExport"`
+	UserGeofence     user_manager.GeofenceConfig    `yaml:"userGeofence"`
+	Moderation       moderation_manager.Config      `yaml:"moderation"`
 }
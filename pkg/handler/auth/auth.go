@@ -34,10 +34,34 @@ func NewHttpHandler(ctx context.Context, h AuthHandler, middleware common.Middle
 func (c *HttpHandler) Init(ctx context.Context, router *gin.Engine) {
 	routes := map[string]map[string]common.HandlerFunc{
 		"POST": {
-			"/signup-or-login": c.SignupOrLogin,
+			"/signup-or-login":     c.SignupOrLogin,
+			"/signup":              c.Signup,
+			"/login":               c.Login,
+			"/refresh":             c.RefreshAccessToken,
+			"/verify-email":        c.middleware.HandlerWithAuth(c.VerifyEmail),
+			"/verify-email/resend": c.middleware.HandlerWithAuth(c.ResendVerificationEmail),
+			"/phone/otp":           c.RequestPhoneOtp,
+			"/phone/verify":        c.VerifyPhoneOtp,
+			"/phone/link":          c.middleware.HandlerWithAuth(c.LinkPhone),
+			"/identities":          c.middleware.HandlerWithAuth(c.LinkIdentity),
+			"/2fa/enroll":          c.middleware.HandlerWithAuth(c.EnrollTotp),
+			"/2fa/confirm":         c.middleware.HandlerWithAuth(c.ConfirmTotp),
+			"/2fa/backup-codes":    c.middleware.HandlerWithAuth(c.RegenerateBackupCodes),
+			"/2fa/verify":          c.VerifyMfaChallenge,
+		},
+		"GET": {
+			"/sessions":   c.middleware.HandlerWithAuth(c.GetSessions),
+			"/lockouts":   c.middleware.HandlerWithRole(common.RoleAdmin, c.GetLoginLockouts),
+			"/identities": c.middleware.HandlerWithAuth(c.GetIdentities),
+			"/2fa":        c.middleware.HandlerWithAuth(c.GetTotpStatus),
 		},
 		"DELETE": {
-			"/logout": c.middleware.HandlerWithAuth(c.Logout),
+			"/logout":               c.middleware.HandlerWithAuth(c.Logout),
+			"/logout/everywhere":    c.middleware.HandlerWithAuth(c.LogoutEverywhere),
+			"/sessions/others":      c.middleware.HandlerWithAuth(c.RevokeOtherSessions),
+			"/sessions/:deviceId":   c.middleware.HandlerWithAuth(c.RevokeSession),
+			"/identities/:provider": c.middleware.HandlerWithAuth(c.UnlinkIdentity),
+			"/2fa":                  c.middleware.HandlerWithAuth(c.DisableTotp),
 		},
 	}
 	for method, route := range routes {
@@ -45,5 +69,7 @@ func (c *HttpHandler) Init(ctx context.Context, router *gin.Engine) {
 			router.Handle(method, fmt.Sprintf("/auth%s", r), common.GenericHandler(h))
 		}
 	}
+	// served as is instead of the generic response, so standard jwt libraries can consume it
+	router.GET("/.well-known/jwks.json", c.JWKS)
 	fmt.Println("initialized auth handler")
 }
@@ -0,0 +1,94 @@
+package auth_handler
+
+import (
+	"ketalk-api/common"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Identity struct {
+	Provider string    `json:"provider"`
+	Email    string    `json:"email"`
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+type GetIdentitiesResponse struct {
+	HasPassword bool       `json:"hasPassword"`
+	Phone       *string    `json:"phone"`
+	Identities  []Identity `json:"identities"`
+}
+
+type LinkIdentityRequest struct {
+	ProviderToken *ProviderToken `json:"providerToken"`
+}
+
+func (h *HttpHandler) GetIdentities(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.GetIdentities(ctx)
+}
+
+func (h *HttpHandler) LinkIdentity(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req LinkIdentityRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := h.handler.LinkIdentity(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *HttpHandler) UnlinkIdentity(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	if err := h.handler.UnlinkIdentity(ctx, ctx.Param("provider")); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *handler) GetIdentities(ctx *gin.Context) (*GetIdentitiesResponse, error) {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.service.GetIdentities(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	identities := make([]Identity, len(resp.Identities))
+	for i, identity := range resp.Identities {
+		identities[i] = Identity{
+			Provider: identity.Provider,
+			Email:    identity.Email,
+			LinkedAt: identity.LinkedAt,
+		}
+	}
+	return &GetIdentitiesResponse{
+		HasPassword: resp.HasPassword,
+		Phone:       resp.Phone,
+		Identities:  identities,
+	}, nil
+}
+
+func (h *handler) LinkIdentity(ctx *gin.Context, req LinkIdentityRequest) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	manReq := auth_manager.LinkIdentityRequest{
+		UserID: userId,
+	}
+	if req.ProviderToken != nil {
+		manReq.ProviderToken = toManagerProviderToken(req.ProviderToken)
+	}
+	return h.service.LinkIdentity(ctx, manReq)
+}
+
+func (h *handler) UnlinkIdentity(ctx *gin.Context, provider string) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.service.UnlinkIdentity(ctx, userId, provider)
+}
@@ -0,0 +1,17 @@
+package auth_handler
+
+import (
+	"ketalk-api/jwt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *HttpHandler) JWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.handler.JWKS(ctx))
+}
+
+func (h *handler) JWKS(ctx *gin.Context) jwt.JSONWebKeySet {
+	return h.service.JWKS()
+}
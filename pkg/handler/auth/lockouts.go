@@ -0,0 +1,68 @@
+package auth_handler
+
+import (
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LoginLockout struct {
+	ID          uuid.UUID `json:"id"`
+	Scope       string    `json:"scope"`
+	Value       string    `json:"value"`
+	Failures    int64     `json:"failures"`
+	LockedAt    time.Time `json:"lockedAt"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+type GetLoginLockoutsResponse struct {
+	Lockouts []LoginLockout `json:"lockouts"`
+}
+
+func (h *HttpHandler) GetLoginLockouts(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.GetLoginLockouts(ctx)
+}
+
+// GetLoginLockouts reads the optional scope, value, since (RFC 3339) and limit query parameters
+func (h *handler) GetLoginLockouts(ctx *gin.Context) (*GetLoginLockoutsResponse, error) {
+	req := auth_manager.GetLoginLockoutsRequest{
+		Scope: ctx.Query("scope"),
+		Value: ctx.Query("value"),
+	}
+	if since := ctx.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, err
+		}
+		req.Since = &t
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, err
+		}
+		req.Limit = l
+	}
+	lockouts, err := h.service.GetLoginLockouts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]LoginLockout, len(lockouts))
+	for i, lockout := range lockouts {
+		resp[i] = LoginLockout{
+			ID:          lockout.ID,
+			Scope:       lockout.Scope,
+			Value:       lockout.Value,
+			Failures:    lockout.Failures,
+			LockedAt:    lockout.LockedAt,
+			LockedUntil: lockout.LockedUntil,
+		}
+	}
+	return &GetLoginLockoutsResponse{
+		Lockouts: resp,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package auth_handler
+
+import (
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	DeviceID string `json:"deviceId"`
+	DeviceOS string `json:"deviceOs"`
+}
+
+func (h *HttpHandler) Login(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req LoginRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	resp, err := h.handler.Login(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (h *handler) Login(ctx *gin.Context, req LoginRequest) (*SignupOrLoginResponse, error) {
+	resp, err := h.service.Login(ctx, auth_manager.LoginRequest{
+		Email:    req.Email,
+		Password: req.Password,
+		DeviceID: req.DeviceID,
+		DeviceOS: req.DeviceOS,
+		IP:       ctx.ClientIP(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toSignupOrLoginResponse(resp), nil
+}
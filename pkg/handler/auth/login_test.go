@@ -0,0 +1,73 @@
+package auth_handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"ketalk-api/common"
+	"ketalk-api/password"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testPassword = "correct-horse-42"
+
+// fakeAuthManager fails like the real manager does for the checks these tests cover
+type fakeAuthManager struct {
+	auth_manager.AuthManager
+}
+
+func (m *fakeAuthManager) Login(ctx context.Context, req auth_manager.LoginRequest) (*auth_manager.SignupOrLoginResponse, error) {
+	if req.Password != testPassword {
+		return nil, auth_manager.ErrInvalidCredentials
+	}
+	return &auth_manager.SignupOrLoginResponse{Email: req.Email}, nil
+}
+
+func (m *fakeAuthManager) Signup(ctx context.Context, req auth_manager.SignupRequest) (*auth_manager.SignupOrLoginResponse, error) {
+	if err := password.Validate(req.Password); err != nil {
+		return nil, err
+	}
+	return &auth_manager.SignupOrLoginResponse{Email: req.Email}, nil
+}
+
+func TestCredentialErrorStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHttpHandler(context.Background(), NewHandler(&fakeAuthManager{}), nil)
+	router := gin.New()
+	router.POST("/auth/login", common.GenericHandler(h.Login))
+	router.POST("/auth/signup", common.GenericHandler(h.Signup))
+
+	tests := []struct {
+		name string
+		path string
+		body interface{}
+		want int
+	}{
+		{"login", "/auth/login", LoginRequest{Email: "user@example.com", Password: testPassword}, http.StatusOK},
+		{"wrong password", "/auth/login", LoginRequest{Email: "user@example.com", Password: "wrong-password-1"}, http.StatusUnauthorized},
+		{"signup", "/auth/signup", SignupRequest{Email: "user@example.com", Password: testPassword}, http.StatusOK},
+		{"short password", "/auth/signup", SignupRequest{Email: "user@example.com", Password: "abc1"}, http.StatusBadRequest},
+		{"password without digits", "/auth/signup", SignupRequest{Email: "user@example.com", Password: "onlyletters"}, http.StatusBadRequest},
+		{"common password", "/auth/signup", SignupRequest{Email: "user@example.com", Password: "password1"}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		body, err := json.Marshal(tt.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("latitude", "41.2995")
+		req.Header.Set("longitude", "69.2401")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != tt.want {
+			t.Errorf("%s: status %d, want %d, body %s", tt.name, recorder.Code, tt.want, recorder.Body.String())
+		}
+	}
+}
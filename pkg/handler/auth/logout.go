@@ -2,6 +2,7 @@ package auth_handler
 
 import (
 	"ketalk-api/common"
+	"ketalk-api/jwt"
 	auth_manager "ketalk-api/pkg/manager/auth"
 	"net/http"
 
@@ -23,14 +24,38 @@ func (h *HttpHandler) Logout(ctx *gin.Context, r *http.Request) (interface{}, er
 	return nil, nil
 }
 
+func (h *HttpHandler) LogoutEverywhere(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	if err := h.handler.LogoutEverywhere(ctx); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 func (h *handler) Logout(ctx *gin.Context, logoutReq LogoutRequest) error {
 	userId, err := common.GetUserId(ctx.Request.Context())
 	if err != nil {
 		return err
 	}
+	tokenData, err := jwt.GetJWTToken(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
 
-	return h.service.Logout(ctx, auth_manager.LogoutRequest{
-		RefreshToken: logoutReq.RefreshToken,
-		UserID:       userId,
-	})
+	manReq := auth_manager.LogoutRequest{
+		RefreshToken:  logoutReq.RefreshToken,
+		UserID:        userId,
+		AccessTokenID: tokenData.ID,
+	}
+	if tokenData.ExpiresAt != nil {
+		manReq.AccessTokenExpiresAt = tokenData.ExpiresAt.Time
+	}
+	return h.service.Logout(ctx, manReq)
+}
+
+func (h *handler) LogoutEverywhere(ctx *gin.Context) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.service.LogoutEverywhere(ctx, userId)
 }
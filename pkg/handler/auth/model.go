@@ -1,11 +1,35 @@
 package auth_handler
 
 import (
+	"ketalk-api/jwt"
+
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler interface {
 	SignupOrLogin(ctx *gin.Context, req SignupOrLoginRequest) (*SignupOrLoginResponse, error)
+	Signup(ctx *gin.Context, req SignupRequest) (*SignupOrLoginResponse, error)
+	Login(ctx *gin.Context, req LoginRequest) (*SignupOrLoginResponse, error)
+	VerifyEmail(ctx *gin.Context, req VerifyEmailRequest) error
+	ResendVerificationEmail(ctx *gin.Context) error
+	RequestPhoneOtp(ctx *gin.Context, req RequestPhoneOtpRequest) error
+	VerifyPhoneOtp(ctx *gin.Context, req VerifyPhoneOtpRequest) (*SignupOrLoginResponse, error)
+	LinkPhone(ctx *gin.Context, req LinkPhoneRequest) error
 	RefreshAccessToken(ctx *gin.Context, req RefreshAccessTokenRequest) (*RefreshAccessTokenResponse, error)
 	Logout(ctx *gin.Context, logoutReq LogoutRequest) error
+	LogoutEverywhere(ctx *gin.Context) error
+	GetSessions(ctx *gin.Context) (*GetSessionsResponse, error)
+	RevokeSession(ctx *gin.Context, deviceId string) error
+	RevokeOtherSessions(ctx *gin.Context) error
+	JWKS(ctx *gin.Context) jwt.JSONWebKeySet
+	GetLoginLockouts(ctx *gin.Context) (*GetLoginLockoutsResponse, error)
+	GetIdentities(ctx *gin.Context) (*GetIdentitiesResponse, error)
+	LinkIdentity(ctx *gin.Context, req LinkIdentityRequest) error
+	UnlinkIdentity(ctx *gin.Context, provider string) error
+	GetTotpStatus(ctx *gin.Context) (*TotpStatusResponse, error)
+	EnrollTotp(ctx *gin.Context) (*EnrollTotpResponse, error)
+	ConfirmTotp(ctx *gin.Context, req TotpCodeRequest) (*BackupCodesResponse, error)
+	RegenerateBackupCodes(ctx *gin.Context, req TotpCodeRequest) (*BackupCodesResponse, error)
+	DisableTotp(ctx *gin.Context, req TotpCodeRequest) error
+	VerifyMfaChallenge(ctx *gin.Context, req VerifyMfaChallengeRequest) (*SignupOrLoginResponse, error)
 }
@@ -0,0 +1,97 @@
+package auth_handler
+
+import (
+	"ketalk-api/common"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RequestPhoneOtpRequest struct {
+	Phone string `json:"phone"`
+}
+
+type VerifyPhoneOtpRequest struct {
+	Phone    string `json:"phone"`
+	Code     string `json:"code"`
+	UserName string `json:"userName"`
+	DeviceID string `json:"deviceId"`
+	DeviceOS string `json:"deviceOs"`
+}
+
+type LinkPhoneRequest struct {
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
+
+func (h *HttpHandler) RequestPhoneOtp(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req RequestPhoneOtpRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := h.handler.RequestPhoneOtp(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *HttpHandler) VerifyPhoneOtp(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req VerifyPhoneOtpRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	resp, err := h.handler.VerifyPhoneOtp(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (h *HttpHandler) LinkPhone(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req LinkPhoneRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := h.handler.LinkPhone(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *handler) RequestPhoneOtp(ctx *gin.Context, req RequestPhoneOtpRequest) error {
+	return h.service.RequestPhoneOtp(ctx, auth_manager.RequestPhoneOtpRequest{
+		Phone: req.Phone,
+	})
+}
+
+func (h *handler) VerifyPhoneOtp(ctx *gin.Context, req VerifyPhoneOtpRequest) (*SignupOrLoginResponse, error) {
+	// location is only needed to register new numbers
+	location, _ := common.GetLocation(ctx.Request)
+	manResp, err := h.service.VerifyPhoneOtp(ctx, auth_manager.VerifyPhoneOtpRequest{
+		Phone:    req.Phone,
+		Code:     req.Code,
+		UserName: req.UserName,
+		DeviceID: req.DeviceID,
+		DeviceOS: req.DeviceOS,
+		IP:       ctx.ClientIP(),
+		Location: location,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toSignupOrLoginResponse(manResp), nil
+}
+
+func (h *handler) LinkPhone(ctx *gin.Context, req LinkPhoneRequest) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.service.LinkPhone(ctx, auth_manager.LinkPhoneRequest{
+		UserID: userId,
+		Phone:  req.Phone,
+		Code:   req.Code,
+		IP:     ctx.ClientIP(),
+	})
+}
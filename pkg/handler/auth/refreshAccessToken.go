@@ -1,6 +1,7 @@
 package auth_handler
 
 import (
+	auth_manager "ketalk-api/pkg/manager/auth"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -8,6 +9,7 @@ import (
 
 type RefreshAccessTokenRequest struct {
 	RefreshToken string `json:"refreshToken"`
+	DeviceID     string `json:"deviceId"`
 }
 
 type RefreshAccessTokenResponse struct {
@@ -28,5 +30,15 @@ func (h *HttpHandler) RefreshAccessToken(ctx *gin.Context, r *http.Request) (int
 }
 
 func (h *handler) RefreshAccessToken(ctx *gin.Context, req RefreshAccessTokenRequest) (*RefreshAccessTokenResponse, error) {
-	return nil, nil
+	resp, err := h.service.RefreshAccessToken(ctx, auth_manager.RefreshAccessTokenRequest{
+		RefreshToken: req.RefreshToken,
+		DeviceID:     req.DeviceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RefreshAccessTokenResponse{
+		AuthToken:    resp.AuthToken,
+		RefreshToken: resp.RefreshToken,
+	}, nil
 }
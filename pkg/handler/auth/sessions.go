@@ -0,0 +1,90 @@
+package auth_handler
+
+import (
+	"ketalk-api/common"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Session struct {
+	DeviceID   string    `json:"deviceId"`
+	DeviceOS   string    `json:"deviceOs"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	Current    bool      `json:"current"`
+}
+
+type GetSessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+func (h *HttpHandler) GetSessions(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.GetSessions(ctx)
+}
+
+func (h *HttpHandler) RevokeSession(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	if err := h.handler.RevokeSession(ctx, ctx.Param("deviceId")); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *HttpHandler) RevokeOtherSessions(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	if err := h.handler.RevokeOtherSessions(ctx); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *handler) GetSessions(ctx *gin.Context) (*GetSessionsResponse, error) {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	// tokens issued before sessions were tracked have no device, no session is marked as current then
+	deviceId, _ := common.GetDeviceId(ctx.Request.Context())
+
+	sessions, err := h.service.GetSessions(ctx, auth_manager.GetSessionsRequest{
+		UserID:          userId,
+		CurrentDeviceID: deviceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := GetSessionsResponse{
+		Sessions: make([]Session, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, Session{
+			DeviceID:   session.DeviceID,
+			DeviceOS:   session.DeviceOS,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			Current:    session.Current,
+		})
+	}
+	return &resp, nil
+}
+
+func (h *handler) RevokeSession(ctx *gin.Context, deviceId string) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.service.RevokeSession(ctx, userId, deviceId)
+}
+
+func (h *handler) RevokeOtherSessions(ctx *gin.Context) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	deviceId, err := common.GetDeviceId(ctx.Request.Context())
+	if err != nil {
+		return auth_manager.ErrUnknownSession
+	}
+	return h.service.RevokeOtherSessions(ctx, userId, deviceId)
+}
@@ -3,7 +3,10 @@ package auth_handler
 import (
 	"ketalk-api/common"
 	auth_manager "ketalk-api/pkg/manager/auth"
+	"ketalk-api/pkg/provider/model"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,23 +18,29 @@ type GoogleToken struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
-type ProviderToken struct {
-	GoogleToken *GoogleToken `json:"googleToken"`
+type OutlookToken struct {
+	IdToken      string `json:"idToken"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// AppleToken carries the name apple hands to the app, it is only sent on the first login
+type AppleToken struct {
+	IdToken   string `json:"idToken"`
+	Nonce     string `json:"nonce"`
+	FirstName string `json:"firstName"`
 }
 
-type SignUpDetails struct {
-	UserName string `json:"userName"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+type ProviderToken struct {
+	GoogleToken  *GoogleToken  `json:"googleToken"`
+	OutlookToken *OutlookToken `json:"outlookToken"`
+	AppleToken   *AppleToken   `json:"appleToken"`
 }
 
 type SignupOrLoginRequest struct {
 	ProviderToken *ProviderToken `json:"providerToken"`
 	DeviceID      string         `json:"deviceId"`
 	DeviceOS      string         `json:"deviceOs"`
-
-	// TODO: will be removed once SSO integrated
-	SignUpDetails *SignUpDetails `json:"signUpDetails"`
 }
 
 type SignupOrLoginResponse struct {
@@ -39,8 +48,13 @@ type SignupOrLoginResponse struct {
 	UserName     string    `json:"userName"`
 	Email        string    `json:"email"`
 	Image        *string   `json:"image"`
+	Verified     bool      `json:"verified"`
 	AuthToken    string    `json:"authToken"`
 	RefreshToken string    `json:"refreshToken"`
+	// MfaChallenge is sent instead of the tokens when the user has 2FA enabled,
+	// it is exchanged for them at /auth/2fa/verify with a code
+	MfaChallenge          string     `json:"mfaChallenge,omitempty"`
+	MfaChallengeExpiresAt *time.Time `json:"mfaChallengeExpiresAt,omitempty"`
 }
 
 func (h *HttpHandler) SignupOrLogin(ctx *gin.Context, r *http.Request) (interface{}, error) {
@@ -64,36 +78,61 @@ func (h *handler) SignupOrLogin(ctx *gin.Context, req SignupOrLoginRequest) (*Si
 		DeviceID: req.DeviceID,
 		DeviceOS: req.DeviceOS,
 		Location: *location,
+		IP:       ctx.ClientIP(),
 	}
 
 	if req.ProviderToken != nil {
-		manReq.ProviderToken = &auth_manager.ProviderToken{}
-		if req.ProviderToken.GoogleToken != nil {
-			manReq.ProviderToken.GoogleToken = &auth_manager.GoogleToken{
-				IdToken:      req.ProviderToken.GoogleToken.IdToken,
-				AccessToken:  req.ProviderToken.GoogleToken.AccessToken,
-				RefreshToken: req.ProviderToken.GoogleToken.RefreshToken,
-			}
-		}
+		manReq.ProviderToken = toManagerProviderToken(req.ProviderToken)
 	}
-	if req.SignUpDetails != nil {
-		manReq.SignUpDetails = &auth_manager.SignUpDetails{
-			UserName: req.SignUpDetails.UserName,
-			Email:    req.SignUpDetails.Email,
-			Password: req.SignUpDetails.Password,
-		}
-	}
-
 	manResp, err := h.service.SignupOrLogin(ctx, manReq)
 	if err != nil {
 		return nil, err
 	}
+	return toSignupOrLoginResponse(manResp), nil
+}
+
+func toManagerProviderToken(token *ProviderToken) *auth_manager.ProviderToken {
+	switch {
+	case token.GoogleToken != nil:
+		return &auth_manager.ProviderToken{
+			ProviderName: model.ProviderNameGoogle,
+			IdToken:      token.GoogleToken.IdToken,
+			AccessToken:  token.GoogleToken.AccessToken,
+			RefreshToken: token.GoogleToken.RefreshToken,
+		}
+	case token.OutlookToken != nil:
+		return &auth_manager.ProviderToken{
+			ProviderName: model.ProviderNameOutlook,
+			IdToken:      token.OutlookToken.IdToken,
+			AccessToken:  token.OutlookToken.AccessToken,
+			RefreshToken: token.OutlookToken.RefreshToken,
+		}
+	case token.AppleToken != nil:
+		return &auth_manager.ProviderToken{
+			ProviderName: model.ProviderNameApple,
+			IdToken:      token.AppleToken.IdToken,
+			Nonce:        token.AppleToken.Nonce,
+			FirstName:    strings.TrimSpace(token.AppleToken.FirstName),
+		}
+	default:
+		return nil
+	}
+}
+
+func toSignupOrLoginResponse(resp *auth_manager.SignupOrLoginResponse) *SignupOrLoginResponse {
+	if resp.MfaChallenge != "" {
+		return &SignupOrLoginResponse{
+			MfaChallenge:          resp.MfaChallenge,
+			MfaChallengeExpiresAt: &resp.MfaChallengeExpiresAt,
+		}
+	}
 	return &SignupOrLoginResponse{
-		Id:           manResp.Id,
-		UserName:     manResp.UserName,
-		Email:        manResp.Email,
-		Image:        manResp.Image,
-		AuthToken:    manResp.AuthToken,
-		RefreshToken: manResp.RefreshToken,
-	}, nil
+		Id:           resp.Id,
+		UserName:     resp.UserName,
+		Email:        resp.Email,
+		Image:        resp.Image,
+		Verified:     resp.Verified,
+		AuthToken:    resp.AuthToken,
+		RefreshToken: resp.RefreshToken,
+	}
 }
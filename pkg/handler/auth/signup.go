@@ -0,0 +1,48 @@
+package auth_handler
+
+import (
+	"ketalk-api/common"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SignupRequest struct {
+	UserName string `json:"userName"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	DeviceID string `json:"deviceId"`
+	DeviceOS string `json:"deviceOs"`
+}
+
+func (h *HttpHandler) Signup(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req SignupRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	resp, err := h.handler.Signup(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (h *handler) Signup(ctx *gin.Context, req SignupRequest) (*SignupOrLoginResponse, error) {
+	location, err := common.GetLocation(ctx.Request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.service.Signup(ctx, auth_manager.SignupRequest{
+		UserName: req.UserName,
+		Email:    req.Email,
+		Password: req.Password,
+		DeviceID: req.DeviceID,
+		DeviceOS: req.DeviceOS,
+		Location: *location,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toSignupOrLoginResponse(resp), nil
+}
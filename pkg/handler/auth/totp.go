@@ -0,0 +1,162 @@
+package auth_handler
+
+import (
+	"ketalk-api/common"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TotpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type EnrollTotpResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type BackupCodesResponse struct {
+	BackupCodes []string `json:"backupCodes"`
+}
+
+type TotpStatusResponse struct {
+	Enabled              bool  `json:"enabled"`
+	RemainingBackupCodes int64 `json:"remainingBackupCodes"`
+}
+
+type VerifyMfaChallengeRequest struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+func (h *HttpHandler) GetTotpStatus(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.GetTotpStatus(ctx)
+}
+
+func (h *HttpHandler) EnrollTotp(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.EnrollTotp(ctx)
+}
+
+func (h *HttpHandler) ConfirmTotp(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req TotpCodeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	return h.handler.ConfirmTotp(ctx, req)
+}
+
+func (h *HttpHandler) RegenerateBackupCodes(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req TotpCodeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	return h.handler.RegenerateBackupCodes(ctx, req)
+}
+
+func (h *HttpHandler) DisableTotp(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req TotpCodeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := h.handler.DisableTotp(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *HttpHandler) VerifyMfaChallenge(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req VerifyMfaChallengeRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	return h.handler.VerifyMfaChallenge(ctx, req)
+}
+
+func (h *handler) GetTotpStatus(ctx *gin.Context) (*TotpStatusResponse, error) {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	status, err := h.service.GetTotpStatus(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &TotpStatusResponse{
+		Enabled:              status.Enabled,
+		RemainingBackupCodes: status.RemainingBackupCodes,
+	}, nil
+}
+
+func (h *handler) EnrollTotp(ctx *gin.Context) (*EnrollTotpResponse, error) {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.service.EnrollTotp(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return &EnrollTotpResponse{
+		Secret:          resp.Secret,
+		ProvisioningURI: resp.ProvisioningURI,
+	}, nil
+}
+
+func (h *handler) ConfirmTotp(ctx *gin.Context, req TotpCodeRequest) (*BackupCodesResponse, error) {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.service.ConfirmTotp(ctx, auth_manager.TotpCodeRequest{
+		UserID: userId,
+		Code:   req.Code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BackupCodesResponse{
+		BackupCodes: resp.BackupCodes,
+	}, nil
+}
+
+func (h *handler) RegenerateBackupCodes(ctx *gin.Context, req TotpCodeRequest) (*BackupCodesResponse, error) {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.service.RegenerateBackupCodes(ctx, auth_manager.TotpCodeRequest{
+		UserID: userId,
+		Code:   req.Code,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BackupCodesResponse{
+		BackupCodes: resp.BackupCodes,
+	}, nil
+}
+
+func (h *handler) DisableTotp(ctx *gin.Context, req TotpCodeRequest) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.service.DisableTotp(ctx, auth_manager.TotpCodeRequest{
+		UserID: userId,
+		Code:   req.Code,
+	})
+}
+
+func (h *handler) VerifyMfaChallenge(ctx *gin.Context, req VerifyMfaChallengeRequest) (*SignupOrLoginResponse, error) {
+	resp, err := h.service.VerifyMfaChallenge(ctx, auth_manager.VerifyMfaChallengeRequest{
+		Challenge: req.Challenge,
+		Code:      req.Code,
+		IP:        ctx.ClientIP(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toSignupOrLoginResponse(resp), nil
+}
@@ -0,0 +1,51 @@
+package auth_handler
+
+import (
+	"ketalk-api/common"
+	auth_manager "ketalk-api/pkg/manager/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VerifyEmailRequest struct {
+	Code string `json:"code"`
+}
+
+func (h *HttpHandler) VerifyEmail(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req VerifyEmailRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := h.handler.VerifyEmail(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *HttpHandler) ResendVerificationEmail(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	if err := h.handler.ResendVerificationEmail(ctx); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *handler) VerifyEmail(ctx *gin.Context, req VerifyEmailRequest) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.service.VerifyEmail(ctx, auth_manager.VerifyEmailRequest{
+		UserID: userId,
+		Code:   req.Code,
+		IP:     ctx.ClientIP(),
+	})
+}
+
+func (h *handler) ResendVerificationEmail(ctx *gin.Context) error {
+	userId, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.service.ResendVerificationEmail(ctx, userId)
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"ketalk-api/common"
+	"ketalk-api/mailer"
 	"ketalk-api/pkg/config"
 	auth_handler "ketalk-api/pkg/handler/auth"
 	conversation_handler "ketalk-api/pkg/handler/conversation"
 	item_handler "ketalk-api/pkg/handler/item"
+	moderation_handler "ketalk-api/pkg/handler/moderation"
+	review_handler "ketalk-api/pkg/handler/review"
 	user_handler "ketalk-api/pkg/handler/user"
 	auth_manager "ketalk-api/pkg/manager/auth"
+	auth_redis "ketalk-api/pkg/manager/auth/redis"
 	auth_repo "ketalk-api/pkg/manager/auth/repository"
 	conversation_manager "ketalk-api/pkg/manager/conversation"
 	conn_redis "ketalk-api/pkg/manager/conversation/redis"
@@ -25,34 +29,46 @@ import (
 	"log"
 
 	"ketalk-api/pkg/manager/middleware"
+	moderation_manager "ketalk-api/pkg/manager/moderation"
+	moderation_repo "ketalk-api/pkg/manager/moderation/repository"
+	review_manager "ketalk-api/pkg/manager/review"
+	review_repo "ketalk-api/pkg/manager/review/repository"
 
 	user_manager "ketalk-api/pkg/manager/user"
+	user_redis "ketalk-api/pkg/manager/user/redis"
 	user_repo "ketalk-api/pkg/manager/user/repository"
 	"ketalk-api/storage"
 
 	"ketalk-api/pkg/provider"
+	"ketalk-api/pkg/provider/apple"
 	"ketalk-api/pkg/provider/google"
+	"ketalk-api/pkg/provider/outlook"
 	"ketalk-api/postgres"
+	"ketalk-api/sms"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 )
 
-func NewMiddleware(ctx context.Context, dbConfig postgres.ConfigPostgres) (common.Middleware, error) {
+func NewMiddleware(ctx context.Context, dbConfig postgres.ConfigPostgres, redisConn *redis.Client) (common.Middleware, error) {
 	db, err := postgres.InitDB(ctx, dbConfig)
 	if err != nil {
 		return nil, err
 	}
 	userRepo := user_repo.NewRepository(ctx, db)
 	userGeofenceRepo := user_repo.NewUserGeofenceRepository(db)
-	userPort := user_manager.NewUserPort(userRepo, userGeofenceRepo)
-	return middleware.NewMiddleware(userPort), nil
+	userIdentityRepo := user_repo.NewUserIdentityRepository(db)
+	userBlockRepo := user_repo.NewUserBlockRepository(db)
+	notificationPreferenceRepo := user_repo.NewNotificationPreferenceRepository(db)
+	userPort := user_manager.NewUserPort(userRepo, userGeofenceRepo, userIdentityRepo, userBlockRepo, notificationPreferenceRepo)
+	return middleware.NewMiddleware(userPort, auth_redis.NewRedisClient(redisConn)), nil
 }
 
 func InitHandlers(
 	ctx context.Context,
 	middleware common.Middleware,
-	redis conn_redis.RedisClient,
+	redisConn *redis.Client,
 	ginEngine *gin.Engine,
 	cfg config.Config) error {
 
@@ -64,7 +80,10 @@ func InitHandlers(
 
 	userRepo := user_repo.NewRepository(ctx, db)
 	authRepo := auth_repo.NewRepository(ctx, db)
-	itemRepo := item_repo.NewItemRepository(ctx, db)
+	emailVerificationRepo := auth_repo.NewEmailVerificationRepository(db)
+	loginLockoutRepo := auth_repo.NewLoginLockoutRepository(db)
+	totpRepo := auth_repo.NewTotpRepository(db)
+	itemRepo := item_repo.NewItemRepository(ctx, db, cfg.DB)
 	itemImageRepo := item_repo.NewItemImageRepository(ctx, db)
 	userItemRepo := item_repo.NewUserItemRepository(db, cfg.DB)
 
@@ -74,15 +93,31 @@ func InitHandlers(
 
 	karatRepo := item_repo.NewKaratRepository(db)
 	categoryRepo := item_repo.NewCategoryRepository(db)
+	reviewRepo := review_repo.NewReviewRepository(db)
+	reportRepo := moderation_repo.NewReportRepository(db)
+	moderationActionRepo := moderation_repo.NewModerationActionRepository(db)
 
 	geofenceRepo := geofence_repo.NewGeofenceRepository(db)
 	userGeofenceRepo := user_repo.NewUserGeofenceRepository(db)
+	userIdentityRepo := user_repo.NewUserIdentityRepository(db)
+	userBlockRepo := user_repo.NewUserBlockRepository(db)
+	notificationPreferenceRepo := user_repo.NewNotificationPreferenceRepository(db)
+	accountDeletionRepo := user_repo.NewAccountDeletionRepository(db)
+	dataExportRepo := user_repo.NewDataExportRepository(db)
 
 	// run migrations
 	if err := runMigrations(db, &cfg.DB,
 		userRepo,
 		authRepo,
+		emailVerificationRepo,
+		loginLockoutRepo,
+		totpRepo,
 		userGeofenceRepo,
+		userIdentityRepo,
+		userBlockRepo,
+		notificationPreferenceRepo,
+		accountDeletionRepo,
+		dataExportRepo,
 		itemRepo,
 		itemImageRepo,
 		userItemRepo,
@@ -91,29 +126,62 @@ func InitHandlers(
 		messageRepo,
 		karatRepo,
 		categoryRepo,
+		reviewRepo,
+		reportRepo,
+		moderationActionRepo,
 		geofenceRepo,
 	); err != nil {
 		return err
 	}
 
-	userPort := user_manager.NewUserPort(userRepo, userGeofenceRepo)
-	itemPort := item_manager.NewItemPort(itemRepo, itemImageRepo)
+	conversationRedis := conn_redis.NewRedisClient(redisConn, cfg.Redis)
+	authRedis := auth_redis.NewRedisClient(redisConn)
+	userRedis := user_redis.NewRedisClient(redisConn)
+
+	userPort := user_manager.NewUserPort(userRepo, userGeofenceRepo, userIdentityRepo, userBlockRepo, notificationPreferenceRepo)
+	itemPort := item_manager.NewItemPort(itemRepo, itemImageRepo, userItemRepo)
 	geofencePort := georegion_manager.NewGeofencePort(geofenceRepo)
+	reviewPort := review_manager.NewReviewPort(reviewRepo)
 
 	conversationPort := conversation_manager.NewConversationPort(conversationRepo, messageRepo, memberRepo)
+	authPort := auth_manager.NewAuthPort(authRepo, authRedis, cfg.Auth)
 
 	googleClient := google.NewGoogleClient(cfg.Google)
-	providerClient := provider.NewProviderClient(googleClient)
+	outlookClient := outlook.NewOutlookClient(cfg.Outlook)
+	appleClient := apple.NewAppleClient(cfg.Apple)
+	providerClient := provider.NewProviderClient(googleClient, outlookClient, appleClient)
 
-	authManager := auth_manager.NewAuthManager(authRepo, userPort, geofencePort, providerClient, cfg.Auth)
+	mailClient, err := mailer.NewMailer(cfg.Mailer)
+	if err != nil {
+		return err
+	}
+
+	smsSender, err := sms.NewSmsSender(cfg.Sms)
+	if err != nil {
+		return err
+	}
+
+	authManager := auth_manager.NewAuthManager(authRepo, emailVerificationRepo, loginLockoutRepo, totpRepo, userPort, geofencePort, providerClient, mailClient, smsSender, authRedis, cfg.Auth)
 	authHandler := auth_handler.NewHandler(authManager)
 
-	userManager := user_manager.NewUserManager(userRepo, userGeofenceRepo, geofencePort, blobStorage)
+	accountDeletionWorker := user_manager.NewAccountDeletionWorker(accountDeletionRepo, userRepo, authPort, itemPort, conversationPort, blobStorage)
+	go accountDeletionWorker.Run(ctx)
+
+	dataExportWorker := user_manager.NewDataExportWorker(dataExportRepo, userRepo, userGeofenceRepo, geofencePort, authPort, itemPort, conversationPort, blobStorage, cfg.UserExport)
+	go dataExportWorker.Run(ctx)
+
+	userManager := user_manager.NewUserManager(userRepo, userGeofenceRepo, userBlockRepo, notificationPreferenceRepo, accountDeletionRepo, dataExportRepo, geofencePort, itemPort, conversationPort, reviewPort, blobStorage, userRedis, accountDeletionWorker, dataExportWorker, cfg.UserGeofence)
 	userHandler := user_handler.NewHandler(userManager)
 
 	itemManager := item_manager.NewItemManager(itemRepo, itemImageRepo, userItemRepo, karatRepo, categoryRepo, userPort, conversationPort, geofencePort, blobStorage)
 	itemHandler := item_handler.NewHandler(itemManager)
 
+	reviewManager := review_manager.NewReviewManager(reviewRepo, itemPort, userPort, blobStorage)
+	reviewHandler := review_handler.NewHandler(reviewManager)
+
+	moderationManager := moderation_manager.NewModerationManager(reportRepo, moderationActionRepo, itemPort, userPort, conversationPort, authPort, cfg.Moderation)
+	moderationHandler := moderation_handler.NewHandler(moderationManager)
+
 	authHttpHandler := auth_handler.NewHttpHandler(ctx, authHandler, middleware)
 	authHttpHandler.Init(ctx, ginEngine)
 
@@ -123,14 +191,20 @@ func InitHandlers(
 	itemHttpHandler := item_handler.NewHttpHandler(ctx, itemHandler, middleware)
 	itemHttpHandler.Init(ctx, ginEngine)
 
-	conversationManager := conversation_manager.NewConversationManager(ctx, conversationRepo, memberRepo, messageRepo, itemPort, blobStorage, userPort, redis)
+	reviewHttpHandler := review_handler.NewHttpHandler(ctx, reviewHandler, middleware)
+	reviewHttpHandler.Init(ctx, ginEngine)
+
+	moderationHttpHandler := moderation_handler.NewHttpHandler(ctx, moderationHandler, middleware)
+	moderationHttpHandler.Init(ctx, ginEngine)
+
+	conversationManager := conversation_manager.NewConversationManager(ctx, conversationRepo, memberRepo, messageRepo, itemPort, blobStorage, userPort, conversationRedis)
 	conversationHandler := conversation_handler.NewHandler(conversationManager)
 
 	conversationHttpHandler := conversation_handler.NewHttpHandler(conversationHandler, middleware)
 	conversationHttpHandler.Init(ctx, ginEngine)
 
 	// run web socket server properly instead of inside the handler
-	go initWebSocketServer(ctx, userPort, middleware, redis, db, cfg)
+	go initWebSocketServer(ctx, userPort, middleware, conversationRedis, db, cfg)
 
 	return nil
 }
@@ -138,7 +212,15 @@ func InitHandlers(
 func runMigrations(db *gorm.DB,
 	dbConfig postgres.ConfigPostgres,
 	userRepo user_repo.Repository, authRepo auth_repo.Repository,
+	emailVerificationRepo auth_repo.EmailVerificationRepository,
+	loginLockoutRepo auth_repo.LoginLockoutRepository,
+	totpRepo auth_repo.TotpRepository,
 	userGeofenceRepo user_repo.UserGeofenceRepository,
+	userIdentityRepo user_repo.UserIdentityRepository,
+	userBlockRepo user_repo.UserBlockRepository,
+	notificationPreferenceRepo user_repo.NotificationPreferenceRepository,
+	accountDeletionRepo user_repo.AccountDeletionRepository,
+	dataExportRepo user_repo.DataExportRepository,
 	itemRepo item_repo.ItemRepository,
 	itemImageRepo item_repo.ItemImageRepository,
 	userItemRepo item_repo.UserItemRepository,
@@ -147,6 +229,9 @@ func runMigrations(db *gorm.DB,
 	messageRepo conversation_repo.MessageRepository,
 	karatRepo item_repo.KaratRepository,
 	categoryRepo item_repo.CategoryRepository,
+	reviewRepo review_repo.ReviewRepository,
+	reportRepo moderation_repo.ReportRepository,
+	moderationActionRepo moderation_repo.ModerationActionRepository,
 	geofenceRepo geofence_repo.GeofenceRepository,
 ) error {
 	if resp := db.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", dbConfig.GetSchema())); resp.Error != nil {
@@ -165,10 +250,42 @@ func runMigrations(db *gorm.DB,
 		return err
 	}
 
+	if err := userIdentityRepo.Migrate(); err != nil {
+		return err
+	}
+
+	if err := userBlockRepo.Migrate(); err != nil {
+		return err
+	}
+
+	if err := notificationPreferenceRepo.Migrate(); err != nil {
+		return err
+	}
+
+	if err := accountDeletionRepo.Migrate(); err != nil {
+		return err
+	}
+
+	if err := dataExportRepo.Migrate(); err != nil {
+		return err
+	}
+
 	if err := authRepo.Migrate(); err != nil {
 		return err
 	}
 
+	if err := emailVerificationRepo.Migrate(); err != nil {
+		return err
+	}
+
+	if err := loginLockoutRepo.Migrate(); err != nil {
+		return err
+	}
+
+	if err := totpRepo.Migrate(); err != nil {
+		return err
+	}
+
 	if err := itemRepo.Migrate(); err != nil {
 		return err
 	}
@@ -204,6 +321,21 @@ func runMigrations(db *gorm.DB,
 		return err
 	}
 
+	err = reviewRepo.Migrate()
+	if err != nil {
+		return err
+	}
+
+	err = reportRepo.Migrate()
+	if err != nil {
+		return err
+	}
+
+	err = moderationActionRepo.Migrate()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,42 @@
+package item_handler
+
+import (
+	"ketalk-api/common"
+	item_manager "ketalk-api/pkg/manager/item"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BumpItemResponse struct {
+	BumpedAt   int64 `json:"bumpedAt"`
+	NextBumpAt int64 `json:"nextBumpAt"`
+}
+
+func (h *HttpHandler) BumpItem(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.BumpItem(ctx)
+	return resp, err
+}
+
+func (h *handler) BumpItem(ctx *gin.Context) (*BumpItemResponse, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	itemID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.manager.BumpItem(ctx, item_manager.BumpItemRequest{
+		ItemID: itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &BumpItemResponse{
+		BumpedAt:   resp.BumpedAt.UTC().Unix(),
+		NextBumpAt: resp.NextBumpAt.UTC().Unix(),
+	}, nil
+}
@@ -1,6 +1,7 @@
 package item_handler
 
 import (
+	"ketalk-api/common"
 	item_manager "ketalk-api/pkg/manager/item"
 	"net/http"
 
@@ -29,9 +30,14 @@ func (h *handler) CreatePurchase(ctx *gin.Context, req CreatePurchaseRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
 	purchaseReq := item_manager.CreatePurchaseRequest{
-		ItemID:  itemID,
-		BuyerID: req.BuyerID,
+		ItemID:   itemID,
+		SellerID: userID,
+		BuyerID:  req.BuyerID,
 	}
 
 	_, err = h.manager.CreatePurchase(ctx, purchaseReq)
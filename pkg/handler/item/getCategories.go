@@ -12,8 +12,11 @@ type GetAllCategoriesResponse struct {
 }
 
 type Category struct {
-	ID      uuid.UUID                 `json:"id"`
-	Name    string                    `json:"name"`
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// Locales is deprecated, name and description are localized to the Accept-Language of the request.
+	// It is kept for one release so clients can switch.
 	Locales map[string]CategoryLocale `json:"locales"`
 }
 
@@ -44,9 +47,10 @@ func (h *handler) GetAllCategories(ctx *gin.Context) (*GetAllCategoriesResponse,
 			}
 		}
 		categories[i] = Category{
-			ID:      category.ID,
-			Name:    category.Name,
-			Locales: locales,
+			ID:          category.ID,
+			Name:        category.Name,
+			Description: category.Description,
+			Locales:     locales,
 		}
 	}
 	return &GetAllCategoriesResponse{
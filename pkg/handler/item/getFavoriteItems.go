@@ -9,7 +9,8 @@ import (
 )
 
 type GetFavoriteItemsResponse struct {
-	Items []ItemBlock `json:"items"`
+	Items      []ItemBlock `json:"items"`
+	NextCursor *string     `json:"nextCursor"`
 }
 
 func (h *HttpHandler) GetFavoriteItems(ctx *gin.Context, r *http.Request) (interface{}, error) {
@@ -22,15 +23,20 @@ func (h *handler) GetFavoriteItems(ctx *gin.Context) (*GetFavoriteItemsResponse,
 	if err != nil {
 		return nil, err
 	}
+	page, err := getPageRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
 	req := item_manager.GetFavoriteItemsRequest{
-		UserID: userID,
+		UserID:      userID,
+		PageRequest: page,
 	}
 	resp, err := h.manager.GetFavoriteItems(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	var items []ItemBlock = make([]ItemBlock, len(resp))
-	for i, item := range resp {
+	var items []ItemBlock = make([]ItemBlock, len(resp.Items))
+	for i, item := range resp.Items {
 		items[i] = ItemBlock{
 			ID:            item.ID,
 			Title:         item.Title,
@@ -47,6 +53,7 @@ func (h *handler) GetFavoriteItems(ctx *gin.Context) (*GetFavoriteItemsResponse,
 		}
 	}
 	return &GetFavoriteItemsResponse{
-		Items: items,
+		Items:      items,
+		NextCursor: resp.NextCursor,
 	}, nil
 }
@@ -10,7 +10,8 @@ import (
 )
 
 type GetItemsResponse struct {
-	Items []ItemBlock `json:"items"`
+	Items      []ItemBlock `json:"items"`
+	NextCursor *string     `json:"nextCursor"`
 }
 
 type ItemBlock struct {
@@ -42,16 +43,26 @@ func (h *handler) GetItems(ctx *gin.Context) (*GetItemsResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	sort, err := item_manager.ParseItemSort(ctx.Query("sort"))
+	if err != nil {
+		return nil, err
+	}
+	page, err := getPageRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
 	req := item_manager.GetItemsRequest{
-		Location: *location,
-		UserID:   userID,
+		Location:    *location,
+		UserID:      userID,
+		Sort:        sort,
+		PageRequest: page,
 	}
 	resp, err := h.manager.GetItems(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	var items []ItemBlock = make([]ItemBlock, len(resp))
-	for i, item := range resp {
+	var items []ItemBlock = make([]ItemBlock, len(resp.Items))
+	for i, item := range resp.Items {
 		items[i] = ItemBlock{
 			ID:            item.ID,
 			Title:         item.Title,
@@ -68,6 +79,7 @@ func (h *handler) GetItems(ctx *gin.Context) (*GetItemsResponse, error) {
 		}
 	}
 	return &GetItemsResponse{
-		Items: items,
+		Items:      items,
+		NextCursor: resp.NextCursor,
 	}, nil
 }
@@ -12,8 +12,11 @@ type GetAllKaratsResponse struct {
 }
 
 type Karat struct {
-	ID      uuid.UUID              `json:"id"`
-	Name    string                 `json:"name"`
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// Locales is deprecated, name and description are localized to the Accept-Language of the request.
+	// It is kept for one release so clients can switch.
 	Locales map[string]KaratLocale `json:"locales"`
 }
 
@@ -44,9 +47,10 @@ func (h *handler) GetAllKarats(ctx *gin.Context) (*GetAllKaratsResponse, error)
 			}
 		}
 		karats[i] = Karat{
-			ID:      karat.ID,
-			Name:    karat.Name,
-			Locales: locales,
+			ID:          karat.ID,
+			Name:        karat.Name,
+			Description: karat.Description,
+			Locales:     locales,
 		}
 	}
 	return &GetAllKaratsResponse{
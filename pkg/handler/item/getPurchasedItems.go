@@ -9,7 +9,8 @@ import (
 )
 
 type GetPurchasedItemsResponse struct {
-	Items []ItemBlock `json:"items"`
+	Items      []ItemBlock `json:"items"`
+	NextCursor *string     `json:"nextCursor"`
 }
 
 func (h *HttpHandler) GetPurchasedItems(ctx *gin.Context, r *http.Request) (interface{}, error) {
@@ -22,16 +23,21 @@ func (h *handler) GetPurchasedItems(ctx *gin.Context) (*GetPurchasedItemsRespons
 	if err != nil {
 		return nil, err
 	}
+	page, err := getPageRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
 	req := item_manager.GetPurchasedItemsRequest{
-		UserID: userID,
+		UserID:      userID,
+		PageRequest: page,
 	}
 	purchasedItems, err := h.manager.GetPurchasedItems(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	var items []ItemBlock = make([]ItemBlock, len(purchasedItems))
-	for i, item := range purchasedItems {
+	var items []ItemBlock = make([]ItemBlock, len(purchasedItems.Items))
+	for i, item := range purchasedItems.Items {
 		items[i] = ItemBlock{
 			ID:            item.ID,
 			Title:         item.Title,
@@ -48,6 +54,7 @@ func (h *handler) GetPurchasedItems(ctx *gin.Context) (*GetPurchasedItemsRespons
 		}
 	}
 	return &GetPurchasedItemsResponse{
-		Items: items,
+		Items:      items,
+		NextCursor: purchasedItems.NextCursor,
 	}, nil
 }
@@ -37,6 +37,7 @@ func (c *HttpHandler) Init(ctx context.Context, router *gin.Engine) {
 			"":              c.middleware.HandlerWithAuth(c.CreateItem),
 			"/:id/favorite": c.middleware.HandlerWithAuth(c.FavoriteItem),
 			"/:id/purchase": c.middleware.HandlerWithAuth(c.CreatePurchase),
+			"/:id/bump":     c.middleware.HandlerWithAuth(c.BumpItem),
 		},
 		"PUT": {
 			"/image/upload":              c.middleware.HandlerWithAuth(c.UploadItemImages),
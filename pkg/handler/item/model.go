@@ -20,4 +20,5 @@ type ItemHandler interface {
 	CreatePurchase(ctx *gin.Context, req CreatePurchaseRequest) (*CreatePurchaseResponse, error)
 	SearchItems(ctx *gin.Context) (*SearchItemsResponse, error)
 	DeleteItem(ctx *gin.Context) (*DeleteItemResponse, error)
+	BumpItem(ctx *gin.Context) (*BumpItemResponse, error)
 }
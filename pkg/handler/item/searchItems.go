@@ -2,6 +2,7 @@ package item_handler
 
 import (
 	"fmt"
+	"ketalk-api/common"
 	item_manager "ketalk-api/pkg/manager/item"
 	"math"
 	"net/http"
@@ -13,10 +14,11 @@ import (
 )
 
 type SearchItemsResponse struct {
-	Items []ItemBlock `json:"items"`
+	Items      []ItemBlock `json:"items"`
+	NextCursor *string     `json:"nextCursor"`
 }
 
-// search?priceRange=100,1000&karatIds=18,24&categoryIds=ring,necklace&sizeRange=10,20&keyword=hello
+// search?priceRange=100,1000&karatIds=18,24&categoryIds=ring,necklace&sizeRange=10,20&keyword=hello&sort=price_asc
 
 func (h *HttpHandler) SearchItems(ctx *gin.Context, r *http.Request) (interface{}, error) {
 	resp, err := h.handler.SearchItems(ctx)
@@ -40,6 +42,14 @@ func (h *handler) SearchItems(ctx *gin.Context) (*SearchItemsResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	sort, err := item_manager.ParseItemSort(ctx.Query("sort"))
+	if err != nil {
+		return nil, err
+	}
+	page, err := getPageRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
 	keyword := ctx.Query("keyword")
 	manReq := item_manager.SearchItemsRequest{
 		Keyword:     keyword,
@@ -47,13 +57,19 @@ func (h *handler) SearchItems(ctx *gin.Context) (*SearchItemsResponse, error) {
 		SizeRange:   sizeRange,
 		KaratIDs:    karatIds,
 		CategoryIDs: categoryIds,
+		Sort:        sort,
+		PageRequest: page,
+	}
+	// search works without signing in, the block list only applies to a signed in user
+	if userID, err := common.GetUserId(ctx.Request.Context()); err == nil {
+		manReq.UserID = &userID
 	}
 	itemBlocks, err := h.manager.SearchItems(ctx, manReq)
 	if err != nil {
 		return nil, err
 	}
-	var items []ItemBlock = make([]ItemBlock, len(itemBlocks))
-	for i, item := range itemBlocks {
+	var items []ItemBlock = make([]ItemBlock, len(itemBlocks.Items))
+	for i, item := range itemBlocks.Items {
 		items[i] = ItemBlock{
 			ID:            item.ID,
 			Title:         item.Title,
@@ -71,7 +87,8 @@ func (h *handler) SearchItems(ctx *gin.Context) (*SearchItemsResponse, error) {
 	}
 
 	return &SearchItemsResponse{
-		Items: items,
+		Items:      items,
+		NextCursor: itemBlocks.NextCursor,
 	}, nil
 }
 
@@ -156,3 +173,18 @@ func getIds(ctx *gin.Context, key string) ([]uuid.UUID, error) {
 	}
 	return uuids, nil
 }
+
+// getPageRequest reads the cursor and limit of a paginated list, e.g. ?cursor=<nextCursor>&limit=20
+func getPageRequest(ctx *gin.Context) (item_manager.PageRequest, error) {
+	page := item_manager.PageRequest{
+		Cursor: ctx.Query("cursor"),
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return item_manager.PageRequest{}, fmt.Errorf("%w: limit must be a number", common.ErrInvalidInput)
+		}
+		page.Limit = l
+	}
+	return page, nil
+}
@@ -0,0 +1,60 @@
+package moderation_handler
+
+import (
+	"ketalk-api/common"
+	moderation_manager "ketalk-api/pkg/manager/moderation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateReportRequest struct {
+	// TargetType is one of item, user or message
+	TargetType string    `json:"targetType"`
+	TargetID   uuid.UUID `json:"targetId"`
+	Reason     string    `json:"reason"`
+	Details    string    `json:"details"`
+}
+
+func (h *HttpHandler) CreateReport(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req CreateReportRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	resp, err := h.handler.CreateReport(ctx, req)
+	return resp, err
+}
+
+func (h *handler) CreateReport(ctx *gin.Context, req CreateReportRequest) (*Report, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	report, err := h.manager.CreateReport(ctx, moderation_manager.CreateReportRequest{
+		ReporterID: userID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toReport(report), nil
+}
+
+func toReport(report *moderation_manager.Report) *Report {
+	return &Report{
+		ID:         report.ID,
+		ReporterID: report.ReporterID,
+		TargetType: report.TargetType,
+		TargetID:   report.TargetID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		ResolvedBy: report.ResolvedBy,
+		ResolvedAt: report.ResolvedAt,
+		CreatedAt:  report.CreatedAt,
+	}
+}
@@ -0,0 +1,86 @@
+package moderation_handler
+
+import (
+	moderation_manager "ketalk-api/pkg/manager/moderation"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ModerationAction struct {
+	ID uuid.UUID `json:"id"`
+	// ModeratorID is null for actions taken automatically
+	ModeratorID *uuid.UUID `json:"moderatorId"`
+	Action      string     `json:"action"`
+	TargetType  string     `json:"targetType"`
+	TargetID    uuid.UUID  `json:"targetId"`
+	ReportID    *uuid.UUID `json:"reportId"`
+	Note        string     `json:"note"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+type GetActionsResponse struct {
+	Actions []ModerationAction `json:"actions"`
+}
+
+func (h *HttpHandler) GetActions(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.GetActions(ctx)
+}
+
+// GetActions reads the optional targetType, targetId, moderatorId, since (RFC 3339) and limit query parameters
+func (h *handler) GetActions(ctx *gin.Context) (*GetActionsResponse, error) {
+	req := moderation_manager.GetActionsRequest{
+		TargetType: ctx.Query("targetType"),
+	}
+	if targetID := ctx.Query("targetId"); targetID != "" {
+		id, err := uuid.Parse(targetID)
+		if err != nil {
+			return nil, err
+		}
+		req.TargetID = &id
+	}
+	if moderatorID := ctx.Query("moderatorId"); moderatorID != "" {
+		id, err := uuid.Parse(moderatorID)
+		if err != nil {
+			return nil, err
+		}
+		req.ModeratorID = &id
+	}
+	if since := ctx.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, err
+		}
+		req.Since = &t
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, err
+		}
+		req.Limit = l
+	}
+	actions, err := h.manager.GetActions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]ModerationAction, len(actions))
+	for i, action := range actions {
+		resp[i] = ModerationAction{
+			ID:          action.ID,
+			ModeratorID: action.ModeratorID,
+			Action:      action.Action,
+			TargetType:  action.TargetType,
+			TargetID:    action.TargetID,
+			ReportID:    action.ReportID,
+			Note:        action.Note,
+			CreatedAt:   action.CreatedAt,
+		}
+	}
+	return &GetActionsResponse{
+		Actions: resp,
+	}, nil
+}
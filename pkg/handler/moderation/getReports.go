@@ -0,0 +1,76 @@
+package moderation_handler
+
+import (
+	moderation_manager "ketalk-api/pkg/manager/moderation"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GetReportsResponse struct {
+	Reports []Report `json:"reports"`
+}
+
+type ReportDetail struct {
+	Report
+	ReportCount   int64     `json:"reportCount"`
+	Content       string    `json:"content"`
+	TargetOwnerID uuid.UUID `json:"targetOwnerId"`
+}
+
+func (h *HttpHandler) GetReports(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.GetReports(ctx)
+}
+
+// GetReports reads the optional status, targetType and limit query parameters,
+// only open reports are listed unless status is given, "all" lists every report
+func (h *handler) GetReports(ctx *gin.Context) (*GetReportsResponse, error) {
+	req := moderation_manager.GetReportsRequest{
+		Status:     ctx.DefaultQuery("status", "open"),
+		TargetType: ctx.Query("targetType"),
+	}
+	if req.Status == "all" {
+		req.Status = ""
+	}
+	if limit := ctx.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			return nil, err
+		}
+		req.Limit = l
+	}
+	reports, err := h.manager.GetReports(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]Report, len(reports))
+	for i := range reports {
+		resp[i] = *toReport(&reports[i])
+	}
+	return &GetReportsResponse{
+		Reports: resp,
+	}, nil
+}
+
+func (h *HttpHandler) GetReport(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	return h.handler.GetReport(ctx)
+}
+
+func (h *handler) GetReport(ctx *gin.Context) (*ReportDetail, error) {
+	reportID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	detail, err := h.manager.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	return &ReportDetail{
+		Report:        *toReport(&detail.Report),
+		ReportCount:   detail.ReportCount,
+		Content:       detail.Content,
+		TargetOwnerID: detail.TargetOwnerID,
+	}, nil
+}
@@ -0,0 +1,31 @@
+package moderation_handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ModerationHandler interface {
+	CreateReport(ctx *gin.Context, req CreateReportRequest) (*Report, error)
+	GetReports(ctx *gin.Context) (*GetReportsResponse, error)
+	GetReport(ctx *gin.Context) (*ReportDetail, error)
+	DismissReport(ctx *gin.Context, req DismissReportRequest) error
+	HideItem(ctx *gin.Context, req HideItemRequest) error
+	SuspendUser(ctx *gin.Context, req SuspendUserRequest) error
+	GetActions(ctx *gin.Context) (*GetActionsResponse, error)
+}
+
+type Report struct {
+	ID         uuid.UUID  `json:"id"`
+	ReporterID uuid.UUID  `json:"reporterId"`
+	TargetType string     `json:"targetType"`
+	TargetID   uuid.UUID  `json:"targetId"`
+	Reason     string     `json:"reason"`
+	Details    string     `json:"details"`
+	Status     string     `json:"status"`
+	ResolvedBy *uuid.UUID `json:"resolvedBy"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
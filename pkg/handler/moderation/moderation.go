@@ -0,0 +1,54 @@
+package moderation_handler
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/common"
+	manager "ketalk-api/pkg/manager/moderation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HttpHandler struct {
+	handler    ModerationHandler
+	middleware common.Middleware
+}
+
+type handler struct {
+	manager manager.ModerationManager
+}
+
+func NewHandler(manager manager.ModerationManager) ModerationHandler {
+	return &handler{
+		manager,
+	}
+}
+
+func NewHttpHandler(ctx context.Context, h ModerationHandler, middleware common.Middleware) *HttpHandler {
+	return &HttpHandler{
+		h,
+		middleware,
+	}
+}
+
+func (c *HttpHandler) Init(ctx context.Context, router *gin.Engine) {
+	routes := map[string]map[string]common.HandlerFunc{
+		"POST": {
+			"/reports":             c.middleware.HandlerWithAuth(c.CreateReport),
+			"/reports/:id/dismiss": c.middleware.HandlerWithRole(common.RoleModerator, c.DismissReport),
+			"/items/:id/hide":      c.middleware.HandlerWithRole(common.RoleModerator, c.HideItem),
+			"/users/:id/suspend":   c.middleware.HandlerWithRole(common.RoleModerator, c.SuspendUser),
+		},
+		"GET": {
+			"/reports":     c.middleware.HandlerWithRole(common.RoleModerator, c.GetReports),
+			"/reports/:id": c.middleware.HandlerWithRole(common.RoleModerator, c.GetReport),
+			"/actions":     c.middleware.HandlerWithRole(common.RoleModerator, c.GetActions),
+		},
+	}
+	for method, route := range routes {
+		for r, h := range route {
+			router.Handle(method, fmt.Sprintf("/moderation%s", r), common.GenericHandler(h))
+		}
+	}
+	fmt.Println("initialized moderation handler")
+}
@@ -0,0 +1,105 @@
+package moderation_handler
+
+import (
+	"ketalk-api/common"
+	moderation_manager "ketalk-api/pkg/manager/moderation"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DismissReportRequest struct {
+	Note string `json:"note"`
+}
+
+type HideItemRequest struct {
+	ReportID *uuid.UUID `json:"reportId"`
+	Note     string     `json:"note"`
+}
+
+type SuspendUserRequest struct {
+	ReportID *uuid.UUID `json:"reportId"`
+	Note     string     `json:"note"`
+	// Until is left out for an indefinite suspension
+	Until *time.Time `json:"until"`
+}
+
+func (h *HttpHandler) DismissReport(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req DismissReportRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	err := h.handler.DismissReport(ctx, req)
+	return nil, err
+}
+
+func (h *handler) DismissReport(ctx *gin.Context, req DismissReportRequest) error {
+	moderatorID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	reportID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return h.manager.DismissReport(ctx, moderation_manager.DismissReportRequest{
+		ModeratorID: moderatorID,
+		ReportID:    reportID,
+		Note:        req.Note,
+	})
+}
+
+func (h *HttpHandler) HideItem(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req HideItemRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	err := h.handler.HideItem(ctx, req)
+	return nil, err
+}
+
+func (h *handler) HideItem(ctx *gin.Context, req HideItemRequest) error {
+	moderatorID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	itemID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return h.manager.HideItem(ctx, moderation_manager.HideItemRequest{
+		ModeratorID: moderatorID,
+		ItemID:      itemID,
+		ReportID:    req.ReportID,
+		Note:        req.Note,
+	})
+}
+
+func (h *HttpHandler) SuspendUser(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req SuspendUserRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	err := h.handler.SuspendUser(ctx, req)
+	return nil, err
+}
+
+func (h *handler) SuspendUser(ctx *gin.Context, req SuspendUserRequest) error {
+	moderatorID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return h.manager.SuspendUser(ctx, moderation_manager.SuspendUserRequest{
+		ModeratorID: moderatorID,
+		UserID:      userID,
+		Until:       req.Until,
+		ReportID:    req.ReportID,
+		Note:        req.Note,
+	})
+}
@@ -0,0 +1,64 @@
+package review_handler
+
+import (
+	"ketalk-api/common"
+	review_manager "ketalk-api/pkg/manager/review"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CreateReviewRequest struct {
+	ItemID uuid.UUID `json:"itemId"`
+	// BuyerID is only needed when the seller reviews the buyer
+	BuyerID *uuid.UUID `json:"buyerId"`
+	Rating  int        `json:"rating"`
+	Text    string     `json:"text"`
+}
+
+func (h *HttpHandler) CreateReview(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req CreateReviewRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	resp, err := h.handler.CreateReview(ctx, req)
+	return resp, err
+}
+
+func (h *handler) CreateReview(ctx *gin.Context, req CreateReviewRequest) (*Review, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	review, err := h.manager.CreateReview(ctx, review_manager.CreateReviewRequest{
+		ReviewerID: userID,
+		ItemID:     req.ItemID,
+		BuyerID:    req.BuyerID,
+		Rating:     req.Rating,
+		Text:       req.Text,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toReview(review), nil
+}
+
+func toReview(review *review_manager.Review) *Review {
+	return &Review{
+		ID:     review.ID,
+		ItemID: review.ItemID,
+		Reviewer: Reviewer{
+			ID:     review.Reviewer.ID,
+			Name:   review.Reviewer.Name,
+			Avatar: review.Reviewer.Avatar,
+		},
+		RevieweeID:    review.RevieweeID,
+		ReviewerRole:  review.ReviewerRole,
+		Rating:        review.Rating,
+		Text:          review.Text,
+		CreatedAt:     review.CreatedAt,
+		EditedAt:      review.EditedAt,
+		EditableUntil: review.EditableUntil,
+	}
+}
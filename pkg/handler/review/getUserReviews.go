@@ -0,0 +1,45 @@
+package review_handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RatingSummary struct {
+	Count   int64    `json:"count"`
+	Average *float64 `json:"average"`
+}
+
+type GetUserReviewsResponse struct {
+	Summary RatingSummary `json:"summary"`
+	Reviews []Review      `json:"reviews"`
+}
+
+func (h *HttpHandler) GetUserReviews(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.GetUserReviews(ctx)
+	return resp, err
+}
+
+func (h *handler) GetUserReviews(ctx *gin.Context) (*GetUserReviewsResponse, error) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.manager.GetUserReviews(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	reviews := make([]Review, len(resp.Reviews))
+	for i := range resp.Reviews {
+		reviews[i] = *toReview(&resp.Reviews[i])
+	}
+	return &GetUserReviewsResponse{
+		Summary: RatingSummary{
+			Count:   resp.Summary.Count,
+			Average: resp.Summary.Average,
+		},
+		Reviews: reviews,
+	}, nil
+}
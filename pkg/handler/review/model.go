@@ -0,0 +1,33 @@
+package review_handler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReviewHandler interface {
+	CreateReview(ctx *gin.Context, req CreateReviewRequest) (*Review, error)
+	UpdateReview(ctx *gin.Context, req UpdateReviewRequest) (*Review, error)
+	GetUserReviews(ctx *gin.Context) (*GetUserReviewsResponse, error)
+}
+
+type Reviewer struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Avatar *string   `json:"avatar"`
+}
+
+type Review struct {
+	ID            uuid.UUID  `json:"id"`
+	ItemID        uuid.UUID  `json:"itemId"`
+	Reviewer      Reviewer   `json:"reviewer"`
+	RevieweeID    uuid.UUID  `json:"revieweeId"`
+	ReviewerRole  string     `json:"reviewerRole"`
+	Rating        int        `json:"rating"`
+	Text          string     `json:"text"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	EditedAt      *time.Time `json:"editedAt"`
+	EditableUntil time.Time  `json:"editableUntil"`
+}
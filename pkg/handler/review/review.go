@@ -0,0 +1,52 @@
+package review_handler
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/common"
+	manager "ketalk-api/pkg/manager/review"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HttpHandler struct {
+	handler    ReviewHandler
+	middleware common.Middleware
+}
+
+type handler struct {
+	manager manager.ReviewManager
+}
+
+func NewHandler(manager manager.ReviewManager) ReviewHandler {
+	return &handler{
+		manager,
+	}
+}
+
+func NewHttpHandler(ctx context.Context, h ReviewHandler, middleware common.Middleware) *HttpHandler {
+	return &HttpHandler{
+		h,
+		middleware,
+	}
+}
+
+func (c *HttpHandler) Init(ctx context.Context, router *gin.Engine) {
+	routes := map[string]map[string]common.HandlerFunc{
+		"POST": {
+			"": c.middleware.HandlerWithAuth(c.CreateReview),
+		},
+		"PUT": {
+			"/:id": c.middleware.HandlerWithAuth(c.UpdateReview),
+		},
+		"GET": {
+			"/user/:id": c.middleware.HandlerWithAuth(c.GetUserReviews),
+		},
+	}
+	for method, route := range routes {
+		for r, h := range route {
+			router.Handle(method, fmt.Sprintf("/review%s", r), common.GenericHandler(h))
+		}
+	}
+	fmt.Println("initialized review handler")
+}
@@ -0,0 +1,45 @@
+package review_handler
+
+import (
+	"ketalk-api/common"
+	review_manager "ketalk-api/pkg/manager/review"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UpdateReviewRequest struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+func (h *HttpHandler) UpdateReview(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req UpdateReviewRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	resp, err := h.handler.UpdateReview(ctx, req)
+	return resp, err
+}
+
+func (h *handler) UpdateReview(ctx *gin.Context, req UpdateReviewRequest) (*Review, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	reviewID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	review, err := h.manager.UpdateReview(ctx, review_manager.UpdateReviewRequest{
+		ReviewID:   reviewID,
+		ReviewerID: userID,
+		Rating:     req.Rating,
+		Text:       req.Text,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toReview(review), nil
+}
@@ -0,0 +1,98 @@
+package user_handler
+
+import (
+	"ketalk-api/common"
+	user_manager "ketalk-api/pkg/manager/user"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BlockUserRequest struct {
+	UserID uuid.UUID `json:"userId"`
+}
+
+type BlockedUser struct {
+	UserId    uuid.UUID `json:"id"`
+	Username  string    `json:"userName"`
+	Image     *string   `json:"avatar"`
+	BlockedAt time.Time `json:"blockedAt"`
+}
+
+type GetBlockedUsersResponse struct {
+	Users []BlockedUser `json:"users"`
+}
+
+func (h *HttpHandler) BlockUser(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req BlockUserRequest
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := h.handler.BlockUser(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *HttpHandler) UnblockUser(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	if err := h.handler.UnblockUser(ctx); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *HttpHandler) GetBlockedUsers(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.GetBlockedUsers(ctx)
+	return resp, err
+}
+
+func (h *handler) BlockUser(ctx *gin.Context, req BlockUserRequest) error {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	return h.manager.BlockUser(ctx, user_manager.BlockUserRequest{
+		UserID:        userID,
+		BlockedUserID: req.UserID,
+	})
+}
+
+func (h *handler) UnblockUser(ctx *gin.Context) error {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return err
+	}
+	blockedUserID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return h.manager.UnblockUser(ctx, user_manager.BlockUserRequest{
+		UserID:        userID,
+		BlockedUserID: blockedUserID,
+	})
+}
+
+func (h *handler) GetBlockedUsers(ctx *gin.Context) (*GetBlockedUsersResponse, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	blockedUsers, err := h.manager.GetBlockedUsers(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]BlockedUser, len(blockedUsers))
+	for i, user := range blockedUsers {
+		users[i] = BlockedUser{
+			UserId:    user.ID,
+			Username:  user.Username,
+			Image:     user.Image,
+			BlockedAt: user.BlockedAt,
+		}
+	}
+	return &GetBlockedUsersResponse{
+		Users: users,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package user_handler
+
+import (
+	"ketalk-api/common"
+	user_manager "ketalk-api/pkg/manager/user"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DataExportResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Status    string     `json:"status"`
+	Url       *string    `json:"url"`
+	CreatedAt time.Time  `json:"createdAt"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+func (h *HttpHandler) RequestDataExport(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.RequestDataExport(ctx)
+	return resp, err
+}
+
+func (h *HttpHandler) GetDataExport(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.GetDataExport(ctx)
+	return resp, err
+}
+
+func (h *handler) RequestDataExport(ctx *gin.Context) (*DataExportResponse, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.manager.RequestDataExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toDataExportResponse(resp), nil
+}
+
+func (h *handler) GetDataExport(ctx *gin.Context) (*DataExportResponse, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	exportID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.manager.GetDataExport(ctx, user_manager.GetDataExportRequest{
+		UserID:   userID,
+		ExportID: exportID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDataExportResponse(resp), nil
+}
+
+func toDataExportResponse(export *user_manager.DataExport) *DataExportResponse {
+	return &DataExportResponse{
+		ID:        export.ID,
+		Status:    export.Status,
+		Url:       export.Url,
+		CreatedAt: export.CreatedAt,
+		ExpiresAt: export.ExpiresAt,
+	}
+}
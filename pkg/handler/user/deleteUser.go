@@ -0,0 +1,34 @@
+package user_handler
+
+import (
+	"ketalk-api/common"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeleteUserResponse struct {
+	JobID  uuid.UUID `json:"jobId"`
+	Status string    `json:"status"`
+}
+
+func (h *HttpHandler) DeleteUser(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.DeleteUser(ctx)
+	return resp, err
+}
+
+func (h *handler) DeleteUser(ctx *gin.Context) (*DeleteUserResponse, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.manager.DeleteUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &DeleteUserResponse{
+		JobID:  resp.JobID,
+		Status: resp.Status,
+	}, nil
+}
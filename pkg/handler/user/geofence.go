@@ -0,0 +1,92 @@
+package user_handler
+
+import (
+	"ketalk-api/common"
+	user_manager "ketalk-api/pkg/manager/user"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VerifyGeofenceResponse struct {
+	Geofence     Geofence   `json:"geofence"`
+	VerifiedAt   time.Time  `json:"verifiedAt"`
+	Changed      bool       `json:"changed"`
+	NextChangeAt *time.Time `json:"nextChangeAt"`
+}
+
+type GeofenceHistoryEntry struct {
+	Geofence   Geofence   `json:"geofence"`
+	From       time.Time  `json:"from"`
+	Until      *time.Time `json:"until"`
+	VerifiedAt *time.Time `json:"verifiedAt"`
+}
+
+type GetGeofenceHistoryResponse struct {
+	Geofences []GeofenceHistoryEntry `json:"geofences"`
+}
+
+func (h *HttpHandler) VerifyGeofence(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.VerifyGeofence(ctx)
+	return resp, err
+}
+
+func (h *HttpHandler) GetGeofenceHistory(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.GetGeofenceHistory(ctx)
+	return resp, err
+}
+
+// VerifyGeofence reads the current location from the latitude and longitude headers
+func (h *handler) VerifyGeofence(ctx *gin.Context) (*VerifyGeofenceResponse, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	location, err := common.GetLocation(ctx.Request)
+	if err != nil {
+		return nil, err
+	}
+	verification, err := h.manager.VerifyGeofence(ctx, user_manager.VerifyGeofenceRequest{
+		UserID:   userID,
+		Location: *location,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &VerifyGeofenceResponse{
+		Geofence: Geofence{
+			ID:   verification.Geofence.ID,
+			Name: verification.Geofence.Name,
+		},
+		VerifiedAt:   verification.VerifiedAt,
+		Changed:      verification.Changed,
+		NextChangeAt: verification.NextChangeAt,
+	}, nil
+}
+
+func (h *handler) GetGeofenceHistory(ctx *gin.Context) (*GetGeofenceHistoryResponse, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	history, err := h.manager.GetGeofenceHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]GeofenceHistoryEntry, len(history))
+	for i, entry := range history {
+		resp[i] = GeofenceHistoryEntry{
+			Geofence: Geofence{
+				ID:   entry.Geofence.ID,
+				Name: entry.Geofence.Name,
+			},
+			From:       entry.From,
+			Until:      entry.Until,
+			VerifiedAt: entry.VerifiedAt,
+		}
+	}
+	return &GetGeofenceHistoryResponse{
+		Geofences: resp,
+	}, nil
+}
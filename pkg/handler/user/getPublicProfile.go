@@ -0,0 +1,88 @@
+package user_handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GetPublicProfileResponse struct {
+	UserId      uuid.UUID `json:"id"`
+	Username    string    `json:"userName"`
+	Image       *string   `json:"avatar"`
+	Verified    bool      `json:"verified"`
+	Geofence    Geofence  `json:"geofence"`
+	MemberSince time.Time `json:"memberSince"`
+	Stats       UserStats `json:"stats"`
+	Rating      Rating    `json:"rating"`
+}
+
+type Rating struct {
+	Count   int64    `json:"count"`
+	Average *float64 `json:"average"`
+}
+
+type UserStats struct {
+	ActiveCount            int64      `json:"activeCount"`
+	SoldCount              int64      `json:"soldCount"`
+	AverageResponseSeconds *int64     `json:"averageResponseSeconds"`
+	ResponseRate           *float64   `json:"responseRate"`
+	LastActiveAt           *time.Time `json:"lastActiveAt"`
+}
+
+// @BasePath /api/v1
+
+// GetPublicProfile
+// @Summary Get public profile
+// @Schemes
+// @Description get the public profile of a user with the seller statistics
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authoriztion"
+// @Param id path string true "User ID"
+// @Success 200 {object} GetPublicProfileResponse
+// @Router /user/{id} [get]
+func (h *HttpHandler) GetPublicProfile(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.GetPublicProfile(ctx)
+	return resp, err
+}
+
+func (h *handler) GetPublicProfile(ctx *gin.Context) (*GetPublicProfileResponse, error) {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	profile, err := h.manager.GetPublicProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var averageResponseSeconds *int64
+	if profile.Stats.AverageResponseTime != nil {
+		seconds := int64(profile.Stats.AverageResponseTime.Seconds())
+		averageResponseSeconds = &seconds
+	}
+	return &GetPublicProfileResponse{
+		UserId:   profile.ID,
+		Username: profile.Username,
+		Image:    profile.Image,
+		Verified: profile.Verified,
+		Geofence: Geofence{
+			ID:   profile.Geofence.ID,
+			Name: profile.Geofence.Name,
+		},
+		MemberSince: profile.MemberSince,
+		Stats: UserStats{
+			ActiveCount:            profile.Stats.ActiveCount,
+			SoldCount:              profile.Stats.SoldCount,
+			AverageResponseSeconds: averageResponseSeconds,
+			ResponseRate:           profile.Stats.ResponseRate,
+			LastActiveAt:           profile.Stats.LastActiveAt,
+		},
+		Rating: Rating{
+			Count:   profile.Rating.Count,
+			Average: profile.Rating.Average,
+		},
+	}, nil
+}
@@ -13,6 +13,9 @@ type GetUserResponse struct {
 	Username string    `json:"userName"`
 	Email    string    `json:"email"`
 	Image    *string   `json:"avatar"`
+	Phone    *string   `json:"phone"`
+	Verified bool      `json:"verified"`
+	Locale   string    `json:"locale"`
 	Geofence Geofence  `json:"geofence"`
 }
 
@@ -51,6 +54,9 @@ func (h *handler) GetUser(ctx *gin.Context) (*GetUserResponse, error) {
 		Username: user.Username,
 		Email:    user.Email,
 		Image:    user.Image,
+		Phone:    user.Phone,
+		Verified: user.Verified,
+		Locale:   string(user.Locale),
 		Geofence: Geofence{
 			ID:   user.Geofence.ID,
 			Name: user.Geofence.Name,
@@ -6,6 +6,18 @@ import (
 
 type UserHandler interface {
 	GetUser(ctx *gin.Context) (*GetUserResponse, error)
+	GetPublicProfile(ctx *gin.Context) (*GetPublicProfileResponse, error)
 	UpdateUser(ctx *gin.Context, req UpdateUserRequest) (*UpdateUserResponse, error)
 	GetPresignedUrl(ctx *gin.Context) (*GetPresignedUrlResponse, error)
+	SetRole(ctx *gin.Context, req SetRoleRequest) error
+	DeleteUser(ctx *gin.Context) (*DeleteUserResponse, error)
+	RequestDataExport(ctx *gin.Context) (*DataExportResponse, error)
+	GetDataExport(ctx *gin.Context) (*DataExportResponse, error)
+	BlockUser(ctx *gin.Context, req BlockUserRequest) error
+	UnblockUser(ctx *gin.Context) error
+	GetBlockedUsers(ctx *gin.Context) (*GetBlockedUsersResponse, error)
+	VerifyGeofence(ctx *gin.Context) (*VerifyGeofenceResponse, error)
+	GetGeofenceHistory(ctx *gin.Context) (*GetGeofenceHistoryResponse, error)
+	GetNotificationPreferences(ctx *gin.Context) (*NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx *gin.Context, req NotificationPreferences) (*NotificationPreferences, error)
 }
@@ -0,0 +1,120 @@
+package user_handler
+
+import (
+	"ketalk-api/common"
+	user_manager "ketalk-api/pkg/manager/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationPreferences struct {
+	Channels   NotificationChannels `json:"channels"`
+	Events     NotificationEvents   `json:"events"`
+	QuietHours *QuietHours          `json:"quietHours"`
+}
+
+type NotificationChannels struct {
+	Push  bool `json:"push"`
+	Email bool `json:"email"`
+	InApp bool `json:"inApp"`
+}
+
+type NotificationEvents struct {
+	NewMessage     bool `json:"newMessage"`
+	PriceDrop      bool `json:"priceDrop"`
+	ItemSold       bool `json:"itemSold"`
+	ReviewReceived bool `json:"reviewReceived"`
+}
+
+// QuietHours are sent as e.g. {"start": "22:00", "end": "07:00", "timezone": "Asia/Tashkent"}
+type QuietHours struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+func (h *HttpHandler) GetNotificationPreferences(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	resp, err := h.handler.GetNotificationPreferences(ctx)
+	return resp, err
+}
+
+func (h *HttpHandler) UpdateNotificationPreferences(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req NotificationPreferences
+	if err := ctx.BindJSON(&req); err != nil {
+		return nil, err
+	}
+	resp, err := h.handler.UpdateNotificationPreferences(ctx, req)
+	return resp, err
+}
+
+func (h *handler) GetNotificationPreferences(ctx *gin.Context) (*NotificationPreferences, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	preferences, err := h.manager.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toNotificationPreferences(preferences), nil
+}
+
+func (h *handler) UpdateNotificationPreferences(ctx *gin.Context, req NotificationPreferences) (*NotificationPreferences, error) {
+	userID, err := common.GetUserId(ctx.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	update := user_manager.UpdateNotificationPreferencesRequest{
+		UserID: userID,
+		NotificationPreferences: user_manager.NotificationPreferences{
+			Channels: user_manager.NotificationChannels{
+				Push:  req.Channels.Push,
+				Email: req.Channels.Email,
+				InApp: req.Channels.InApp,
+			},
+			Events: user_manager.NotificationEvents{
+				NewMessage:     req.Events.NewMessage,
+				PriceDrop:      req.Events.PriceDrop,
+				ItemSold:       req.Events.ItemSold,
+				ReviewReceived: req.Events.ReviewReceived,
+			},
+		},
+	}
+	if req.QuietHours != nil {
+		update.QuietHours = &user_manager.QuietHours{
+			Start:    req.QuietHours.Start,
+			End:      req.QuietHours.End,
+			Timezone: req.QuietHours.Timezone,
+		}
+	}
+	preferences, err := h.manager.UpdateNotificationPreferences(ctx, update)
+	if err != nil {
+		return nil, err
+	}
+	return toNotificationPreferences(preferences), nil
+}
+
+func toNotificationPreferences(preferences *user_manager.NotificationPreferences) *NotificationPreferences {
+	resp := &NotificationPreferences{
+		Channels: NotificationChannels{
+			Push:  preferences.Channels.Push,
+			Email: preferences.Channels.Email,
+			InApp: preferences.Channels.InApp,
+		},
+		Events: NotificationEvents{
+			NewMessage:     preferences.Events.NewMessage,
+			PriceDrop:      preferences.Events.PriceDrop,
+			ItemSold:       preferences.Events.ItemSold,
+			ReviewReceived: preferences.Events.ReviewReceived,
+		},
+	}
+	if preferences.QuietHours != nil {
+		resp.QuietHours = &QuietHours{
+			Start:    preferences.QuietHours.Start,
+			End:      preferences.QuietHours.End,
+			Timezone: preferences.QuietHours.Timezone,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,36 @@
+package user_handler
+
+import (
+	"ketalk-api/common"
+	user_manager "ketalk-api/pkg/manager/user"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SetRoleRequest struct {
+	Role common.Role `json:"role"`
+}
+
+func (h *HttpHandler) SetRole(ctx *gin.Context, r *http.Request) (interface{}, error) {
+	var req SetRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		return nil, err
+	}
+	if err := h.handler.SetRole(ctx, req); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (h *handler) SetRole(ctx *gin.Context, req SetRoleRequest) error {
+	userID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return err
+	}
+	return h.manager.SetRole(ctx, user_manager.SetRoleRequest{
+		UserID: userID,
+		Role:   req.Role,
+	})
+}
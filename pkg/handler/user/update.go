@@ -10,8 +10,11 @@ import (
 )
 
 type UpdateUserRequest struct {
-	Name  *string `json:"name"`
+	Name *string `json:"name"`
+	// Image is the imageName from /user/presigned-url, set once the upload is done
 	Image *string `json:"image"`
+	// Locale is one of uz, ru or en, an empty string follows the Accept-Language of the device again
+	Locale *common.Locale `json:"locale"`
 }
 
 type UpdateUserResponse struct {
@@ -19,6 +22,7 @@ type UpdateUserResponse struct {
 	Username string    `json:"username"`
 	Email    string    `json:"email"`
 	Image    *string   `json:"image"`
+	Locale   string    `json:"locale"`
 }
 
 func (h *HttpHandler) UpdateUser(ctx *gin.Context, r *http.Request) (interface{}, error) {
@@ -39,6 +43,7 @@ func (h *handler) UpdateUser(ctx *gin.Context, req UpdateUserRequest) (*UpdateUs
 		UserID: userID,
 		Name:   req.Name,
 		Image:  req.Image,
+		Locale: req.Locale,
 	})
 	if err != nil {
 		return nil, err
@@ -48,5 +53,6 @@ func (h *handler) UpdateUser(ctx *gin.Context, req UpdateUserRequest) (*UpdateUs
 		Username: user.Username,
 		Email:    user.Email,
 		Image:    user.Image,
+		Locale:   string(user.Locale),
 	}, nil
 }
@@ -34,11 +34,27 @@ func NewHttpHandler(ctx context.Context, h UserHandler, middleware common.Middle
 func (c *HttpHandler) Init(ctx context.Context, router *gin.Engine) {
 	routes := map[string]map[string]common.HandlerFunc{
 		"GET": {
-			"":               c.middleware.HandlerWithAuth(c.GetUser),
-			"/presigned-url": c.middleware.HandlerWithAuth(c.GetPresignedUrl),
+			"":                  c.middleware.HandlerWithAuth(c.GetUser),
+			"/presigned-url":    c.middleware.HandlerWithAuth(c.GetPresignedUrl),
+			"/export/:id":       c.middleware.HandlerWithAuth(c.GetDataExport),
+			"/blocks":           c.middleware.HandlerWithAuth(c.GetBlockedUsers),
+			"/geofence/history": c.middleware.HandlerWithAuth(c.GetGeofenceHistory),
+			"/preferences":      c.middleware.HandlerWithAuth(c.GetNotificationPreferences),
+			"/:id":              c.middleware.HandlerWithAuth(c.GetPublicProfile),
+		},
+		"POST": {
+			"/export":          c.middleware.HandlerWithAuth(c.RequestDataExport),
+			"/blocks":          c.middleware.HandlerWithAuth(c.BlockUser),
+			"/geofence/verify": c.middleware.HandlerWithAuth(c.VerifyGeofence),
 		},
 		"PUT": {
-			"": c.middleware.HandlerWithAuth(c.UpdateUser),
+			"":             c.middleware.HandlerWithAuth(c.UpdateUser),
+			"/preferences": c.middleware.HandlerWithAuth(c.UpdateNotificationPreferences),
+			"/:id/role":    c.middleware.HandlerWithRole(common.RoleAdmin, c.SetRole),
+		},
+		"DELETE": {
+			"":            c.middleware.HandlerWithAuth(c.DeleteUser),
+			"/blocks/:id": c.middleware.HandlerWithAuth(c.UnblockUser),
 		},
 	}
 	for method, route := range routes {
@@ -0,0 +1,126 @@
+package auth_manager
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"ketalk-api/mailer"
+	"ketalk-api/pkg/manager/auth/repository"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	emailVerificationCodeLength   = 6
+	emailVerificationCodeTTL      = 15 * time.Minute
+	emailVerificationResendWindow = time.Minute
+	maxEmailVerificationAttempts  = 5
+)
+
+func (m *authManager) VerifyEmail(ctx context.Context, req VerifyEmailRequest) error {
+	user, err := m.userPort.GetUser(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	// a new code is sent on every resend, the account counter limits the guesses across them
+	attempt := loginAttempt{Account: req.UserID.String(), IP: req.IP}
+	if err := m.limitLoginAttempt(ctx, attempt, func() error {
+		return m.checkVerificationCode(ctx, req.UserID, req.Code)
+	}); err != nil {
+		return err
+	}
+
+	if err := m.userPort.SetEmailVerified(ctx, req.UserID); err != nil {
+		return err
+	}
+	return m.emailVerificationRepository.DeleteUserVerifications(ctx, req.UserID)
+}
+
+func (m *authManager) checkVerificationCode(ctx context.Context, userID uuid.UUID, code string) error {
+	verification, err := m.emailVerificationRepository.GetActive(ctx, userID)
+	if err == gorm.ErrRecordNotFound {
+		return ErrVerificationCodeNotFound
+	} else if err != nil {
+		return err
+	}
+	if verification.ExpiresAt.Before(time.Now()) {
+		return ErrVerificationCodeExpired
+	}
+	if verification.Attempts >= maxEmailVerificationAttempts {
+		return ErrTooManyVerificationAttempts
+	}
+	if subtle.ConstantTimeCompare([]byte(hashVerificationCode(code)), []byte(verification.CodeHash)) != 1 {
+		if err := m.emailVerificationRepository.IncrementAttempts(ctx, verification.ID); err != nil {
+			return err
+		}
+		return ErrInvalidVerificationCode
+	}
+	return nil
+}
+
+func (m *authManager) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := m.userPort.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	verification, err := m.emailVerificationRepository.GetActive(ctx, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if err == nil && time.Since(verification.CreatedAt) < emailVerificationResendWindow {
+		return ErrVerificationCodeRecentlySent
+	}
+	return m.sendVerificationCode(ctx, userID, user.Email)
+}
+
+func (m *authManager) sendVerificationCode(ctx context.Context, userID uuid.UUID, email string) error {
+	code, err := generateVerificationCode()
+	if err != nil {
+		return err
+	}
+	// only the latest code is valid
+	if err := m.emailVerificationRepository.DeleteUserVerifications(ctx, userID); err != nil {
+		return err
+	}
+	if err := m.emailVerificationRepository.Create(ctx, &repository.EmailVerification{
+		UserID:    userID,
+		Email:     email,
+		CodeHash:  hashVerificationCode(code),
+		ExpiresAt: time.Now().Add(emailVerificationCodeTTL),
+	}); err != nil {
+		return err
+	}
+	return m.mailer.Send(ctx, mailer.Mail{
+		To:      email,
+		Subject: "Verify your email",
+		Body:    fmt.Sprintf("Your verification code is %s. It expires in %d minutes.", code, int(emailVerificationCodeTTL.Minutes())),
+	})
+}
+
+func generateVerificationCode() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(emailVerificationCodeLength), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", emailVerificationCodeLength, n), nil
+}
+
+func hashVerificationCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,102 @@
+package auth_manager
+
+import (
+	"context"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/pkg/provider/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (m *authManager) GetIdentities(ctx context.Context, userID uuid.UUID) (*GetIdentitiesResponse, error) {
+	user, err := m.userPort.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := m.userPort.GetUserIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &GetIdentitiesResponse{
+		HasPassword: user.Password != nil,
+		Phone:       user.Phone,
+		Identities:  make([]Identity, len(identities)),
+	}
+	for i, identity := range identities {
+		resp.Identities[i] = Identity{
+			Provider: identity.Provider,
+			Email:    identity.Email,
+			LinkedAt: identity.LinkedAt,
+		}
+	}
+	return resp, nil
+}
+
+func (m *authManager) LinkIdentity(ctx context.Context, req LinkIdentityRequest) error {
+	if req.ProviderToken == nil || req.ProviderToken.IdToken == "" {
+		return ErrInvalidProviderToken
+	}
+	providerToken := toModelProviderToken(req.ProviderToken)
+	userDetails, err := m.provider.VerifyIDToken(ctx, &providerToken)
+	if err != nil {
+		return err
+	}
+
+	identity, err := m.userPort.GetIdentity(ctx, string(providerToken.ProviderName), userDetails.ExternalID)
+	if err == nil {
+		if identity.UserID == req.UserID {
+			return nil
+		}
+		return ErrIdentityLinkedToOtherUser
+	} else if err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	identities, err := m.userPort.GetUserIdentities(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	for _, identity := range identities {
+		if identity.Provider == string(providerToken.ProviderName) {
+			return ErrProviderAlreadyLinked
+		}
+	}
+
+	return m.userPort.LinkIdentity(ctx, port.Identity{
+		UserID:     req.UserID,
+		Provider:   string(providerToken.ProviderName),
+		ExternalID: userDetails.ExternalID,
+		Email:      normalizeEmail(userDetails.Email),
+	})
+}
+
+func (m *authManager) UnlinkIdentity(ctx context.Context, userID uuid.UUID, provider string) error {
+	err := m.userPort.UnlinkIdentity(ctx, userID, provider)
+	if err == gorm.ErrRecordNotFound {
+		return ErrIdentityNotFound
+	}
+	return err
+}
+
+// getIdentityUser returns gorm.ErrRecordNotFound when the identity is not linked to any user
+func (m *authManager) getIdentityUser(ctx context.Context, providerName model.ProviderName, externalID string) (*port.User, error) {
+	identity, err := m.userPort.GetIdentity(ctx, string(providerName), externalID)
+	if err != nil {
+		return nil, err
+	}
+	return m.userPort.GetUser(ctx, identity.UserID)
+}
+
+func toModelProviderToken(token *ProviderToken) model.ProviderToken {
+	return model.ProviderToken{
+		ProviderName: token.ProviderName,
+		IdToken:      token.IdToken,
+		Nonce:        token.Nonce,
+		Token: model.Token{
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
+		},
+		FirstName: token.FirstName,
+	}
+}
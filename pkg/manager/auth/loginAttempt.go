@@ -0,0 +1,233 @@
+package auth_manager
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/common"
+	"ketalk-api/pkg/manager/auth/repository"
+	"log"
+	"time"
+)
+
+const (
+	// loginFailureWindow is how long failures are counted after the first one
+	loginFailureWindow   = 15 * time.Minute
+	loginLockoutDuration = 15 * time.Minute
+	loginBaseDelay       = time.Second
+	loginMaxDelay        = time.Minute
+	// maxLockoutsPage is the page size of the lockout audit when none is requested
+	maxLockoutsPage = 100
+)
+
+const (
+	LoginScopeEmail  = "email"
+	LoginScopeIP     = "ip"
+	LoginScopeDevice = "device"
+	// LoginScopeAccount counts the attempts on a user across login methods and verification codes
+	LoginScopeAccount = "account"
+	LoginScopePhone   = "phone"
+	// LoginScopeMfa counts the second factor failures of a user
+	LoginScopeMfa = "mfa"
+)
+
+type loginLimit struct {
+	// freeFailures are allowed before the delays start
+	freeFailures int64
+	// maxFailures locks the scope for loginLockoutDuration
+	maxFailures int64
+}
+
+// the ip is shared behind NATs, so it gets more room than an account or a device
+var loginLimits = map[string]loginLimit{
+	LoginScopeEmail:   {freeFailures: 3, maxFailures: 10},
+	LoginScopeIP:      {freeFailures: 10, maxFailures: 50},
+	LoginScopeDevice:  {freeFailures: 3, maxFailures: 10},
+	LoginScopeAccount: {freeFailures: 3, maxFailures: 10},
+	LoginScopePhone:   {freeFailures: 3, maxFailures: 10},
+}
+
+// loginAttempt identifies who is trying to login, empty values are not counted
+type loginAttempt struct {
+	Email    string
+	IP       string
+	DeviceID string
+	// Account is the id of the user, when it is known before the credentials are checked
+	Account string
+	Phone   string
+}
+
+type loginAttemptKey struct {
+	scope string
+	value string
+}
+
+func (k loginAttemptKey) String() string {
+	return fmt.Sprintf("%s:%s", k.scope, k.value)
+}
+
+func (a loginAttempt) keys() []loginAttemptKey {
+	var keys []loginAttemptKey
+	for _, key := range []loginAttemptKey{
+		{LoginScopeEmail, a.Email},
+		{LoginScopeIP, a.IP},
+		{LoginScopeDevice, a.DeviceID},
+		{LoginScopeAccount, a.Account},
+		{LoginScopePhone, a.Phone},
+	} {
+		if key.value != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// limitLoginAttempt runs the check of the credentials as a counted attempt. The attempt is counted before
+// the check, so parallel attempts can not all get in before a lockout is set, and taken back when it succeeds.
+func (m *authManager) limitLoginAttempt(ctx context.Context, attempt loginAttempt, check func() error) error {
+	failures, err := m.startLoginAttempt(ctx, attempt)
+	if err != nil {
+		return err
+	}
+	if err := check(); err != nil {
+		if delayErr := m.delayLoginAttempts(ctx, attempt, failures); delayErr != nil {
+			return delayErr
+		}
+		return err
+	}
+	m.resetLoginFailures(ctx, attempt)
+	return nil
+}
+
+// startLoginAttempt rejects the attempt while any of its keys is delayed or locked out, otherwise it counts
+// the attempt as a failure for every key and locks out the keys that went over their maximum.
+func (m *authManager) startLoginAttempt(ctx context.Context, attempt loginAttempt) ([]int64, error) {
+	keys := attempt.keys()
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = key.String()
+	}
+	retryAfter, err := m.redis.GetLoginLock(ctx, redisKeys...)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		return nil, &common.RetryAfterError{
+			Err:        common.ErrTooManyAttempts,
+			RetryAfter: retryAfter,
+		}
+	}
+
+	failures := make([]int64, len(keys))
+	lockedOut := false
+	for i, key := range keys {
+		failures[i], err = m.redis.RecordLoginFailure(ctx, key.String(), loginFailureWindow)
+		if err != nil {
+			return nil, err
+		}
+		if failures[i] > loginLimits[key.scope].maxFailures {
+			if err := m.lockLogin(ctx, key, failures[i], loginLockoutDuration); err != nil {
+				return nil, err
+			}
+			lockedOut = true
+		}
+	}
+	if lockedOut {
+		return nil, &common.RetryAfterError{
+			Err:        common.ErrTooManyAttempts,
+			RetryAfter: loginLockoutDuration,
+		}
+	}
+	return failures, nil
+}
+
+// delayLoginAttempts is called after a failed attempt, each failure over the free ones
+// doubles the delay before the next attempt
+func (m *authManager) delayLoginAttempts(ctx context.Context, attempt loginAttempt, failures []int64) error {
+	for i, key := range attempt.keys() {
+		limit := loginLimits[key.scope]
+		if failures[i] > limit.freeFailures {
+			if err := m.redis.LockLogin(ctx, key.String(), loginDelay(failures[i]-limit.freeFailures)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lockLogin locks the key out and records the lockout in the audit
+func (m *authManager) lockLogin(ctx context.Context, key loginAttemptKey, failures int64, duration time.Duration) error {
+	if err := m.redis.LockLogin(ctx, key.String(), duration); err != nil {
+		return err
+	}
+	// the counter starts over, so the next lockout is recorded once more failures add up
+	if err := m.redis.ResetLoginFailures(ctx, key.String()); err != nil {
+		return err
+	}
+	lockout := &repository.LoginLockout{
+		Scope:       key.scope,
+		Value:       key.value,
+		Failures:    failures,
+		LockedUntil: time.Now().Add(duration),
+	}
+	if err := m.loginLockoutRepository.Create(ctx, lockout); err != nil {
+		// the lockout is in place already, only the audit is missing
+		log.Printf("failed to audit login lockout of %s: %v\n", key, err)
+	}
+	return nil
+}
+
+// resetLoginFailures is called after a successful attempt, the ip only gets the attempt back as it is shared
+func (m *authManager) resetLoginFailures(ctx context.Context, attempt loginAttempt) {
+	var keys []string
+	for _, key := range attempt.keys() {
+		if key.scope == LoginScopeIP {
+			if err := m.redis.TakeBackLoginFailure(ctx, key.String()); err != nil {
+				log.Printf("failed to take back login attempt: %v\n", err)
+			}
+			continue
+		}
+		keys = append(keys, key.String())
+	}
+	if err := m.redis.ResetLoginFailures(ctx, keys...); err != nil {
+		log.Printf("failed to reset login failures: %v\n", err)
+	}
+}
+
+func loginDelay(delayedFailures int64) time.Duration {
+	delay := loginBaseDelay
+	for i := int64(1); i < delayedFailures && delay < loginMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > loginMaxDelay {
+		return loginMaxDelay
+	}
+	return delay
+}
+
+func (m *authManager) GetLoginLockouts(ctx context.Context, req GetLoginLockoutsRequest) ([]LoginLockout, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxLockoutsPage {
+		limit = maxLockoutsPage
+	}
+	lockouts, err := m.loginLockoutRepository.GetLockouts(ctx, repository.LoginLockoutFilter{
+		Scope: req.Scope,
+		Value: req.Value,
+		Since: req.Since,
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]LoginLockout, len(lockouts))
+	for i, lockout := range lockouts {
+		resp[i] = LoginLockout{
+			ID:          lockout.ID,
+			Scope:       lockout.Scope,
+			Value:       lockout.Value,
+			Failures:    lockout.Failures,
+			LockedAt:    lockout.CreatedAt,
+			LockedUntil: lockout.LockedUntil,
+		}
+	}
+	return resp, nil
+}
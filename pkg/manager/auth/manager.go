@@ -5,133 +5,348 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"ketalk-api/common"
 	"ketalk-api/jwt"
+	"ketalk-api/mailer"
+	"ketalk-api/password"
+	auth_redis "ketalk-api/pkg/manager/auth/redis"
 	"ketalk-api/pkg/manager/auth/repository"
 	"ketalk-api/pkg/manager/port"
 	"ketalk-api/pkg/provider"
 	"ketalk-api/pkg/provider/model"
+	"ketalk-api/sms"
+	"log"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// defaultUsername is used when the provider shares neither a name nor a readable email
+const defaultUsername = "user"
+
 type Token struct {
 	AuthToken    string
 	RefreshToken string
 }
 
 type authManager struct {
-	authRepository repository.Repository
-	userPort       port.UserPort
-	geofencePort   port.GeofencePort
-	provider       provider.ProviderClient
-	jwtConfig      jwt.Config
+	authRepository              repository.Repository
+	emailVerificationRepository repository.EmailVerificationRepository
+	loginLockoutRepository      repository.LoginLockoutRepository
+	totpRepository              repository.TotpRepository
+	userPort                    port.UserPort
+	geofencePort                port.GeofencePort
+	provider                    provider.ProviderClient
+	mailer                      mailer.Mailer
+	smsSender                   sms.SmsSender
+	redis                       auth_redis.RedisClient
+	jwtConfig                   jwt.Config
 }
 
-func NewAuthManager(authRepository repository.Repository, userPort port.UserPort, geofencePort port.GeofencePort, provider provider.ProviderClient, jwtConfig jwt.Config) AuthManager {
+func NewAuthManager(authRepository repository.Repository, emailVerificationRepository repository.EmailVerificationRepository, loginLockoutRepository repository.LoginLockoutRepository, totpRepository repository.TotpRepository, userPort port.UserPort, geofencePort port.GeofencePort, provider provider.ProviderClient, mailer mailer.Mailer, smsSender sms.SmsSender, redis auth_redis.RedisClient, jwtConfig jwt.Config) AuthManager {
 	return &authManager{
 		authRepository,
+		emailVerificationRepository,
+		loginLockoutRepository,
+		totpRepository,
 		userPort,
 		geofencePort,
 		provider,
+		mailer,
+		smsSender,
+		redis,
 		jwtConfig,
 	}
 }
 
 func (m *authManager) SignupOrLogin(ctx context.Context, req SignupOrLoginRequest) (*SignupOrLoginResponse, error) {
-	// create user
-	providerToken := model.ProviderToken{
-		GoogleToken: &model.GoogleToken{
-			IdToken: req.ProviderToken.GoogleToken.IdToken,
-			Token: model.Token{
-				AccessToken:  req.ProviderToken.GoogleToken.AccessToken,
-				RefreshToken: req.ProviderToken.GoogleToken.RefreshToken,
-			},
-		},
-	}
-
-	userDetails, err := m.provider.VerifyIDToken(ctx, &providerToken)
-	var username string
-	var email string
+	if req.ProviderToken == nil || req.ProviderToken.IdToken == "" {
+		return nil, fmt.Errorf("invalid request")
+	}
+	providerToken := toModelProviderToken(req.ProviderToken)
+
+	// the email is only known once the token is verified
+	attempt := loginAttempt{IP: req.IP, DeviceID: req.DeviceID}
+	var userDetails *model.ProviderUserDetails
+	if err := m.limitLoginAttempt(ctx, attempt, func() (err error) {
+		userDetails, err = m.provider.VerifyIDToken(ctx, &providerToken)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	// a linked identity wins over the email, which may have changed at the provider
+	user, err := m.getIdentityUser(ctx, providerToken.ProviderName, userDetails.ExternalID)
+	if err == gorm.ErrRecordNotFound {
+		user, err = m.createOrGetProviderUser(ctx, providerToken.ProviderName, userDetails, req.Location)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return m.login(ctx, user, req.DeviceID, req.DeviceOS)
+}
+
+// createOrGetProviderUser matches the user by the email when it is verified on both sides or signs them up,
+// the identity is linked so the next login finds the user by the external id.
+func (m *authManager) createOrGetProviderUser(ctx context.Context, providerName model.ProviderName, userDetails *model.ProviderUserDetails, location common.Location) (*port.User, error) {
+	geofence, err := m.geofencePort.GetGeofenceByLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
 	var image *string
-	if err == nil {
-		username = userDetails.FirstName
-		email = userDetails.Email
+	if userDetails.Image != "" {
 		image = &userDetails.Image
-	} else if req.SignUpDetails != nil {
-		username = req.SignUpDetails.UserName
-		email = req.SignUpDetails.Email
-	} else {
-		return nil, fmt.Errorf("invalid request")
+	}
+	email := normalizeEmail(userDetails.Email)
+	// provider has already verified the email
+	user, err := m.userPort.CreateOrGetUser(ctx, port.CreateOrGetUserRequest{
+		Username:      providerUsername(userDetails),
+		Email:         email,
+		Image:         image,
+		EmailVerified: true,
+		GeofenceID:    geofence.ID,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	createUserReq := port.CreateOrGetUserRequest{
-		Username: username,
-		Email:    email,
-		Image:    image,
+	if err := m.userPort.LinkIdentity(ctx, port.Identity{
+		UserID:     user.ID,
+		Provider:   string(providerName),
+		ExternalID: userDetails.ExternalID,
+		Email:      email,
+	}); err != nil {
+		return nil, err
 	}
-	if req.SignUpDetails != nil {
-		createUserReq.Password = &req.SignUpDetails.Password
+	return user, nil
+}
+
+func (m *authManager) Signup(ctx context.Context, req SignupRequest) (*SignupOrLoginResponse, error) {
+	email := normalizeEmail(req.Email)
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, ErrInvalidEmail
+	}
+	if _, err := m.userPort.GetUserByEmail(ctx, email); err == nil {
+		return nil, ErrEmailAlreadyRegistered
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if err := password.Validate(req.Password); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := password.Hash(req.Password)
+	if err != nil {
+		return nil, err
 	}
 
 	geofence, err := m.geofencePort.GetGeofenceByLocation(ctx, req.Location)
 	if err != nil {
 		return nil, err
 	}
-	createUserReq.GeofenceID = geofence.ID
 
-	user, err := m.userPort.CreateOrGetUser(ctx, createUserReq)
+	user, err := m.userPort.CreateOrGetUser(ctx, port.CreateOrGetUserRequest{
+		Username:   req.UserName,
+		Email:      email,
+		Password:   &hashedPassword,
+		GeofenceID: geofence.ID,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if user.Password != nil && req.SignUpDetails != nil && *user.Password != req.SignUpDetails.Password {
-		return nil, fmt.Errorf("invalid password")
+	if err := m.sendVerificationCode(ctx, user.ID, user.Email); err != nil {
+		// user can still request a new code
+		log.Printf("failed to send verification code to user %s: %v\n", user.ID, err)
+	}
+
+	return m.login(ctx, user, req.DeviceID, req.DeviceOS)
+}
+
+func (m *authManager) Login(ctx context.Context, req LoginRequest) (*SignupOrLoginResponse, error) {
+	email := normalizeEmail(req.Email)
+	// the email is the account here, unknown emails are counted as well so they can not be told apart by the limits
+	attempt := loginAttempt{Email: email, IP: req.IP, DeviceID: req.DeviceID}
+	var user *port.User
+	if err := m.limitLoginAttempt(ctx, attempt, func() (err error) {
+		user, err = m.checkCredentials(ctx, email, req.Password)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return m.login(ctx, user, req.DeviceID, req.DeviceOS)
+}
+
+func (m *authManager) checkCredentials(ctx context.Context, email string, plain string) (*port.User, error) {
+	user, err := m.userPort.GetUserByEmail(ctx, email)
+	if err == gorm.ErrRecordNotFound {
+		// hashing anyway keeps the response time from telling which emails are registered
+		password.VerifyDummy(plain)
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
 	}
+	// accounts created through a provider have no password
+	if user.Password == nil {
+		password.VerifyDummy(plain)
+		return nil, ErrInvalidCredentials
+	}
+	if err := m.verifyPassword(ctx, user, plain); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
 
-	// create token
-	token, err := m.issueTokens(ctx, user.ID, nil, req.DeviceID, req.DeviceOS)
+func (m *authManager) login(ctx context.Context, user *port.User, deviceID string, deviceOS string) (*SignupOrLoginResponse, error) {
+	if user.IsSuspended(time.Now()) {
+		return nil, common.ErrAccountSuspended
+	}
+	enabled, err := m.isTotpEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return m.createMfaChallenge(ctx, user, deviceID, deviceOS)
+	}
+	return m.issueLogin(ctx, user, deviceID, deviceOS)
+}
+
+func (m *authManager) issueLogin(ctx context.Context, user *port.User, deviceID string, deviceOS string) (*SignupOrLoginResponse, error) {
+	// a new login replaces the previous session of the device
+	if err := m.authRepository.DeleteDeviceRefreshTokens(ctx, user.ID, deviceID); err != nil {
+		return nil, err
+	}
+	token, err := m.issueTokens(ctx, user.ID, user.Role, nil, deviceID, deviceOS)
 	if err != nil {
 		return nil, err
 	}
 
-	// return
 	return &SignupOrLoginResponse{
 		Id:           user.ID,
 		UserName:     user.Username,
 		Email:        user.Email,
 		Image:        user.Image,
+		Verified:     user.Verified,
 		AuthToken:    token.AuthToken,
 		RefreshToken: token.RefreshToken,
 	}, nil
 }
 
-func (h *authManager) issueTokens(ctx context.Context, userId uuid.UUID, oldToken *string, deviceID string, deviceOS string) (Token, error) {
-	tokens, err := h.createTokens(ctx, userId)
+// verifyPassword checks the given password against the stored one and
+// upgrades plaintext or outdated hashes after a successful match.
+func (m *authManager) verifyPassword(ctx context.Context, user *port.User, plain string) error {
+	ok, needsRehash, err := password.Verify(plain, *user.Password)
 	if err != nil {
-		return tokens, err
+		return err
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+	if !needsRehash {
+		return nil
+	}
+	hashedPassword, err := password.Hash(plain)
+	if err != nil {
+		return err
+	}
+	if err := m.userPort.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		// login still succeeds, we will retry on the next one
+		log.Printf("failed to rehash password for user %s: %v\n", user.ID, err)
 	}
+	return nil
+}
 
-	if oldToken != nil {
-		err = h.authRepository.DeleteRefreshToken(ctx, *oldToken)
-		if err != nil {
-			return tokens, err
+func (m *authManager) RefreshAccessToken(ctx context.Context, req RefreshAccessTokenRequest) (*RefreshAccessTokenResponse, error) {
+	deviceRefreshToken, err := m.authRepository.GetRefreshToken(ctx, req.RefreshToken)
+	if err == gorm.ErrRecordNotFound {
+		// an already rotated token being used again means it has leaked,
+		// so we revoke every token issued for that device
+		rotated, err := m.authRepository.GetRotatedRefreshToken(ctx, req.RefreshToken)
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidRefreshToken
+		} else if err != nil {
+			return nil, err
+		}
+		if err := m.revokeDeviceSession(ctx, rotated.UserId, rotated.DeviceID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	} else if err != nil {
+		return nil, err
+	}
+
+	if deviceRefreshToken.DeviceID != req.DeviceID {
+		// another device holding the token means it has leaked as well
+		if err := m.revokeDeviceSession(ctx, deviceRefreshToken.UserId, deviceRefreshToken.DeviceID); err != nil {
+			return nil, err
 		}
+		return nil, ErrRefreshTokenDeviceMismatch
+	}
+	if deviceRefreshToken.RefreshTokenExpiryAt.Before(time.Now()) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	// the role is read again, so changes are picked up on the next refresh
+	user, err := m.userPort.GetUser(ctx, deviceRefreshToken.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := m.issueTokens(ctx, user.ID, user.Role, deviceRefreshToken, deviceRefreshToken.DeviceID, deviceRefreshToken.DeviceOS)
+	if err == gorm.ErrRecordNotFound {
+		// token was rotated concurrently by another request
+		if err := m.revokeDeviceSession(ctx, deviceRefreshToken.UserId, deviceRefreshToken.DeviceID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &RefreshAccessTokenResponse{
+		AuthToken:    token.AuthToken,
+		RefreshToken: token.RefreshToken,
+	}, nil
+}
+
+func (h *authManager) issueTokens(ctx context.Context, userId uuid.UUID, role common.Role, oldToken *repository.DeviceRefreshToken, deviceID string, deviceOS string) (Token, error) {
+	tokens, err := h.createTokens(ctx, userId, role, deviceID)
+	if err != nil {
+		return tokens, err
 	}
+
+	now := time.Now()
 	input := repository.DeviceRefreshToken{
 		DeviceID:             deviceID,
 		DeviceOS:             deviceOS,
 		UserId:               userId,
 		RefreshToken:         tokens.RefreshToken,
-		RefreshTokenExpiryAt: time.Now().Add(h.jwtConfig.RefreshTokenExpiryDuration),
+		RefreshTokenExpiryAt: now.Add(h.jwtConfig.RefreshTokenExpiryDuration),
+		SessionCreatedAt:     now,
+	}
+	if oldToken != nil {
+		input.SessionCreatedAt = oldToken.SessionCreatedAt
+		err = h.authRepository.RotateRefreshToken(ctx, oldToken.RefreshToken, &input)
+		return tokens, err
 	}
 	err = h.authRepository.AddRefreshToken(ctx, &input)
 	return tokens, err
 }
 
-func (h *authManager) createTokens(_ context.Context, userId uuid.UUID) (Token, error) {
+func (h *authManager) createTokens(_ context.Context, userId uuid.UUID, role common.Role, deviceID string) (Token, error) {
+	if !role.IsValid() {
+		role = common.RoleUser
+	}
 	authToken, err := jwt.IssueToken(h.jwtConfig, userId, map[string]interface{}{
-		"id": userId,
+		"id":        userId,
+		"device_id": deviceID,
+		"role":      role,
 	})
 	if err != nil {
 		return Token{}, fmt.Errorf("could not issue JWT for %v, err: %+v", userId, err)
@@ -158,6 +373,58 @@ func GenerateRandomHex(length int) (string, error) {
 	return hex.EncodeToString(randBytes), nil
 }
 
+func (m *authManager) JWKS() jwt.JSONWebKeySet {
+	return m.jwtConfig.JWKS()
+}
+
 func (m *authManager) Logout(ctx context.Context, req LogoutRequest) error {
-	return m.authRepository.DeleteRefreshToken(ctx, req.RefreshToken)
+	if err := m.authRepository.DeleteRefreshToken(ctx, req.UserID, req.RefreshToken); err != nil {
+		return err
+	}
+	if req.AccessTokenID == "" {
+		// issued before tokens had an id, it expires on its own
+		return nil
+	}
+	return m.redis.RevokeToken(ctx, req.AccessTokenID, req.AccessTokenExpiresAt)
+}
+
+// LogoutEverywhere revokes every refresh token and every access token issued so far for the user.
+func (m *authManager) LogoutEverywhere(ctx context.Context, userID uuid.UUID) error {
+	if err := m.authRepository.DeleteUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+	return m.revokeAccessTokens(ctx, userID)
+}
+
+// revokeDeviceSession ends the token family of a device whose refresh token has leaked,
+// the sessions of the other devices of the user are kept
+func (m *authManager) revokeDeviceSession(ctx context.Context, userID uuid.UUID, deviceID string) error {
+	if err := m.authRepository.DeleteDeviceRefreshTokens(ctx, userID, deviceID); err != nil {
+		return err
+	}
+	// access tokens issued from the leaked token may still be in use
+	return m.revokeDeviceAccessTokens(ctx, userID, deviceID)
+}
+
+func (m *authManager) revokeAccessTokens(ctx context.Context, userID uuid.UUID) error {
+	// access tokens older than their valid duration have expired anyway
+	return m.redis.RevokeTokensIssuedBefore(ctx, userID, time.Now(), m.jwtConfig.ValidDuration)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// providerUsername falls back to the email local part when the provider did not share a name,
+// unless the email is a private relay address which would leak nothing but random characters.
+func providerUsername(details *model.ProviderUserDetails) string {
+	if details.FirstName != "" {
+		return details.FirstName
+	}
+	if !details.IsPrivateEmail {
+		if localPart, _, ok := strings.Cut(details.Email, "@"); ok && localPart != "" {
+			return localPart
+		}
+	}
+	return defaultUsername
 }
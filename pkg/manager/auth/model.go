@@ -2,34 +2,32 @@ package auth_manager
 
 import (
 	"context"
+	"fmt"
 	"ketalk-api/common"
+	"ketalk-api/jwt"
+	"ketalk-api/password"
+	"ketalk-api/pkg/provider/model"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-type GoogleToken struct {
+type ProviderToken struct {
+	ProviderName model.ProviderName
 	IdToken      string
+	Nonce        string
 	AccessToken  string
 	RefreshToken string
-}
-
-type SignUpDetails struct {
-	UserName string
-	Email    string
-	Password string
-}
-
-type ProviderToken struct {
-	GoogleToken *GoogleToken
+	// FirstName is only sent by apple on the first login
+	FirstName string
 }
 
 type SignupOrLoginRequest struct {
 	ProviderToken *ProviderToken
 	DeviceID      string
 	DeviceOS      string
-
-	SignUpDetails *SignUpDetails
 	Location      common.Location
+	IP            string
 }
 
 type SignupOrLoginResponse struct {
@@ -37,16 +35,327 @@ type SignupOrLoginResponse struct {
 	UserName     string
 	Email        string
 	Image        *string
+	Verified     bool
 	AuthToken    string
 	RefreshToken string
+	// MfaChallenge is set instead of the tokens when the user has 2FA enabled,
+	// the tokens are issued by VerifyMfaChallenge
+	MfaChallenge          string
+	MfaChallengeExpiresAt time.Time
+}
+
+type SignupRequest struct {
+	UserName string
+	Email    string
+	Password string
+	DeviceID string
+	DeviceOS string
+	Location common.Location
+}
+
+type LoginRequest struct {
+	Email    string
+	Password string
+	DeviceID string
+	DeviceOS string
+	IP       string
+}
+
+type VerifyEmailRequest struct {
+	UserID uuid.UUID
+	Code   string
+	IP     string
 }
 
 type LogoutRequest struct {
-	UserID       uuid.UUID
+	UserID               uuid.UUID
+	RefreshToken         string
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+}
+
+type RequestPhoneOtpRequest struct {
+	Phone string
+}
+
+type VerifyPhoneOtpRequest struct {
+	Phone    string
+	Code     string
+	UserName string
+	DeviceID string
+	DeviceOS string
+	IP       string
+	// Location is only required when the phone number is not registered yet
+	Location *common.Location
+}
+
+type LinkPhoneRequest struct {
+	UserID uuid.UUID
+	Phone  string
+	Code   string
+	IP     string
+}
+
+type GetSessionsRequest struct {
+	UserID          uuid.UUID
+	CurrentDeviceID string
+}
+
+type Session struct {
+	DeviceID   string
+	DeviceOS   string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	Current    bool
+}
+
+type RefreshAccessTokenRequest struct {
+	RefreshToken string
+	DeviceID     string
+}
+
+type RefreshAccessTokenResponse struct {
+	AuthToken    string
 	RefreshToken string
 }
 
+type LinkIdentityRequest struct {
+	UserID        uuid.UUID
+	ProviderToken *ProviderToken
+}
+
+type Identity struct {
+	Provider string
+	Email    string
+	LinkedAt time.Time
+}
+
+// GetIdentitiesResponse lists every way the user can login
+type GetIdentitiesResponse struct {
+	HasPassword bool
+	Phone       *string
+	Identities  []Identity
+}
+
+type EnrollTotpResponse struct {
+	Secret string
+	// ProvisioningURI is shown as a QR code for the authenticator app
+	ProvisioningURI string
+}
+
+type TotpCodeRequest struct {
+	UserID uuid.UUID
+	Code   string
+}
+
+type BackupCodesResponse struct {
+	BackupCodes []string
+}
+
+type TotpStatus struct {
+	Enabled              bool
+	RemainingBackupCodes int64
+}
+
+type VerifyMfaChallengeRequest struct {
+	Challenge string
+	Code      string
+	IP        string
+}
+
+type GetLoginLockoutsRequest struct {
+	Scope string
+	Value string
+	Since *time.Time
+	Limit int
+}
+
+type LoginLockout struct {
+	ID          uuid.UUID
+	Scope       string
+	Value       string
+	Failures    int64
+	LockedAt    time.Time
+	LockedUntil time.Time
+}
+
+var (
+	ErrInvalidCredentials           = fmt.Errorf("%w: invalid email or password", common.ErrUnauthorized)
+	ErrInvalidEmail                 = fmt.Errorf("%w: invalid email", common.ErrInvalidInput)
+	ErrEmailAlreadyRegistered       = fmt.Errorf("%w: email is already registered", common.ErrConflict)
+	ErrEmailAlreadyVerified         = fmt.Errorf("%w: email is already verified", common.ErrConflict)
+	ErrVerificationCodeNotFound     = fmt.Errorf("%w: verification code not found", common.ErrInvalidInput)
+	ErrVerificationCodeExpired      = fmt.Errorf("%w: verification code expired", common.ErrInvalidInput)
+	ErrVerificationCodeRecentlySent = fmt.Errorf("%w: verification code was sent recently", common.ErrTooManyAttempts)
+	ErrInvalidVerificationCode      = fmt.Errorf("%w: invalid verification code", common.ErrInvalidInput)
+	ErrTooManyVerificationAttempts  = fmt.Errorf("%w: too many verification attempts", common.ErrTooManyAttempts)
+	ErrInvalidRefreshToken          = fmt.Errorf("%w: invalid refresh token", common.ErrUnauthorized)
+	ErrRefreshTokenExpired          = fmt.Errorf("%w: refresh token expired", common.ErrUnauthorized)
+	ErrRefreshTokenReused           = fmt.Errorf("%w: refresh token reused", common.ErrUnauthorized)
+	ErrRefreshTokenDeviceMismatch   = fmt.Errorf("%w: refresh token was issued for another device", common.ErrUnauthorized)
+	ErrSessionNotFound              = fmt.Errorf("%w: session not found", common.ErrNotFound)
+	ErrInvalidPhone                 = fmt.Errorf("%w: invalid phone number", common.ErrInvalidInput)
+	ErrPhoneAlreadyRegistered       = fmt.Errorf("%w: phone number is already registered", common.ErrConflict)
+	ErrLocationRequired             = fmt.Errorf("%w: location is required", common.ErrInvalidInput)
+	ErrUnknownSession               = fmt.Errorf("%w: current session is unknown, refresh the access token", common.ErrUnauthorized)
+	ErrInvalidProviderToken         = fmt.Errorf("%w: invalid provider token", common.ErrUnauthorized)
+	ErrIdentityLinkedToOtherUser    = fmt.Errorf("%w: identity is linked to another user", common.ErrConflict)
+	ErrProviderAlreadyLinked        = fmt.Errorf("%w: an identity of this provider is already linked", common.ErrConflict)
+	ErrIdentityNotFound             = fmt.Errorf("%w: identity not found", common.ErrNotFound)
+	ErrTotpAlreadyEnabled           = fmt.Errorf("%w: two-factor authentication is already enabled", common.ErrConflict)
+	ErrTotpNotEnrolled              = fmt.Errorf("%w: two-factor authentication enrollment was not started", common.ErrConflict)
+	ErrTotpNotEnabled               = fmt.Errorf("%w: two-factor authentication is not enabled", common.ErrConflict)
+	ErrInvalidTotpCode              = fmt.Errorf("%w: invalid two-factor authentication code", common.ErrUnauthorized)
+	ErrInvalidMfaChallenge          = fmt.Errorf("%w: invalid or expired two-factor authentication challenge", common.ErrUnauthorized)
+)
+
+func init() {
+	common.RegisterErrorMessages(ErrInvalidCredentials, map[common.Locale]string{
+		common.LocaleUz: "email yoki parol noto'g'ri",
+		common.LocaleRu: "неверный email или пароль",
+	})
+	common.RegisterErrorMessages(ErrInvalidEmail, map[common.Locale]string{
+		common.LocaleUz: "email noto'g'ri",
+		common.LocaleRu: "неверный email",
+	})
+	common.RegisterErrorMessages(ErrEmailAlreadyRegistered, map[common.Locale]string{
+		common.LocaleUz: "bu email allaqachon ro'yxatdan o'tgan",
+		common.LocaleRu: "этот email уже зарегистрирован",
+	})
+	common.RegisterErrorMessages(ErrEmailAlreadyVerified, map[common.Locale]string{
+		common.LocaleUz: "email allaqachon tasdiqlangan",
+		common.LocaleRu: "email уже подтверждён",
+	})
+	common.RegisterErrorMessages(ErrVerificationCodeNotFound, map[common.Locale]string{
+		common.LocaleUz: "tasdiqlash kodi topilmadi",
+		common.LocaleRu: "код подтверждения не найден",
+	})
+	common.RegisterErrorMessages(ErrVerificationCodeExpired, map[common.Locale]string{
+		common.LocaleUz: "tasdiqlash kodining muddati tugagan",
+		common.LocaleRu: "срок действия кода подтверждения истёк",
+	})
+	common.RegisterErrorMessages(ErrVerificationCodeRecentlySent, map[common.Locale]string{
+		common.LocaleUz: "tasdiqlash kodi yaqinda yuborilgan",
+		common.LocaleRu: "код подтверждения недавно отправлен",
+	})
+	common.RegisterErrorMessages(ErrInvalidVerificationCode, map[common.Locale]string{
+		common.LocaleUz: "tasdiqlash kodi noto'g'ri",
+		common.LocaleRu: "неверный код подтверждения",
+	})
+	common.RegisterErrorMessages(ErrTooManyVerificationAttempts, map[common.Locale]string{
+		common.LocaleUz: "tasdiqlash urinishlari soni juda ko'p",
+		common.LocaleRu: "слишком много попыток подтверждения",
+	})
+	common.RegisterErrorMessages(ErrInvalidRefreshToken, map[common.Locale]string{
+		common.LocaleUz: "yangilash tokeni noto'g'ri",
+		common.LocaleRu: "неверный токен обновления",
+	})
+	common.RegisterErrorMessages(ErrRefreshTokenExpired, map[common.Locale]string{
+		common.LocaleUz: "yangilash tokenining muddati tugagan",
+		common.LocaleRu: "срок действия токена обновления истёк",
+	})
+	common.RegisterErrorMessages(ErrRefreshTokenReused, map[common.Locale]string{
+		common.LocaleUz: "yangilash tokeni qayta ishlatilgan",
+		common.LocaleRu: "токен обновления использован повторно",
+	})
+	common.RegisterErrorMessages(ErrRefreshTokenDeviceMismatch, map[common.Locale]string{
+		common.LocaleUz: "yangilash tokeni boshqa qurilma uchun berilgan",
+		common.LocaleRu: "токен обновления выдан для другого устройства",
+	})
+	common.RegisterErrorMessages(ErrSessionNotFound, map[common.Locale]string{
+		common.LocaleUz: "seans topilmadi",
+		common.LocaleRu: "сеанс не найден",
+	})
+	common.RegisterErrorMessages(ErrInvalidPhone, map[common.Locale]string{
+		common.LocaleUz: "telefon raqami noto'g'ri",
+		common.LocaleRu: "неверный номер телефона",
+	})
+	common.RegisterErrorMessages(ErrPhoneAlreadyRegistered, map[common.Locale]string{
+		common.LocaleUz: "bu telefon raqami allaqachon ro'yxatdan o'tgan",
+		common.LocaleRu: "этот номер телефона уже зарегистрирован",
+	})
+	common.RegisterErrorMessages(ErrLocationRequired, map[common.Locale]string{
+		common.LocaleUz: "joylashuv talab qilinadi",
+		common.LocaleRu: "требуется местоположение",
+	})
+	common.RegisterErrorMessages(ErrUnknownSession, map[common.Locale]string{
+		common.LocaleUz: "joriy seans noma'lum, kirish tokenini yangilang",
+		common.LocaleRu: "текущий сеанс неизвестен, обновите токен доступа",
+	})
+	common.RegisterErrorMessages(ErrInvalidProviderToken, map[common.Locale]string{
+		common.LocaleUz: "provayder tokeni noto'g'ri",
+		common.LocaleRu: "неверный токен провайдера",
+	})
+	common.RegisterErrorMessages(ErrIdentityLinkedToOtherUser, map[common.Locale]string{
+		common.LocaleUz: "bu hisob boshqa foydalanuvchiga bog'langan",
+		common.LocaleRu: "этот аккаунт привязан к другому пользователю",
+	})
+	common.RegisterErrorMessages(ErrProviderAlreadyLinked, map[common.Locale]string{
+		common.LocaleUz: "bu provayderning hisobi allaqachon bog'langan",
+		common.LocaleRu: "аккаунт этого провайдера уже привязан",
+	})
+	common.RegisterErrorMessages(ErrIdentityNotFound, map[common.Locale]string{
+		common.LocaleUz: "bog'langan hisob topilmadi",
+		common.LocaleRu: "привязанный аккаунт не найден",
+	})
+	common.RegisterErrorMessages(ErrTotpAlreadyEnabled, map[common.Locale]string{
+		common.LocaleUz: "ikki bosqichli autentifikatsiya allaqachon yoqilgan",
+		common.LocaleRu: "двухфакторная аутентификация уже включена",
+	})
+	common.RegisterErrorMessages(ErrTotpNotEnrolled, map[common.Locale]string{
+		common.LocaleUz: "ikki bosqichli autentifikatsiyani sozlash boshlanmagan",
+		common.LocaleRu: "настройка двухфакторной аутентификации не начата",
+	})
+	common.RegisterErrorMessages(ErrTotpNotEnabled, map[common.Locale]string{
+		common.LocaleUz: "ikki bosqichli autentifikatsiya yoqilmagan",
+		common.LocaleRu: "двухфакторная аутентификация не включена",
+	})
+	common.RegisterErrorMessages(ErrInvalidTotpCode, map[common.Locale]string{
+		common.LocaleUz: "ikki bosqichli autentifikatsiya kodi noto'g'ri",
+		common.LocaleRu: "неверный код двухфакторной аутентификации",
+	})
+	common.RegisterErrorMessages(ErrInvalidMfaChallenge, map[common.Locale]string{
+		common.LocaleUz: "ikki bosqichli autentifikatsiya so'rovi noto'g'ri yoki muddati tugagan",
+		common.LocaleRu: "запрос двухфакторной аутентификации неверен или истёк",
+	})
+	common.RegisterErrorMessages(password.ErrPasswordTooShort, map[common.Locale]string{
+		common.LocaleUz: fmt.Sprintf("parol kamida %d belgidan iborat bo'lishi kerak", password.MinLength),
+		common.LocaleRu: fmt.Sprintf("пароль должен содержать не менее %d символов", password.MinLength),
+	})
+	common.RegisterErrorMessages(password.ErrPasswordTooWeak, map[common.Locale]string{
+		common.LocaleUz: "parolda harflar ham, raqamlar ham bo'lishi kerak",
+		common.LocaleRu: "пароль должен содержать буквы и цифры",
+	})
+	common.RegisterErrorMessages(password.ErrPasswordCommon, map[common.Locale]string{
+		common.LocaleUz: "parol juda oddiy",
+		common.LocaleRu: "пароль слишком простой",
+	})
+}
+
 type AuthManager interface {
 	SignupOrLogin(ctx context.Context, req SignupOrLoginRequest) (*SignupOrLoginResponse, error)
+	Signup(ctx context.Context, req SignupRequest) (*SignupOrLoginResponse, error)
+	Login(ctx context.Context, req LoginRequest) (*SignupOrLoginResponse, error)
+	VerifyEmail(ctx context.Context, req VerifyEmailRequest) error
+	ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error
+	RequestPhoneOtp(ctx context.Context, req RequestPhoneOtpRequest) error
+	VerifyPhoneOtp(ctx context.Context, req VerifyPhoneOtpRequest) (*SignupOrLoginResponse, error)
+	LinkPhone(ctx context.Context, req LinkPhoneRequest) error
+	RefreshAccessToken(ctx context.Context, req RefreshAccessTokenRequest) (*RefreshAccessTokenResponse, error)
 	Logout(ctx context.Context, req LogoutRequest) error
+	LogoutEverywhere(ctx context.Context, userID uuid.UUID) error
+	GetSessions(ctx context.Context, req GetSessionsRequest) ([]Session, error)
+	RevokeSession(ctx context.Context, userID uuid.UUID, deviceID string) error
+	RevokeOtherSessions(ctx context.Context, userID uuid.UUID, currentDeviceID string) error
+	JWKS() jwt.JSONWebKeySet
+	GetLoginLockouts(ctx context.Context, req GetLoginLockoutsRequest) ([]LoginLockout, error)
+	GetIdentities(ctx context.Context, userID uuid.UUID) (*GetIdentitiesResponse, error)
+	LinkIdentity(ctx context.Context, req LinkIdentityRequest) error
+	UnlinkIdentity(ctx context.Context, userID uuid.UUID, provider string) error
+	EnrollTotp(ctx context.Context, userID uuid.UUID) (*EnrollTotpResponse, error)
+	ConfirmTotp(ctx context.Context, req TotpCodeRequest) (*BackupCodesResponse, error)
+	DisableTotp(ctx context.Context, req TotpCodeRequest) error
+	RegenerateBackupCodes(ctx context.Context, req TotpCodeRequest) (*BackupCodesResponse, error)
+	GetTotpStatus(ctx context.Context, userID uuid.UUID) (*TotpStatus, error)
+	VerifyMfaChallenge(ctx context.Context, req VerifyMfaChallengeRequest) (*SignupOrLoginResponse, error)
 }
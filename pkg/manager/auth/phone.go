@@ -0,0 +1,174 @@
+package auth_manager
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	auth_redis "ketalk-api/pkg/manager/auth/redis"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/sms"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	phoneOtpTTL            = 5 * time.Minute
+	phoneOtpResendCooldown = time.Minute
+	maxPhoneOtpAttempts    = 5
+	// a phone number gets at most maxPhoneOtpSends codes per phoneOtpSendWindow
+	phoneOtpSendWindow = time.Hour
+	maxPhoneOtpSends   = 5
+)
+
+func (m *authManager) RequestPhoneOtp(ctx context.Context, req RequestPhoneOtpRequest) error {
+	phone, err := normalizePhone(req.Phone)
+	if err != nil {
+		return err
+	}
+	ok, err := m.redis.AcquireCooldown(ctx, phoneOtpKey(phone), phoneOtpResendCooldown)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerificationCodeRecentlySent
+	}
+	sends, err := m.redis.RecordOtpSend(ctx, phoneOtpKey(phone), phoneOtpSendWindow)
+	if err != nil {
+		return err
+	}
+	if sends > maxPhoneOtpSends {
+		return ErrVerificationCodeRecentlySent
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return err
+	}
+	// only the latest code is valid, the attempts carry over to it
+	if err := m.redis.SetOtp(ctx, phoneOtpKey(phone), hashVerificationCode(code), phoneOtpTTL); err != nil {
+		return err
+	}
+	return m.smsSender.Send(ctx, sms.Message{
+		To:   phone,
+		Body: fmt.Sprintf("Your Ketalk code is %s. It expires in %d minutes.", code, int(phoneOtpTTL.Minutes())),
+	})
+}
+
+// VerifyPhoneOtp logs in the owner of the phone number, a new user is created for unknown numbers.
+func (m *authManager) VerifyPhoneOtp(ctx context.Context, req VerifyPhoneOtpRequest) (*SignupOrLoginResponse, error) {
+	phone, err := normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	attempt := loginAttempt{Phone: phone, IP: req.IP, DeviceID: req.DeviceID}
+	if err := m.limitLoginAttempt(ctx, attempt, func() error {
+		return m.checkPhoneOtp(ctx, phone, req.Code)
+	}); err != nil {
+		return nil, err
+	}
+
+	user, err := m.userPort.GetUserByPhone(ctx, phone)
+	if err == gorm.ErrRecordNotFound {
+		if req.Location == nil {
+			return nil, ErrLocationRequired
+		}
+		geofence, err := m.geofencePort.GetGeofenceByLocation(ctx, *req.Location)
+		if err != nil {
+			return nil, err
+		}
+		username := strings.TrimSpace(req.UserName)
+		if username == "" {
+			username = defaultUsername
+		}
+		user, err = m.userPort.CreateUserWithPhone(ctx, port.CreateUserWithPhoneRequest{
+			Username:   username,
+			Phone:      phone,
+			GeofenceID: geofence.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return m.login(ctx, user, req.DeviceID, req.DeviceOS)
+}
+
+// LinkPhone adds a verified phone number to an existing account.
+func (m *authManager) LinkPhone(ctx context.Context, req LinkPhoneRequest) error {
+	phone, err := normalizePhone(req.Phone)
+	if err != nil {
+		return err
+	}
+	owner, err := m.userPort.GetUserByPhone(ctx, phone)
+	if err == nil && owner.ID != req.UserID {
+		return ErrPhoneAlreadyRegistered
+	} else if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	attempt := loginAttempt{Phone: phone, IP: req.IP, Account: req.UserID.String()}
+	if err := m.limitLoginAttempt(ctx, attempt, func() error {
+		return m.checkPhoneOtp(ctx, phone, req.Code)
+	}); err != nil {
+		return err
+	}
+	return m.userPort.SetPhone(ctx, req.UserID, phone)
+}
+
+func (m *authManager) checkPhoneOtp(ctx context.Context, phone string, code string) error {
+	// the attempt is counted before the code is compared, so parallel guesses can not share one
+	attempts, err := m.redis.IncrementOtpAttempts(ctx, phoneOtpKey(phone))
+	if err == auth_redis.ErrOtpNotFound {
+		// expired codes are removed by redis
+		return ErrVerificationCodeNotFound
+	} else if err != nil {
+		return err
+	}
+	if attempts > maxPhoneOtpAttempts {
+		return ErrTooManyVerificationAttempts
+	}
+	otp, err := m.redis.GetOtp(ctx, phoneOtpKey(phone))
+	if err == auth_redis.ErrOtpNotFound {
+		return ErrVerificationCodeNotFound
+	} else if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(hashVerificationCode(code)), []byte(otp.CodeHash)) != 1 {
+		return ErrInvalidVerificationCode
+	}
+	return m.redis.DeleteOtp(ctx, phoneOtpKey(phone))
+}
+
+func phoneOtpKey(phone string) string {
+	return fmt.Sprintf("phone:%s", phone)
+}
+
+// normalizePhone returns the number in E.164 format, separators people usually type are dropped.
+func normalizePhone(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(phone))
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + phone[2:]
+	}
+	if !strings.HasPrefix(phone, "+") {
+		return "", ErrInvalidPhone
+	}
+	digits := phone[1:]
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", ErrInvalidPhone
+		}
+	}
+	return phone, nil
+}
@@ -0,0 +1,50 @@
+package auth_manager
+
+import (
+	"context"
+	"ketalk-api/jwt"
+	auth_redis "ketalk-api/pkg/manager/auth/redis"
+	"ketalk-api/pkg/manager/auth/repository"
+	"ketalk-api/pkg/manager/port"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type authPort struct {
+	authRepository repository.Repository
+	redis          auth_redis.TokenRevocation
+	jwtConfig      jwt.Config
+}
+
+func NewAuthPort(authRepository repository.Repository, redis auth_redis.TokenRevocation, jwtConfig jwt.Config) port.AuthPort {
+	return &authPort{
+		authRepository,
+		redis,
+		jwtConfig,
+	}
+}
+
+func (p *authPort) RevokeUserTokens(ctx context.Context, userID uuid.UUID) error {
+	if err := p.authRepository.DeleteUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+	return p.redis.RevokeTokensIssuedBefore(ctx, userID, time.Now(), p.jwtConfig.ValidDuration)
+}
+
+func (p *authPort) GetUserSessions(ctx context.Context, userID uuid.UUID) ([]port.Session, error) {
+	tokens, err := p.authRepository.GetActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]port.Session, len(tokens))
+	for i, token := range tokens {
+		sessions[i] = port.Session{
+			DeviceID:   token.DeviceID,
+			DeviceOS:   token.DeviceOS,
+			CreatedAt:  token.SessionCreatedAt,
+			LastUsedAt: token.CreatedAt,
+		}
+	}
+	return sessions, nil
+}
@@ -0,0 +1,62 @@
+package auth_redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// TokenRevocation denies access tokens before they expire
+type TokenRevocation interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	RevokeTokensIssuedBefore(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error
+	RevokeDeviceTokensIssuedBefore(ctx context.Context, userID uuid.UUID, deviceID string, issuedBefore time.Time, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, tokenID string, userID uuid.UUID, deviceID string, issuedAt time.Time) (bool, error)
+}
+
+// OtpStore keeps the phone codes that were sent and limits how often they are sent
+type OtpStore interface {
+	SetOtp(ctx context.Context, key string, codeHash string, ttl time.Duration) error
+	GetOtp(ctx context.Context, key string) (*Otp, error)
+	IncrementOtpAttempts(ctx context.Context, key string) (int, error)
+	DeleteOtp(ctx context.Context, key string) error
+	AcquireCooldown(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	RecordOtpSend(ctx context.Context, key string, window time.Duration) (int64, error)
+}
+
+// LoginAttemptStore counts failed logins and locks the keys that failed too often
+type LoginAttemptStore interface {
+	RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+	// TakeBackLoginFailure undoes one count of RecordLoginFailure
+	TakeBackLoginFailure(ctx context.Context, key string) error
+	ResetLoginFailures(ctx context.Context, keys ...string) error
+	LockLogin(ctx context.Context, key string, ttl time.Duration) error
+	GetLoginLock(ctx context.Context, keys ...string) (time.Duration, error)
+}
+
+// MfaChallengeStore keeps the logins waiting for the second factor
+type MfaChallengeStore interface {
+	SetMfaChallenge(ctx context.Context, challengeID string, challenge MfaChallenge, ttl time.Duration) error
+	GetMfaChallenge(ctx context.Context, challengeID string) (*MfaChallenge, error)
+	IncrementMfaChallengeAttempts(ctx context.Context, challengeID string) (int, error)
+	DeleteMfaChallenge(ctx context.Context, challengeID string) (bool, error)
+}
+
+type RedisClient interface {
+	TokenRevocation
+	OtpStore
+	LoginAttemptStore
+	MfaChallengeStore
+}
+
+type redisClient struct {
+	client *redis.Client
+}
+
+func NewRedisClient(client *redis.Client) RedisClient {
+	return &redisClient{
+		client: client,
+	}
+}
@@ -0,0 +1,88 @@
+package auth_redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// recordLoginFailure starts the window with the first failure, so the counter
+// resets once no failure happened for the whole window.
+var recordLoginFailure = redis.NewScript(`
+local failures = redis.call("INCR", KEYS[1])
+if failures == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return failures
+`)
+
+// takeBackLoginFailure never creates a counter, the window of the counter may be over already
+var takeBackLoginFailure = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 and tonumber(redis.call("GET", KEYS[1])) > 0 then
+	return redis.call("DECR", KEYS[1])
+end
+return 0
+`)
+
+// lockLogin never shortens a lock that is already longer, like a lockout followed by a delay.
+var lockLogin = redis.NewScript(`
+if redis.call("PTTL", KEYS[1]) < tonumber(ARGV[1]) then
+	redis.call("SET", KEYS[1], 1, "PX", ARGV[1])
+end
+return 1
+`)
+
+func loginFailuresKey(key string) string {
+	return fmt.Sprintf("login_failures:%s", key)
+}
+
+func loginLockKey(key string) string {
+	return fmt.Sprintf("login_lock:%s", key)
+}
+
+func (c *redisClient) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return recordLoginFailure.Run(ctx, c.client, []string{loginFailuresKey(key)}, window.Milliseconds()).Int64()
+}
+
+func (c *redisClient) TakeBackLoginFailure(ctx context.Context, key string) error {
+	return takeBackLoginFailure.Run(ctx, c.client, []string{loginFailuresKey(key)}).Err()
+}
+
+func (c *redisClient) ResetLoginFailures(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	redisKeys := make([]string, len(keys))
+	for i, key := range keys {
+		redisKeys[i] = loginFailuresKey(key)
+	}
+	return c.client.Del(ctx, redisKeys...).Err()
+}
+
+func (c *redisClient) LockLogin(ctx context.Context, key string, ttl time.Duration) error {
+	return lockLogin.Run(ctx, c.client, []string{loginLockKey(key)}, ttl.Milliseconds()).Err()
+}
+
+// GetLoginLock returns the longest remaining lock of the keys, or zero when none is locked.
+func (c *redisClient) GetLoginLock(ctx context.Context, keys ...string) (time.Duration, error) {
+	cmds := make([]*redis.DurationCmd, len(keys))
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.PTTL(ctx, loginLockKey(key))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	var longest time.Duration
+	for _, cmd := range cmds {
+		// missing keys report a negative ttl
+		if ttl := cmd.Val(); ttl > longest {
+			longest = ttl
+		}
+	}
+	return longest, nil
+}
@@ -0,0 +1,91 @@
+package auth_redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+var ErrMfaChallengeNotFound = fmt.Errorf("mfa challenge not found")
+
+// MfaChallenge is the login that is waiting for the second factor
+type MfaChallenge struct {
+	UserID   uuid.UUID
+	DeviceID string
+	DeviceOS string
+	Attempts int
+}
+
+var incrementMfaChallengeAttempts = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "attempts", 1)
+`)
+
+func mfaChallengeKey(challengeID string) string {
+	return fmt.Sprintf("mfa_challenge:%s", challengeID)
+}
+
+func (c *redisClient) SetMfaChallenge(ctx context.Context, challengeID string, challenge MfaChallenge, ttl time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, mfaChallengeKey(challengeID),
+			"user_id", challenge.UserID.String(),
+			"device_id", challenge.DeviceID,
+			"device_os", challenge.DeviceOS,
+			"attempts", 0,
+		)
+		pipe.Expire(ctx, mfaChallengeKey(challengeID), ttl)
+		return nil
+	})
+	return err
+}
+
+func (c *redisClient) GetMfaChallenge(ctx context.Context, challengeID string) (*MfaChallenge, error) {
+	values, err := c.client.HGetAll(ctx, mfaChallengeKey(challengeID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, ErrMfaChallengeNotFound
+	}
+	userID, err := uuid.Parse(values["user_id"])
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := strconv.Atoi(values["attempts"])
+	if err != nil {
+		return nil, err
+	}
+	return &MfaChallenge{
+		UserID:   userID,
+		DeviceID: values["device_id"],
+		DeviceOS: values["device_os"],
+		Attempts: attempts,
+	}, nil
+}
+
+func (c *redisClient) IncrementMfaChallengeAttempts(ctx context.Context, challengeID string) (int, error) {
+	attempts, err := incrementMfaChallengeAttempts.Run(ctx, c.client, []string{mfaChallengeKey(challengeID)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if attempts < 0 {
+		return 0, ErrMfaChallengeNotFound
+	}
+	return attempts, nil
+}
+
+// DeleteMfaChallenge returns false when the challenge was already used or expired,
+// so only one verify call can complete a login.
+func (c *redisClient) DeleteMfaChallenge(ctx context.Context, challengeID string) (bool, error) {
+	deleted, err := c.client.Del(ctx, mfaChallengeKey(challengeID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return deleted == 1, nil
+}
@@ -0,0 +1,93 @@
+package auth_redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var ErrOtpNotFound = fmt.Errorf("otp not found")
+
+type Otp struct {
+	CodeHash string
+	Attempts int
+}
+
+// incrementOtpAttempts only counts attempts of an otp that still exists,
+// otherwise the counter would outlive the expired code.
+var incrementOtpAttempts = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "attempts", 1)
+`)
+
+func otpKey(key string) string {
+	return fmt.Sprintf("otp:%s", key)
+}
+
+func otpSendsKey(key string) string {
+	return fmt.Sprintf("otp_sends:%s", key)
+}
+
+func cooldownKey(key string) string {
+	return fmt.Sprintf("cooldown:%s", key)
+}
+
+// SetOtp stores the hash of a one time code replacing the previous one, the attempts are kept
+// so that sending a new code does not allow more guesses.
+func (c *redisClient) SetOtp(ctx context.Context, key string, codeHash string, ttl time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, otpKey(key), "code_hash", codeHash)
+		pipe.HSetNX(ctx, otpKey(key), "attempts", 0)
+		pipe.Expire(ctx, otpKey(key), ttl)
+		return nil
+	})
+	return err
+}
+
+func (c *redisClient) GetOtp(ctx context.Context, key string) (*Otp, error) {
+	values, err := c.client.HGetAll(ctx, otpKey(key)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, ErrOtpNotFound
+	}
+	attempts, err := strconv.Atoi(values["attempts"])
+	if err != nil {
+		return nil, err
+	}
+	return &Otp{
+		CodeHash: values["code_hash"],
+		Attempts: attempts,
+	}, nil
+}
+
+func (c *redisClient) IncrementOtpAttempts(ctx context.Context, key string) (int, error) {
+	attempts, err := incrementOtpAttempts.Run(ctx, c.client, []string{otpKey(key)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if attempts < 0 {
+		return 0, ErrOtpNotFound
+	}
+	return attempts, nil
+}
+
+func (c *redisClient) DeleteOtp(ctx context.Context, key string) error {
+	return c.client.Del(ctx, otpKey(key)).Err()
+}
+
+// AcquireCooldown returns false while the cooldown of the key started by a previous call is running.
+func (c *redisClient) AcquireCooldown(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, cooldownKey(key), 1, ttl).Result()
+}
+
+// RecordOtpSend counts the codes sent for the key, the window starts with the first one
+func (c *redisClient) RecordOtpSend(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return recordLoginFailure.Run(ctx, c.client, []string{otpSendsKey(key)}, window.Milliseconds()).Int64()
+}
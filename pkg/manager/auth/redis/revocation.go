@@ -0,0 +1,89 @@
+package auth_redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+func revokedTokenKey(tokenID string) string {
+	return fmt.Sprintf("revoked_token:%s", tokenID)
+}
+
+func revokedBeforeKey(userID uuid.UUID) string {
+	return fmt.Sprintf("tokens_revoked_before:%s", userID)
+}
+
+func deviceRevokedBeforeKey(userID uuid.UUID, deviceID string) string {
+	return fmt.Sprintf("tokens_revoked_before:%s:%s", userID, deviceID)
+}
+
+// RevokeToken denies the access token until it expires on its own.
+func (c *redisClient) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return c.client.Set(ctx, revokedTokenKey(tokenID), 1, ttl).Err()
+}
+
+// RevokeTokensIssuedBefore denies every access token of the user issued before the given time.
+// The watermark is kept for ttl, after that the tokens it covers have expired anyway.
+func (c *redisClient) RevokeTokensIssuedBefore(ctx context.Context, userID uuid.UUID, issuedBefore time.Time, ttl time.Duration) error {
+	return c.client.Set(ctx, revokedBeforeKey(userID), issuedBefore.UnixMilli(), ttl).Err()
+}
+
+// RevokeDeviceTokensIssuedBefore denies the access tokens of one session of the user issued before the given time.
+func (c *redisClient) RevokeDeviceTokensIssuedBefore(ctx context.Context, userID uuid.UUID, deviceID string, issuedBefore time.Time, ttl time.Duration) error {
+	return c.client.Set(ctx, deviceRevokedBeforeKey(userID, deviceID), issuedBefore.UnixMilli(), ttl).Err()
+}
+
+// IsTokenRevoked checks the denylist and the watermarks of the user and of the device the token was issued for.
+func (c *redisClient) IsTokenRevoked(ctx context.Context, tokenID string, userID uuid.UUID, deviceID string, issuedAt time.Time) (bool, error) {
+	watermarks := []string{revokedBeforeKey(userID)}
+	if deviceID != "" {
+		watermarks = append(watermarks, deviceRevokedBeforeKey(userID, deviceID))
+	}
+	keys := watermarks
+	if tokenID != "" {
+		keys = append(keys, revokedTokenKey(tokenID))
+	}
+	values, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+	if len(values) > len(watermarks) && values[len(watermarks)] != nil {
+		return true, nil
+	}
+	for _, value := range values[:len(watermarks)] {
+		if value == nil {
+			continue
+		}
+		watermark, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("invalid revocation watermark for user %s", userID)
+		}
+		revoked, err := issuedBeforeWatermark(issuedAt, watermark)
+		if err != nil {
+			return false, err
+		}
+		if revoked {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// issuedBeforeWatermark compares in milliseconds, the precision of the iat of our tokens,
+// a token issued in the same second as a logout but after it stays valid
+func issuedBeforeWatermark(issuedAt time.Time, watermark string) (bool, error) {
+	revokedBefore, err := strconv.ParseInt(watermark, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedAt.UnixMilli() < revokedBefore, nil
+}
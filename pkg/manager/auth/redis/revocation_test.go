@@ -0,0 +1,66 @@
+package auth_redis
+
+import (
+	"context"
+	"ketalk-api/jwt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func issueTestToken(t *testing.T, cfg jwt.Config) time.Time {
+	t.Helper()
+	token, err := jwt.IssueToken(cfg, uuid.New(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := jwt.ParseJWTToken(context.Background(), cfg, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := jwt.GetJWTToken(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return claims.IssuedAt.Time
+}
+
+func TestRevokeTokenIssuedInTheSameSecond(t *testing.T) {
+	cfg := jwt.Config{EphemeralSigningKey: true}
+	cfg.SetDefaults()
+	if err := cfg.LoadKeys(); err != nil {
+		t.Fatal(err)
+	}
+	// start right after a second begins, so the tokens and the revocation share it
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	before := issueTestToken(t, cfg)
+	time.Sleep(5 * time.Millisecond)
+	revokedAt := time.Now()
+	watermark := strconv.FormatInt(revokedAt.UnixMilli(), 10)
+	time.Sleep(5 * time.Millisecond)
+	after := issueTestToken(t, cfg)
+	if before.Unix() != revokedAt.Unix() || after.Unix() != revokedAt.Unix() {
+		t.Fatalf("tokens were not issued in the second of the revocation: %v %v %v", before, revokedAt, after)
+	}
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		want     bool
+	}{
+		{"issued before the revocation", before, true},
+		{"issued after the revocation", after, false},
+	}
+	for _, tt := range tests {
+		got, err := issuedBeforeWatermark(tt.issuedAt, watermark)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: revoked = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
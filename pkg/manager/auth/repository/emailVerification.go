@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type emailVerificationRepository struct {
+	*gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{
+		db,
+	}
+}
+
+func (r *emailVerificationRepository) Create(ctx context.Context, emailVerification *EmailVerification) error {
+	res := r.DB.Create(emailVerification)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return common.ErrMoreThanOneRowUpdated
+	}
+	return nil
+}
+
+func (r *emailVerificationRepository) GetActive(ctx context.Context, userID uuid.UUID) (*EmailVerification, error) {
+	var emailVerification EmailVerification
+	resp := r.Where("user_id = ?", userID).Order("created_at DESC").First(&emailVerification)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &emailVerification, nil
+}
+
+func (r *emailVerificationRepository) IncrementAttempts(ctx context.Context, id uuid.UUID) error {
+	return r.Model(&EmailVerification{}).Where("id = ?", id).Update("attempts", gorm.Expr("attempts + ?", 1)).Error
+}
+
+func (r *emailVerificationRepository) DeleteUserVerifications(ctx context.Context, userID uuid.UUID) error {
+	return r.Model(&EmailVerification{}).Where("user_id = ? and deleted_at is null", userID).Update("deleted_at", time.Now()).Error
+}
+
+func (r *emailVerificationRepository) Migrate() error {
+	return r.AutoMigrate(&EmailVerification{})
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+
+	"gorm.io/gorm"
+)
+
+type loginLockoutRepository struct {
+	*gorm.DB
+}
+
+func NewLoginLockoutRepository(db *gorm.DB) LoginLockoutRepository {
+	return &loginLockoutRepository{
+		db,
+	}
+}
+
+func (r *loginLockoutRepository) Create(ctx context.Context, lockout *LoginLockout) error {
+	res := r.DB.Create(lockout)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return common.ErrMoreThanOneRowUpdated
+	}
+	return nil
+}
+
+func (r *loginLockoutRepository) GetLockouts(ctx context.Context, filter LoginLockoutFilter) ([]LoginLockout, error) {
+	var lockouts []LoginLockout = make([]LoginLockout, 0)
+	query := r.DB.Model(&LoginLockout{})
+	if filter.Scope != "" {
+		query = query.Where("scope = ?", filter.Scope)
+	}
+	if filter.Value != "" {
+		query = query.Where("value = ?", filter.Value)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	resp := query.Order("created_at DESC").Limit(filter.Limit).Find(&lockouts)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return lockouts, nil
+}
+
+func (r *loginLockoutRepository) Migrate() error {
+	return r.AutoMigrate(&LoginLockout{})
+}
@@ -9,17 +9,105 @@ import (
 )
 
 type DeviceRefreshToken struct {
-	RefreshToken         string
+	RefreshToken         string `gorm:"index"`
 	DeviceID             string
 	DeviceOS             string
 	UserId               uuid.UUID
 	RefreshTokenExpiryAt time.Time
+	// RotatedAt is set when the token was exchanged for a new one, so a later
+	// use of the same token can be told apart from an unknown or logged out one
+	RotatedAt *time.Time
+	// SessionCreatedAt is the login time on the device and is carried over on rotation,
+	// while CreatedAt of the active token tells when the session was last used
+	SessionCreatedAt time.Time
 	common.CreatedDeleted
 }
 
 type Repository interface {
 	AddRefreshToken(ctx context.Context, deviceRefreshToken *DeviceRefreshToken) error
-	DeleteRefreshToken(ctx context.Context, deviceId string) error
+	RotateRefreshToken(ctx context.Context, oldRefreshToken string, deviceRefreshToken *DeviceRefreshToken) error
+	DeleteRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) error
+	DeleteDeviceRefreshTokens(ctx context.Context, userID uuid.UUID, deviceID string) error
+	DeleteOtherDeviceRefreshTokens(ctx context.Context, userID uuid.UUID, deviceID string) error
+	DeleteUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
 	GetRefreshToken(ctx context.Context, refreshToken string) (*DeviceRefreshToken, error)
+	GetActiveRefreshTokens(ctx context.Context, userID uuid.UUID) ([]DeviceRefreshToken, error)
+	GetRotatedRefreshToken(ctx context.Context, refreshToken string) (*DeviceRefreshToken, error)
+	Migrate() error
+}
+
+type EmailVerification struct {
+	ID        uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;index"`
+	Email     string
+	CodeHash  string
+	Attempts  int
+	ExpiresAt time.Time
+	common.CreatedDeleted
+}
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, emailVerification *EmailVerification) error
+	GetActive(ctx context.Context, userID uuid.UUID) (*EmailVerification, error)
+	IncrementAttempts(ctx context.Context, id uuid.UUID) error
+	DeleteUserVerifications(ctx context.Context, userID uuid.UUID) error
+	Migrate() error
+}
+
+// LoginLockout is the audit record of a lockout after too many failed logins
+type LoginLockout struct {
+	ID uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	// Scope is what the failures were counted for, email, ip or device
+	Scope       string `gorm:"index:idx_login_lockout_scope_value"`
+	Value       string `gorm:"index:idx_login_lockout_scope_value"`
+	Failures    int64
+	LockedUntil time.Time
+	common.CreatedUpdated
+}
+
+type LoginLockoutFilter struct {
+	Scope string
+	Value string
+	Since *time.Time
+	Limit int
+}
+
+type LoginLockoutRepository interface {
+	Create(ctx context.Context, lockout *LoginLockout) error
+	GetLockouts(ctx context.Context, filter LoginLockoutFilter) ([]LoginLockout, error)
+	Migrate() error
+}
+
+// UserTotp is the authenticator app secret of a user, 2FA is only on once EnabledAt is set
+type UserTotp struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Secret    string
+	EnabledAt *time.Time
+	// LastUsedStep is the time step of the last accepted code, a code can only be used once
+	LastUsedStep int64
+	common.CreatedUpdated
+}
+
+type TotpBackupCode struct {
+	ID       uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	UserID   uuid.UUID `gorm:"type:uuid;index"`
+	CodeHash string
+	UsedAt   *time.Time
+	common.CreatedUpdated
+}
+
+type TotpRepository interface {
+	GetTotp(ctx context.Context, userID uuid.UUID) (*UserTotp, error)
+	// SaveSecret starts an enrollment, replacing a secret that was not confirmed yet
+	SaveSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	// Enable turns 2FA on and replaces the backup codes
+	Enable(ctx context.Context, userID uuid.UUID, step int64, backupCodeHashes []string) error
+	Disable(ctx context.Context, userID uuid.UUID) error
+	// UseStep records the time step of an accepted code, it returns false when the step was already used
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// UseBackupCode marks the code as used, it returns false when there is no unused code with the hash
+	UseBackupCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	ReplaceBackupCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	CountUnusedBackupCodes(ctx context.Context, userID uuid.UUID) (int64, error)
 	Migrate() error
 }
@@ -2,11 +2,11 @@ package repository
 
 import (
 	"context"
-	"fmt"
 	"ketalk-api/common"
 	"log"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -31,17 +31,74 @@ func (r *repository) AddRefreshToken(ctx context.Context, deviceRefreshToken *De
 	return nil
 }
 
-func (r *repository) DeleteRefreshToken(ctx context.Context, deviceId string) error {
+// RotateRefreshToken revokes the old refresh token and stores the new one in a single transaction.
+// The old token is only revoked if it is still active, so concurrent rotations of the same token fail.
+func (r *repository) RotateRefreshToken(ctx context.Context, oldRefreshToken string, deviceRefreshToken *DeviceRefreshToken) error {
+	return r.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		resp := tx.Model(&DeviceRefreshToken{}).
+			Where("refresh_token = ? and deleted_at is null", oldRefreshToken).
+			Updates(map[string]interface{}{
+				"rotated_at": now,
+				"deleted_at": now,
+			})
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if resp.RowsAffected != 1 {
+			return common.ErrRecordNotFound
+		}
+		res := tx.Create(deviceRefreshToken)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return common.ErrMoreThanOneRowUpdated
+		}
+		return nil
+	})
+}
+
+func (r *repository) DeleteRefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) error {
 	var d DeviceRefreshToken
-	resp := r.Model(&d).Where("device_id = ? and deleted_at is null", deviceId).Update("deleted_at", time.Now())
+	resp := r.Model(&d).Where("user_id = ? and refresh_token = ? and deleted_at is null", userID, refreshToken).Update("deleted_at", time.Now())
 	if resp.Error != nil {
 		return resp.Error
 	}
 	if resp.RowsAffected > 1 {
-		fmt.Println("more than one refresh token is being deleted")
-	} else {
-		log.Printf("could not find any active refresh token for given device id %s\n", deviceId)
+		return common.ErrMoreThanOneRowUpdated
+	}
+	if resp.RowsAffected == 0 {
+		log.Printf("could not find any active refresh token for user %s\n", userID)
+	}
+	return nil
+}
+
+func (r *repository) DeleteDeviceRefreshTokens(ctx context.Context, userID uuid.UUID, deviceID string) error {
+	resp := r.Model(&DeviceRefreshToken{}).Where("user_id = ? and device_id = ? and deleted_at is null", userID, deviceID).Update("deleted_at", time.Now())
+	if resp.Error != nil {
+		return resp.Error
 	}
+	log.Printf("revoked %d refresh tokens for user %s on device %s\n", resp.RowsAffected, userID, deviceID)
+	return nil
+}
+
+// DeleteOtherDeviceRefreshTokens revokes the tokens of every device of the user except the given one.
+func (r *repository) DeleteOtherDeviceRefreshTokens(ctx context.Context, userID uuid.UUID, deviceID string) error {
+	resp := r.Model(&DeviceRefreshToken{}).Where("user_id = ? and device_id <> ? and deleted_at is null", userID, deviceID).Update("deleted_at", time.Now())
+	if resp.Error != nil {
+		return resp.Error
+	}
+	log.Printf("revoked %d refresh tokens for user %s except device %s\n", resp.RowsAffected, userID, deviceID)
+	return nil
+}
+
+func (r *repository) DeleteUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	resp := r.Model(&DeviceRefreshToken{}).Where("user_id = ? and deleted_at is null", userID).Update("deleted_at", time.Now())
+	if resp.Error != nil {
+		return resp.Error
+	}
+	log.Printf("revoked %d refresh tokens for user %s\n", resp.RowsAffected, userID)
 	return nil
 }
 
@@ -55,6 +112,35 @@ func (r *repository) GetRefreshToken(ctx context.Context, refreshToken string) (
 	return &d, nil
 }
 
+func (r *repository) GetRotatedRefreshToken(ctx context.Context, refreshToken string) (*DeviceRefreshToken, error) {
+	var d DeviceRefreshToken
+	resp := r.Unscoped().Model(&d).Where("refresh_token = ? and rotated_at is not null", refreshToken).First(&d)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &d, nil
+}
+
+func (r *repository) GetActiveRefreshTokens(ctx context.Context, userID uuid.UUID) ([]DeviceRefreshToken, error) {
+	var tokens []DeviceRefreshToken
+	resp := r.Model(&DeviceRefreshToken{}).
+		Where("user_id = ? and deleted_at is null and refresh_token_expiry_at > ?", userID, time.Now()).
+		Order("created_at desc").
+		Find(&tokens)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return tokens, nil
+}
+
 func (r *repository) Migrate() error {
-	return r.AutoMigrate(&DeviceRefreshToken{})
+	hasSessionCreatedAt := r.Migrator().HasColumn(&DeviceRefreshToken{}, "session_created_at")
+	if err := r.AutoMigrate(&DeviceRefreshToken{}); err != nil {
+		return err
+	}
+	if !hasSessionCreatedAt {
+		// the login time of existing sessions is lost, the last rotation is the closest we have
+		return r.Model(&DeviceRefreshToken{}).Unscoped().Where("1 = 1").Update("session_created_at", gorm.Expr("created_at")).Error
+	}
+	return nil
 }
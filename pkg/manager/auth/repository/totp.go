@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type totpRepository struct {
+	*gorm.DB
+}
+
+func NewTotpRepository(db *gorm.DB) TotpRepository {
+	return &totpRepository{
+		db,
+	}
+}
+
+func (r *totpRepository) GetTotp(ctx context.Context, userID uuid.UUID) (*UserTotp, error) {
+	var totp UserTotp
+	resp := r.DB.Where("user_id = ?", userID).First(&totp)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &totp, nil
+}
+
+func (r *totpRepository) SaveSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	totp := UserTotp{
+		UserID: userID,
+		Secret: secret,
+	}
+	// an enabled secret is never replaced, it has to be disabled first
+	resp := r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"secret": secret, "last_used_step": 0, "updated_at": time.Now()}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_totp.enabled_at IS NULL"}}},
+	}).Create(&totp)
+	return resp.Error
+}
+
+func (r *totpRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, backupCodeHashes []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&UserTotp{}).Where("user_id = ? AND enabled_at IS NULL", userID).Updates(map[string]interface{}{
+			"enabled_at":     time.Now(),
+			"last_used_step": step,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+		return replaceBackupCodes(tx, userID, backupCodeHashes)
+	})
+}
+
+func (r *totpRepository) Disable(ctx context.Context, userID uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&TotpBackupCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&UserTotp{}).Error
+	})
+}
+
+func (r *totpRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	res := r.DB.Model(&UserTotp{}).Where("user_id = ? AND last_used_step < ?", userID, step).Update("last_used_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *totpRepository) UseBackupCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	res := r.DB.Model(&TotpBackupCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *totpRepository) ReplaceBackupCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return replaceBackupCodes(tx, userID, codeHashes)
+	})
+}
+
+func (r *totpRepository) CountUnusedBackupCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	resp := r.DB.Model(&TotpBackupCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count)
+	if resp.Error != nil {
+		return 0, resp.Error
+	}
+	return count, nil
+}
+
+func (r *totpRepository) Migrate() error {
+	return r.AutoMigrate(&UserTotp{}, &TotpBackupCode{})
+}
+
+func replaceBackupCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&TotpBackupCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]TotpBackupCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = TotpBackupCode{
+			UserID:   userID,
+			CodeHash: hash,
+		}
+	}
+	return tx.Create(&codes).Error
+}
@@ -0,0 +1,74 @@
+package auth_manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (m *authManager) GetSessions(ctx context.Context, req GetSessionsRequest) ([]Session, error) {
+	tokens, err := m.authRepository.GetActiveRefreshTokens(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]Session, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, Session{
+			DeviceID:   token.DeviceID,
+			DeviceOS:   token.DeviceOS,
+			CreatedAt:  token.SessionCreatedAt,
+			LastUsedAt: token.CreatedAt,
+			Current:    req.CurrentDeviceID != "" && token.DeviceID == req.CurrentDeviceID,
+		})
+	}
+	return sessions, nil
+}
+
+func (m *authManager) RevokeSession(ctx context.Context, userID uuid.UUID, deviceID string) error {
+	tokens, err := m.authRepository.GetActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, token := range tokens {
+		if token.DeviceID == deviceID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrSessionNotFound
+	}
+	if err := m.authRepository.DeleteDeviceRefreshTokens(ctx, userID, deviceID); err != nil {
+		return err
+	}
+	return m.revokeDeviceAccessTokens(ctx, userID, deviceID)
+}
+
+func (m *authManager) RevokeOtherSessions(ctx context.Context, userID uuid.UUID, currentDeviceID string) error {
+	if currentDeviceID == "" {
+		return ErrUnknownSession
+	}
+	tokens, err := m.authRepository.GetActiveRefreshTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := m.authRepository.DeleteOtherDeviceRefreshTokens(ctx, userID, currentDeviceID); err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if token.DeviceID == currentDeviceID {
+			continue
+		}
+		if err := m.revokeDeviceAccessTokens(ctx, userID, token.DeviceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeDeviceAccessTokens denies the access tokens already issued to the device, they would stay valid until they expire otherwise
+func (m *authManager) revokeDeviceAccessTokens(ctx context.Context, userID uuid.UUID, deviceID string) error {
+	return m.redis.RevokeDeviceTokensIssuedBefore(ctx, userID, deviceID, time.Now(), m.jwtConfig.ValidDuration)
+}
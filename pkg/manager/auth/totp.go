@@ -0,0 +1,305 @@
+package auth_manager
+
+import (
+	"context"
+	"ketalk-api/common"
+	auth_redis "ketalk-api/pkg/manager/auth/redis"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/totp"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	totpIssuer        = "Ketalk"
+	mfaChallengeTTL   = 5 * time.Minute
+	maxMfaAttempts    = 5
+	backupCodeCount   = 10
+	backupCodeLength  = 10
+	mfaChallengeBytes = 32
+	// the second factor failures are counted per user across challenges, a password login does not reset them
+	mfaFailureWindow   = time.Hour
+	maxMfaFailures     = 10
+	mfaLockoutDuration = time.Hour
+)
+
+func (m *authManager) EnrollTotp(ctx context.Context, userID uuid.UUID) (*EnrollTotpResponse, error) {
+	enabled, err := m.isTotpEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrTotpAlreadyEnabled
+	}
+	user, err := m.userPort.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.totpRepository.SaveSecret(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+	return &EnrollTotpResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, totpIssuer, totpAccountName(user)),
+	}, nil
+}
+
+func (m *authManager) ConfirmTotp(ctx context.Context, req TotpCodeRequest) (*BackupCodesResponse, error) {
+	userTotp, err := m.totpRepository.GetTotp(ctx, req.UserID)
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrTotpNotEnrolled
+	} else if err != nil {
+		return nil, err
+	}
+	if userTotp.EnabledAt != nil {
+		return nil, ErrTotpAlreadyEnabled
+	}
+	step, ok, err := totp.Validate(userTotp.Secret, req.Code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTotpCode
+	}
+	codes, hashes, err := generateBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.totpRepository.Enable(ctx, req.UserID, step, hashes); err != nil {
+		return nil, err
+	}
+	return &BackupCodesResponse{
+		BackupCodes: codes,
+	}, nil
+}
+
+// DisableTotp accepts a backup code as well, for users who lost their authenticator
+func (m *authManager) DisableTotp(ctx context.Context, req TotpCodeRequest) error {
+	if err := m.verifySecondFactor(ctx, req.UserID, req.Code, true); err != nil {
+		return err
+	}
+	return m.totpRepository.Disable(ctx, req.UserID)
+}
+
+func (m *authManager) RegenerateBackupCodes(ctx context.Context, req TotpCodeRequest) (*BackupCodesResponse, error) {
+	if err := m.verifySecondFactor(ctx, req.UserID, req.Code, false); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.totpRepository.ReplaceBackupCodes(ctx, req.UserID, hashes); err != nil {
+		return nil, err
+	}
+	return &BackupCodesResponse{
+		BackupCodes: codes,
+	}, nil
+}
+
+func (m *authManager) GetTotpStatus(ctx context.Context, userID uuid.UUID) (*TotpStatus, error) {
+	enabled, err := m.isTotpEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return &TotpStatus{}, nil
+	}
+	remaining, err := m.totpRepository.CountUnusedBackupCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &TotpStatus{
+		Enabled:              true,
+		RemainingBackupCodes: remaining,
+	}, nil
+}
+
+func (m *authManager) VerifyMfaChallenge(ctx context.Context, req VerifyMfaChallengeRequest) (*SignupOrLoginResponse, error) {
+	challenge, err := m.redis.GetMfaChallenge(ctx, req.Challenge)
+	if err == auth_redis.ErrMfaChallengeNotFound {
+		return nil, ErrInvalidMfaChallenge
+	} else if err != nil {
+		return nil, err
+	}
+	attempts, err := m.redis.IncrementMfaChallengeAttempts(ctx, req.Challenge)
+	if err == auth_redis.ErrMfaChallengeNotFound {
+		return nil, ErrInvalidMfaChallenge
+	} else if err != nil {
+		return nil, err
+	}
+	if attempts > maxMfaAttempts {
+		// the first factor has to be passed again
+		if _, err := m.redis.DeleteMfaChallenge(ctx, req.Challenge); err != nil {
+			return nil, err
+		}
+		return nil, ErrTooManyVerificationAttempts
+	}
+	attempt := loginAttempt{Account: challenge.UserID.String(), IP: req.IP, DeviceID: challenge.DeviceID}
+	if err := m.limitLoginAttempt(ctx, attempt, func() error {
+		return m.verifySecondFactor(ctx, challenge.UserID, req.Code, true)
+	}); err != nil {
+		return nil, err
+	}
+	// a challenge completes a single login, even when two requests pass the check at the same time
+	deleted, err := m.redis.DeleteMfaChallenge(ctx, req.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, ErrInvalidMfaChallenge
+	}
+
+	user, err := m.userPort.GetUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return m.issueLogin(ctx, user, challenge.DeviceID, challenge.DeviceOS)
+}
+
+// createMfaChallenge is returned by the login flows instead of tokens when the user has 2FA enabled
+func (m *authManager) createMfaChallenge(ctx context.Context, user *port.User, deviceID string, deviceOS string) (*SignupOrLoginResponse, error) {
+	challengeID, err := GenerateRandomHex(mfaChallengeBytes * 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.redis.SetMfaChallenge(ctx, challengeID, auth_redis.MfaChallenge{
+		UserID:   user.ID,
+		DeviceID: deviceID,
+		DeviceOS: deviceOS,
+	}, mfaChallengeTTL); err != nil {
+		return nil, err
+	}
+	return &SignupOrLoginResponse{
+		MfaChallenge:          challengeID,
+		MfaChallengeExpiresAt: time.Now().Add(mfaChallengeTTL),
+	}, nil
+}
+
+func (m *authManager) isTotpEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	userTotp, err := m.totpRepository.GetTotp(ctx, userID)
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return userTotp.EnabledAt != nil, nil
+}
+
+// verifySecondFactor limits checkSecondFactor per user, the attempt is counted before the code is checked
+// so parallel guesses can not get past the limit
+func (m *authManager) verifySecondFactor(ctx context.Context, userID uuid.UUID, code string, allowBackupCode bool) error {
+	key := loginAttemptKey{LoginScopeMfa, userID.String()}
+	retryAfter, err := m.redis.GetLoginLock(ctx, key.String())
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		return &common.RetryAfterError{
+			Err:        common.ErrTooManyAttempts,
+			RetryAfter: retryAfter,
+		}
+	}
+	attempts, err := m.redis.RecordLoginFailure(ctx, key.String(), mfaFailureWindow)
+	if err != nil {
+		return err
+	}
+	if attempts > maxMfaFailures {
+		if err := m.lockLogin(ctx, key, attempts, mfaLockoutDuration); err != nil {
+			return err
+		}
+		return &common.RetryAfterError{
+			Err:        common.ErrTooManyAttempts,
+			RetryAfter: mfaLockoutDuration,
+		}
+	}
+	if err := m.checkSecondFactor(ctx, userID, code, allowBackupCode); err != nil {
+		return err
+	}
+	if err := m.redis.ResetLoginFailures(ctx, key.String()); err != nil {
+		log.Printf("failed to reset second factor failures of user %s: %v\n", userID, err)
+	}
+	return nil
+}
+
+// checkSecondFactor accepts a code of the authenticator app, each one only once,
+// and an unused backup code when allowed
+func (m *authManager) checkSecondFactor(ctx context.Context, userID uuid.UUID, code string, allowBackupCode bool) error {
+	userTotp, err := m.totpRepository.GetTotp(ctx, userID)
+	if err == gorm.ErrRecordNotFound {
+		return ErrTotpNotEnabled
+	} else if err != nil {
+		return err
+	}
+	if userTotp.EnabledAt == nil {
+		return ErrTotpNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		step, ok, err := totp.Validate(userTotp.Secret, code, time.Now())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidTotpCode
+		}
+		used, err := m.totpRepository.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTotpCode
+		}
+		return nil
+	}
+
+	if !allowBackupCode {
+		return ErrInvalidTotpCode
+	}
+	used, err := m.totpRepository.UseBackupCode(ctx, userID, hashVerificationCode(normalizeBackupCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTotpCode
+	}
+	return nil
+}
+
+// generateBackupCodes returns the codes shown once to the user and the hashes that are stored
+func generateBackupCodes() ([]string, []string, error) {
+	codes := make([]string, backupCodeCount)
+	hashes := make([]string, backupCodeCount)
+	for i := range codes {
+		code, err := GenerateRandomHex(backupCodeLength)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code[:backupCodeLength/2] + "-" + code[backupCodeLength/2:]
+		hashes[i] = hashVerificationCode(code)
+	}
+	return codes, hashes, nil
+}
+
+func normalizeBackupCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func totpAccountName(user *port.User) string {
+	if user.Email != "" {
+		return user.Email
+	}
+	if user.Phone != nil {
+		return *user.Phone
+	}
+	return user.Username
+}
@@ -0,0 +1,72 @@
+package auth_manager
+
+import (
+	"context"
+	"ketalk-api/pkg/manager/auth/repository"
+	"ketalk-api/totp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeTotpRepository keeps a single enabled secret, UseStep accepts a step the way the update of the repository does
+type fakeTotpRepository struct {
+	repository.TotpRepository
+	totp repository.UserTotp
+}
+
+func (r *fakeTotpRepository) GetTotp(ctx context.Context, userID uuid.UUID) (*repository.UserTotp, error) {
+	userTotp := r.totp
+	return &userTotp, nil
+}
+
+func (r *fakeTotpRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	if r.totp.LastUsedStep >= step {
+		return false, nil
+	}
+	r.totp.LastUsedStep = step
+	return true, nil
+}
+
+func TestCheckSecondFactorStepReplay(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabledAt := time.Now()
+	m := &authManager{
+		totpRepository: &fakeTotpRepository{
+			totp: repository.UserTotp{Secret: secret, EnabledAt: &enabledAt},
+		},
+	}
+	code := func(t *testing.T, at time.Time) string {
+		code, err := totp.Generate(secret, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	now := time.Now()
+	current, previous, next := code(t, now), code(t, now.Add(-totp.Period)), code(t, now.Add(totp.Period))
+	// the cases run in order against the same repository
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "current code", code: current},
+		{name: "current code again", code: current, wantErr: ErrInvalidTotpCode},
+		{name: "code of an earlier period", code: previous, wantErr: ErrInvalidTotpCode},
+		{name: "code of the next period", code: next},
+		{name: "current code after the next one", code: current, wantErr: ErrInvalidTotpCode},
+		{name: "backup code not allowed", code: "abcd-efgh", wantErr: ErrInvalidTotpCode},
+	}
+	for _, tt := range tests {
+		err := m.checkSecondFactor(context.Background(), uuid.New(), tt.code, false)
+		if err != tt.wantErr {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
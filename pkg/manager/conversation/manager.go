@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"ketalk-api/common"
 	conn_redis "ketalk-api/pkg/manager/conversation/redis"
 	"ketalk-api/pkg/manager/conversation/repository"
 	"ketalk-api/pkg/manager/conversation/ws"
@@ -52,12 +53,32 @@ func NewConversationManager(
 }
 
 func (c *conversationManager) CreateConversation(ctx context.Context, request CreateConversationRequest) (*CreateConversationResponse, error) {
+	user, err := c.userPort.GetUser(ctx, request.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.Verified {
+		return nil, common.ErrUserNotVerified
+	}
+
+	item, err := c.itemPort.GetItem(ctx, request.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OwnerID == request.UserID {
+		return nil, errors.New("user owns the item")
+	}
+	// a block in either direction ends the contact, existing conversations included
+	blocked, err := c.userPort.HasBlockBetween(ctx, request.UserID, item.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ErrUserBlocked
+	}
+
 	// 1. check if conversation already exists
 	if conversation, err := c.conversationRepo.GetConversation(ctx, request.ItemID, request.UserID); err == nil {
-		item, err := c.itemPort.GetItem(ctx, request.ItemID)
-		if err != nil {
-			return nil, err
-		}
 		return &CreateConversationResponse{
 			ID:              conversation.ID,
 			SecondaryUserID: item.OwnerID,
@@ -75,15 +96,6 @@ func (c *conversationManager) CreateConversation(ctx context.Context, request Cr
 		return nil, err
 	}
 
-	item, err := c.itemPort.GetItem(ctx, request.ItemID)
-	if err != nil {
-		return nil, err
-	}
-
-	if item.OwnerID == request.UserID {
-		return nil, errors.New("user owns the item")
-	}
-
 	// 3. Create members
 	var members []repository.Member = make([]repository.Member, 2)
 	members[0] = repository.Member{
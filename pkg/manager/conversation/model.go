@@ -2,6 +2,8 @@ package conversation_manager
 
 import (
 	"context"
+	"fmt"
+	"ketalk-api/common"
 	"time"
 
 	"github.com/google/uuid"
@@ -45,6 +47,15 @@ type Member struct {
 	LastSeenAt time.Time
 }
 
+var ErrUserBlocked = fmt.Errorf("%w: user is blocked", common.ErrForbidden)
+
+func init() {
+	common.RegisterErrorMessages(ErrUserBlocked, map[common.Locale]string{
+		common.LocaleUz: "foydalanuvchi bloklangan",
+		common.LocaleRu: "пользователь заблокирован",
+	})
+}
+
 type ConversationManager interface {
 	CreateConversation(ctx context.Context, req CreateConversationRequest) (*CreateConversationResponse, error)
 	GetConversations(ctx context.Context, req GetConversationsRequest) ([]Conversation, error)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"ketalk-api/pkg/manager/conversation/repository"
 	"ketalk-api/pkg/manager/port"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -52,3 +53,77 @@ func (c *conversationPort) GetItemConversations(ctx context.Context, itemID uuid
 	}
 	return conversationPorts, nil
 }
+
+func (c *conversationPort) AnonymizeSender(ctx context.Context, userID uuid.UUID) error {
+	return c.messageRepo.AnonymizeSender(ctx, userID)
+}
+
+func (c *conversationPort) ExportUserConversations(ctx context.Context, userID uuid.UUID) ([]port.ExportedConversation, error) {
+	conversations, err := c.memberRepo.GetConversations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	exported := make([]port.ExportedConversation, len(conversations))
+	for i, conversation := range conversations {
+		messages, err := c.messageRepo.GetMessages(ctx, conversation.Conversation.ID)
+		if err != nil {
+			return nil, err
+		}
+		exportedMessages := make([]port.ExportedMessage, len(messages))
+		for j, message := range messages {
+			exportedMessages[j] = port.ExportedMessage{
+				ID:        message.ID,
+				SenderID:  message.SenderID,
+				Message:   message.Message,
+				CreatedAt: message.CreatedAt,
+			}
+		}
+		exported[i] = port.ExportedConversation{
+			ID:        conversation.Conversation.ID,
+			ItemID:    conversation.Conversation.ItemID,
+			CreatedAt: conversation.Conversation.CreatedAt,
+			Messages:  exportedMessages,
+		}
+	}
+	return exported, nil
+}
+
+func (c *conversationPort) GetUserResponseStats(ctx context.Context, userID uuid.UUID) (*port.ResponseStats, error) {
+	stats, err := c.messageRepo.GetResponseStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &port.ResponseStats{
+		ReceivedConversations:  stats.ReceivedCount,
+		RespondedConversations: stats.RespondedCount,
+		LastMessagedAt:         stats.LastMessagedAt,
+	}
+	if stats.AverageResponseSeconds != nil {
+		averageResponseTime := time.Duration(*stats.AverageResponseSeconds * float64(time.Second))
+		resp.AverageResponseTime = &averageResponseTime
+	}
+	return resp, nil
+}
+
+func (c *conversationPort) GetMessage(ctx context.Context, messageID uuid.UUID) (*port.Message, error) {
+	message, err := c.messageRepo.GetMessage(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := c.memberRepo.GetMembers(ctx, message.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs := make([]uuid.UUID, len(members))
+	for i, member := range members {
+		memberIDs[i] = member.MemberID
+	}
+	return &port.Message{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		SenderID:       message.SenderID,
+		Message:        message.Message,
+		CreatedAt:      message.CreatedAt,
+		MemberIDs:      memberIDs,
+	}, nil
+}
@@ -30,7 +30,8 @@ type redisClient struct {
 	groupIds []int
 }
 
-func Init(ctx context.Context, cfg Config) (RedisClient, error) {
+// Connect opens the connection shared by the conversation messages and the redis clients of the other managers
+func Connect(ctx context.Context, cfg Config) (*redis.Client, error) {
 	tlsConfig := &tls.Config{
 		MinVersion: tls.VersionTLS12,
 	}
@@ -50,7 +51,10 @@ func Init(ctx context.Context, cfg Config) (RedisClient, error) {
 		return nil, err
 	}
 	log.Printf("connected to redis: %v\n", pong)
+	return conn, nil
+}
 
+func NewRedisClient(conn *redis.Client, cfg Config) RedisClient {
 	var groupIds []int = make([]int, cfg.GroupCount)
 	for i := 0; i < cfg.GroupCount; i++ {
 		groupIds[i] = i
@@ -61,7 +65,7 @@ func Init(ctx context.Context, cfg Config) (RedisClient, error) {
 		cfg:      cfg,
 		groupIds: groupIds,
 	}
-	return &redisClient, nil
+	return &redisClient
 }
 
 func (c *redisClient) AddMessage(ctx context.Context, groupID int, conversationID uuid.UUID, message interface{}) error {
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"ketalk-api/common"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -43,6 +45,50 @@ func (r *messageRepository) GetLastMessage(ctx context.Context, conversationID u
 	return &message, nil
 }
 
+func (r *messageRepository) GetMessage(ctx context.Context, messageID uuid.UUID) (*Message, error) {
+	var message Message
+	resp := r.Where("id = ?", messageID).First(&message)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &message, nil
+}
+
+func (r *messageRepository) AnonymizeSender(ctx context.Context, senderID uuid.UUID) error {
+	return r.Model(&Message{}).Where("sender_id = ?", senderID).Update("sender_id", common.DeletedUserID).Error
+}
+
+// GetResponseStats only counts the conversations where the other member sent the first message,
+// the response time is the time until the user's first message after it
+func (r *messageRepository) GetResponseStats(ctx context.Context, userID uuid.UUID) (*ResponseStats, error) {
+	var stats ResponseStats
+	resp := r.Raw(`
+		WITH firsts AS (
+			SELECT message.conversation_id,
+				MIN(message.created_at) FILTER (WHERE message.sender_id <> @user) AS first_received_at,
+				MIN(message.created_at) FILTER (WHERE message.sender_id = @user) AS first_sent_at
+			FROM ketalk.message
+			INNER JOIN ketalk.member ON member.convresation_id = message.conversation_id
+			WHERE member.member_id = @user AND message.deleted_at IS NULL
+			GROUP BY message.conversation_id
+		)
+		SELECT COUNT(*) AS received_count,
+			COUNT(first_sent_at) AS responded_count,
+			AVG(EXTRACT(EPOCH FROM first_sent_at - first_received_at)) AS average_response_seconds
+		FROM firsts
+		WHERE first_received_at IS NOT NULL AND (first_sent_at IS NULL OR first_sent_at > first_received_at)`,
+		sql.Named("user", userID),
+	).Scan(&stats)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	resp = r.Model(&Message{}).Select("MAX(created_at)").Where("sender_id = ?", userID).Scan(&stats.LastMessagedAt)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &stats, nil
+}
+
 func (r *messageRepository) Migrate() error {
 	return r.AutoMigrate(&Message{})
 }
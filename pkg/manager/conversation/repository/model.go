@@ -30,6 +30,15 @@ type Message struct {
 	common.CreatedUpdatedDeleted
 }
 
+// ResponseStats describes how a user answers the conversations started by others
+type ResponseStats struct {
+	ReceivedCount  int64
+	RespondedCount int64
+	// AverageResponseSeconds is nil when the user never responded
+	AverageResponseSeconds *float64
+	LastMessagedAt         *time.Time
+}
+
 type ConversationMember struct {
 	Conversation Conversation `gorm:"embedded"`
 	Member       Member       `gorm:"embedded"`
@@ -54,5 +63,8 @@ type MessageRepository interface {
 	AddMessage(ctx context.Context, message *Message) error
 	GetMessages(ctx context.Context, conversationID uuid.UUID) ([]Message, error)
 	GetLastMessage(ctx context.Context, conversationID uuid.UUID) (*Message, error)
+	GetMessage(ctx context.Context, messageID uuid.UUID) (*Message, error)
+	AnonymizeSender(ctx context.Context, senderID uuid.UUID) error
+	GetResponseStats(ctx context.Context, userID uuid.UUID) (*ResponseStats, error)
 	Migrate() error
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	conn_redis "ketalk-api/pkg/manager/conversation/redis"
+	"ketalk-api/pkg/manager/port"
 	"log"
 	"sync"
 	"time"
@@ -13,24 +14,34 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// blockListTTL is how long the users blocked by the user of a connection are cached,
+// a block takes effect on open connections after it at the latest
+const blockListTTL = time.Minute
+
 type Client struct {
 	conn           *websocket.Conn
 	ConversationID uuid.UUID
 	UserID         uuid.UUID
 	UserName       string
 	GroupID        int
+
+	blocksLock     sync.Mutex
+	blockedUserIDs map[uuid.UUID]bool
+	blocksLoadedAt time.Time
 }
 
 type Receiver struct {
-	clients map[uuid.UUID]map[uuid.UUID]*Client
-	lock    sync.RWMutex
-	redis   conn_redis.RedisClient
+	clients  map[uuid.UUID]map[uuid.UUID]*Client
+	lock     sync.RWMutex
+	redis    conn_redis.RedisClient
+	userPort port.UserPort
 }
 
-func NewReceiver(ctx context.Context, redis conn_redis.RedisClient) *Receiver {
+func NewReceiver(ctx context.Context, redis conn_redis.RedisClient, userPort port.UserPort) *Receiver {
 	receiver := Receiver{
-		clients: make(map[uuid.UUID]map[uuid.UUID]*Client),
-		redis:   redis,
+		clients:  make(map[uuid.UUID]map[uuid.UUID]*Client),
+		redis:    redis,
+		userPort: userPort,
 	}
 	// start redis message handler
 	go receiver.redis.Handle(ctx, receiver.HandleMessage, "websocket")
@@ -84,6 +95,9 @@ func (r *Receiver) HandleMessage(ctx context.Context, payload string) error {
 		return fmt.Errorf("no clients for conversationId: %s", mes.ConversationID)
 	}
 	for _, client := range r.clients[mes.ConversationID] {
+		if client.UserID != mes.UserID && r.isBlocked(ctx, client, mes.UserID) {
+			continue
+		}
 		fmt.Printf("sending message to client: %+v\n", client.UserID)
 		messages := ServerToActorMessages{
 			Messages: []ServerToActorMessage{
@@ -104,6 +118,29 @@ func (r *Receiver) HandleMessage(ctx context.Context, payload string) error {
 	return nil
 }
 
+// isBlocked reports whether the user of the connection blocked the sender, from the cached block list of the connection.
+// The message is dropped when the block list cannot be loaded, an outdated one is used rather than none.
+func (r *Receiver) isBlocked(ctx context.Context, client *Client, senderID uuid.UUID) bool {
+	client.blocksLock.Lock()
+	defer client.blocksLock.Unlock()
+	if client.blockedUserIDs == nil || time.Since(client.blocksLoadedAt) > blockListTTL {
+		blockedUserIDs, err := r.userPort.GetBlockedUserIDs(ctx, client.UserID)
+		if err != nil {
+			log.Printf("failed to get users blocked by %s: %v\n", client.UserID, err)
+			if client.blockedUserIDs == nil {
+				return true
+			}
+		} else {
+			client.blockedUserIDs = make(map[uuid.UUID]bool, len(blockedUserIDs))
+			for _, id := range blockedUserIDs {
+				client.blockedUserIDs[id] = true
+			}
+			client.blocksLoadedAt = time.Now()
+		}
+	}
+	return client.blockedUserIDs[senderID]
+}
+
 func (r *Receiver) GetClient(userId, conversationId uuid.UUID) *Client {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -2,6 +2,7 @@ package ws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"ketalk-api/common"
 	"ketalk-api/common/response"
@@ -12,10 +13,12 @@ import (
 
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"gorm.io/gorm"
 )
 
 type WebSocketServer struct {
@@ -41,7 +44,7 @@ func NewWebSocketServer(
 	r.Use(middleware.AuthMiddleware(cfg))
 
 	webSocketServer := WebSocketServer{
-		receiver: NewReceiver(ctx, redis),
+		receiver: NewReceiver(ctx, redis, userPort),
 		upgrader: &websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -56,7 +59,7 @@ func NewWebSocketServer(
 		_, err := middleware.HandlerWithAuth(webSocketServer.serveWs)(ctx, ctx.Request)
 		if err != nil {
 			fmt.Printf("failed to serve web socket connection: %+v\n", err)
-			sendErr := response.NewError(err, http.StatusInternalServerError).Send(ctx.Writer)
+			sendErr := response.NewError(err, common.StatusCode(err)).Send(ctx.Writer)
 			if sendErr != nil {
 				log.Printf("failed to send failure response for web socket conn: %v, failure: %+v", sendErr, err)
 			}
@@ -94,18 +97,32 @@ func (s *WebSocketServer) serveWs(ctx *gin.Context, req *http.Request) (interfac
 	}
 
 	// check if user is member of conversation
-	if members, err := s.memberRepo.GetMembers(ctx, conversationId); err != nil {
+	members, err := s.memberRepo.GetMembers(ctx, conversationId)
+	if err != nil {
 		return nil, err
-	} else {
-		isMember := false
-		for _, member := range members {
-			if member.MemberID == userId {
-				isMember = true
-				break
-			}
+	}
+	isMember := false
+	for _, member := range members {
+		if member.MemberID == userId {
+			isMember = true
+			break
 		}
-		if !isMember {
-			return nil, fmt.Errorf("user is not a member of conversation")
+	}
+	if !isMember {
+		return nil, fmt.Errorf("user is not a member of conversation")
+	}
+
+	// messages sent by a blocked member since the block are not shown
+	blockedSince := make(map[uuid.UUID]time.Time)
+	for _, member := range members {
+		if member.MemberID == userId {
+			continue
+		}
+		block, err := s.userPort.GetBlock(ctx, userId, member.MemberID)
+		if err == nil {
+			blockedSince[member.MemberID] = block.BlockedAt
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
 		}
 	}
 
@@ -128,14 +145,17 @@ func (s *WebSocketServer) serveWs(ctx *gin.Context, req *http.Request) (interfac
 	if err != nil {
 		return nil, err
 	}
-	var messages []ServerToActorMessage = make([]ServerToActorMessage, len(mes))
-	for i, m := range mes {
-		messages[i] = ServerToActorMessage{
+	var messages []ServerToActorMessage = make([]ServerToActorMessage, 0, len(mes))
+	for _, m := range mes {
+		if blockedAt, ok := blockedSince[m.SenderID]; ok && !m.CreatedAt.Before(blockedAt) {
+			continue
+		}
+		messages = append(messages, ServerToActorMessage{
 			Message:     m.Message,
 			SenderID:    m.SenderID,
 			CreatedAt:   m.CreatedAt.UTC().Unix(),
 			MessageType: MessageTypeMessage,
-		}
+		})
 	}
 
 	if err := client.conn.WriteJSON(ServerToActorMessages{
@@ -13,6 +13,10 @@ import (
 var GlobalGeofence = repository.Geofence{
 	ID:   uuid.UUID{},
 	Name: "Globe Geofence",
+	Locales: repository.GeofenceLocales{
+		"uz": "Butun dunyo",
+		"ru": "Весь мир",
+	},
 }
 
 type geofencePort struct {
@@ -29,35 +33,35 @@ func (p *geofencePort) GetGeofenceByLocation(ctx context.Context, location commo
 	geofence, err := p.geofenceRepository.FindGeofeceByLocation(ctx, location)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return &port.Geofence{
-				ID:   GlobalGeofence.ID,
-				Name: GlobalGeofence.Name,
-			}, nil
+			return toGeofence(ctx, &GlobalGeofence), nil
 		}
 		return nil, err
 	}
 
-	return &port.Geofence{
-		ID:   geofence.ID,
-		Name: geofence.Name,
-	}, nil
+	return toGeofence(ctx, geofence), nil
 }
 
 func (p *geofencePort) GetGeofenceById(ctx context.Context, id uuid.UUID) (*port.Geofence, error) {
 	emptyUuid := uuid.UUID{}
 	if id == emptyUuid {
-		return &port.Geofence{
-			ID:   GlobalGeofence.ID,
-			Name: GlobalGeofence.Name,
-		}, nil
+		return toGeofence(ctx, &GlobalGeofence), nil
 	}
 
 	geofence, err := p.geofenceRepository.GetGeofenceByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	return toGeofence(ctx, geofence), nil
+}
+
+// toGeofence names the geofence in the locale of the request
+func toGeofence(ctx context.Context, geofence *repository.Geofence) *port.Geofence {
+	name, ok := common.Localize(geofence.Locales, common.GetLocale(ctx))
+	if !ok {
+		name = geofence.Name
+	}
 	return &port.Geofence{
 		ID:   geofence.ID,
-		Name: geofence.Name,
-	}, nil
+		Name: name,
+	}
 }
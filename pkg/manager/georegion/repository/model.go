@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"ketalk-api/common"
 
 	"github.com/google/uuid"
@@ -11,9 +13,25 @@ type Geofence struct {
 	ID   uuid.UUID `gorm:"type:uuid;primary_key;"`
 	Name string    `gorm:"type:varchar(255);"`
 	Geom []byte    `gorm:"type:geometry(Polygon,4326)"`
+	// Locales are the names of the geofence per locale, Name is used without a translation
+	Locales GeofenceLocales `gorm:"type:json"`
 	common.CreatedDeleted
 }
 
+type GeofenceLocales map[string]string
+
+func (gl *GeofenceLocales) Scan(value interface{}) error {
+	// geofences created before the names were translated have none
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, gl)
+}
+
 type GeofenceRepository interface {
 	GetGeofenceByID(ctx context.Context, id uuid.UUID) (*Geofence, error)
 	FindGeofeceByLocation(ctx context.Context, location common.Location) (*Geofence, error)
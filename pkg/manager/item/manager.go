@@ -2,16 +2,22 @@ package item_manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"ketalk-api/common"
 	"ketalk-api/pkg/manager/item/repository"
 	"ketalk-api/pkg/manager/port"
 	"ketalk-api/storage"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// bumpCooldown is how long an item has to wait between bumps, also after it was listed
+const bumpCooldown = 24 * time.Hour
+
 type itemManager struct {
 	itemRepository      repository.ItemRepository
 	itemImageRepository repository.ItemImageRepository
@@ -39,6 +45,14 @@ func NewItemManager(itemRepository repository.ItemRepository, itemImageRepositor
 }
 
 func (m *itemManager) AddItem(ctx context.Context, item AddItemRequest) (*AddItemResponse, error) {
+	owner, err := m.userPort.GetUser(ctx, item.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if !owner.Verified {
+		return nil, common.ErrUserNotVerified
+	}
+
 	geofence, err := m.geofencePort.GetGeofenceByLocation(ctx, item.Location)
 	if err != nil {
 		return nil, err
@@ -94,18 +108,33 @@ func (m *itemManager) AddItem(ctx context.Context, item AddItemRequest) (*AddIte
 	}, nil
 }
 
-func (m *itemManager) GetItems(ctx context.Context, req GetItemsRequest) ([]ItemBlock, error) {
+func (m *itemManager) GetItems(ctx context.Context, req GetItemsRequest) (*ItemBlocksPage, error) {
+	page, limit, err := toRepositoryPage(req.PageRequest, req.Sort)
+	if err != nil {
+		return nil, err
+	}
 	geofence, err := m.geofencePort.GetGeofenceByLocation(ctx, req.Location)
 	if err != nil {
 		return nil, err
 	}
 
-	items, err := m.itemRepository.GetItems(ctx, geofence.ID, req.UserID)
+	blockedUserIDs, err := m.userPort.GetBlockedUserIDs(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := m.itemRepository.GetItems(ctx, geofence.ID, req.UserID, blockedUserIDs, page)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := nextPage(items, limit, req.Sort)
 	if err != nil {
 		return nil, err
 	}
 
-	return m.repoItemIntoItemBlocks(ctx, items), nil
+	return &ItemBlocksPage{
+		Items:      m.repoItemIntoItemBlocks(ctx, items),
+		NextCursor: next,
+	}, nil
 }
 
 func (m *itemManager) GetItem(ctx context.Context, req GetItemRequest) (*GetItemResponse, error) {
@@ -196,8 +225,16 @@ func (m *itemManager) UploadItemImages(ctx context.Context, r UploadItemImagesRe
 	return &UploadItemImagesResponse{}, nil
 }
 
-func (m *itemManager) GetFavoriteItems(ctx context.Context, r GetFavoriteItemsRequest) ([]ItemBlock, error) {
-	items, err := m.userItemRepository.GetUserFavoriteItems(ctx, r.UserID)
+func (m *itemManager) GetFavoriteItems(ctx context.Context, r GetFavoriteItemsRequest) (*ItemBlocksPage, error) {
+	page, limit, err := toRepositoryPage(r.PageRequest, ItemSortNewest)
+	if err != nil {
+		return nil, err
+	}
+	items, err := m.userItemRepository.GetUserFavoriteItems(ctx, r.UserID, page)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := nextPage(items, limit, ItemSortNewest)
 	if err != nil {
 		return nil, err
 	}
@@ -224,7 +261,10 @@ func (m *itemManager) GetFavoriteItems(ctx context.Context, r GetFavoriteItemsRe
 			IsHidden:      item.IsHidden,
 		})
 	}
-	return resp, nil
+	return &ItemBlocksPage{
+		Items:      resp,
+		NextCursor: next,
+	}, nil
 }
 
 func (m *itemManager) FavoriteItem(ctx context.Context, req FavoriteItemRequest) (*FavoriteItemResponse, error) {
@@ -260,8 +300,16 @@ func (m *itemManager) FavoriteItem(ctx context.Context, req FavoriteItemRequest)
 	return &FavoriteItemResponse{}, nil
 }
 
-func (m *itemManager) GetUserItems(ctx context.Context, req GetUserItemsRequest) ([]ItemBlock, error) {
-	items, err := m.itemRepository.GetUserItems(ctx, req.UserID)
+func (m *itemManager) GetUserItems(ctx context.Context, req GetUserItemsRequest) (*ItemBlocksPage, error) {
+	page, limit, err := toRepositoryPage(req.PageRequest, ItemSortNewest)
+	if err != nil {
+		return nil, err
+	}
+	items, err := m.itemRepository.GetUserItems(ctx, req.UserID, page)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := nextPage(items, limit, ItemSortNewest)
 	if err != nil {
 		return nil, err
 	}
@@ -288,11 +336,22 @@ func (m *itemManager) GetUserItems(ctx context.Context, req GetUserItemsRequest)
 			IsHidden:      item.IsHidden,
 		})
 	}
-	return resp, nil
+	return &ItemBlocksPage{
+		Items:      resp,
+		NextCursor: next,
+	}, nil
 }
 
-func (m *itemManager) GetPurchasedItems(ctx context.Context, req GetPurchasedItemsRequest) ([]ItemBlock, error) {
-	items, err := m.userItemRepository.GetPurchasedItems(ctx, req.UserID)
+func (m *itemManager) GetPurchasedItems(ctx context.Context, req GetPurchasedItemsRequest) (*ItemBlocksPage, error) {
+	page, limit, err := toRepositoryPage(req.PageRequest, ItemSortNewest)
+	if err != nil {
+		return nil, err
+	}
+	items, err := m.userItemRepository.GetPurchasedItems(ctx, req.UserID, page)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := nextPage(items, limit, ItemSortNewest)
 	if err != nil {
 		return nil, err
 	}
@@ -319,7 +378,10 @@ func (m *itemManager) GetPurchasedItems(ctx context.Context, req GetPurchasedIte
 			IsHidden:      item.IsHidden,
 		})
 	}
-	return resp, nil
+	return &ItemBlocksPage{
+		Items:      resp,
+		NextCursor: next,
+	}, nil
 }
 
 func (m *itemManager) UpdateItem(ctx context.Context, req UpdateItemRequest) (*UpdateItemResponse, error) {
@@ -332,6 +394,9 @@ func (m *itemManager) UpdateItem(ctx context.Context, req UpdateItemRequest) (*U
 		return nil, fmt.Errorf("user is not owner of item")
 	}
 	if req.IsHidden != nil {
+		if !*req.IsHidden && item.ModeratedAt != nil {
+			return nil, ErrItemModerated
+		}
 		item.IsHidden = *req.IsHidden
 	}
 	if req.ItemStatus != nil {
@@ -473,6 +538,7 @@ func (m *itemManager) GetAllKarats(ctx context.Context) ([]Karat, error) {
 		return nil, err
 	}
 
+	locale := common.GetLocale(ctx)
 	var resp []Karat = make([]Karat, len(karats))
 	for i, karat := range karats {
 		var locales map[string]KaratLocale = make(map[string]KaratLocale, len(karat.Locales))
@@ -487,6 +553,10 @@ func (m *itemManager) GetAllKarats(ctx context.Context) ([]Karat, error) {
 			Name:    karat.Name,
 			Locales: locales,
 		}
+		if translation, ok := common.Localize(karat.Locales, locale); ok {
+			resp[i].Name = translation.Name
+			resp[i].Description = translation.Description
+		}
 	}
 	return resp, nil
 }
@@ -496,6 +566,7 @@ func (m *itemManager) GetAllCategories(ctx context.Context) ([]Category, error)
 	if err != nil {
 		return nil, err
 	}
+	locale := common.GetLocale(ctx)
 	var resp []Category = make([]Category, len(categories))
 	for i, category := range categories {
 		var locales map[string]CategoryLocale = make(map[string]CategoryLocale, len(category.Locales))
@@ -510,6 +581,10 @@ func (m *itemManager) GetAllCategories(ctx context.Context) ([]Category, error)
 			Name:    category.Name,
 			Locales: locales,
 		}
+		if translation, ok := common.Localize(category.Locales, locale); ok {
+			resp[i].Name = translation.Name
+			resp[i].Description = translation.Description
+		}
 	}
 	return resp, nil
 }
@@ -527,15 +602,25 @@ func (m *itemManager) GetSimilarItems(ctx context.Context, req GetSimilarItemsRe
 		totalItemsCount := 20
 		userOtherItemsCount := 2
 
-		repoUserOtherItems, err := m.itemRepository.GetLimitedUserItems(ctx, item.OwnerID, userOtherItemsCount)
+		blockedUserIDs, err := m.userPort.GetBlockedUserIDs(ctx, req.UserID)
 		if err != nil {
 			return nil, err
 		}
+		// the other items of a blocked owner are not shown either
+		repoUserOtherItems := make([]repository.Item, 0)
+		if !slices.Contains(blockedUserIDs, item.OwnerID) {
+			repoUserOtherItems, err = m.itemRepository.GetLimitedUserItems(ctx, item.OwnerID, userOtherItemsCount)
+			if err != nil {
+				return nil, err
+			}
+		}
 		otherUserItems = m.repoItemIntoItemBlocks(ctx, repoUserOtherItems)
 
 		suggestedItemsCount := totalItemsCount - len(repoUserOtherItems)
 
-		repoSuggestedItems, err := m.itemRepository.GetLimitedItemsByCategoryOrKarat(ctx, item.OwnerID, item.CategoryID, item.KaratID, suggestedItemsCount)
+		// appending could write into the array of blockedUserIDs
+		ownerIDsToExclude := append(slices.Clone(blockedUserIDs), item.OwnerID)
+		repoSuggestedItems, err := m.itemRepository.GetLimitedItemsByCategoryOrKarat(ctx, ownerIDsToExclude, item.CategoryID, item.KaratID, suggestedItemsCount)
 		if err != nil {
 			return nil, err
 		}
@@ -588,6 +673,32 @@ func (m *itemManager) GetItemBuyers(ctx context.Context, req GetItemBuyersReques
 }
 
 func (m *itemManager) CreatePurchase(ctx context.Context, req CreatePurchaseRequest) (*CreatePurchaseResponse, error) {
+	// purchases allow reviews, so they cannot be recorded by anyone but the seller
+	item, err := m.itemRepository.GetItem(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OwnerID != req.SellerID {
+		return nil, ErrNotItemOwner
+	}
+	if req.BuyerID == req.SellerID {
+		return nil, ErrInvalidBuyer
+	}
+	// the buyer has to have talked to the seller about the item, so the seller can not pick any user to review
+	if err := m.checkItemConversation(ctx, req.ItemID, req.BuyerID); err != nil {
+		return nil, err
+	}
+	// an item is sold once
+	buyers, err := m.userItemRepository.GetItemBuyer(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, buyer := range buyers {
+		if buyer.UserID != req.BuyerID {
+			return nil, ErrItemAlreadyPurchased
+		}
+	}
+
 	userItem, err := m.userItemRepository.GetUserItem(ctx, req.BuyerID, req.ItemID)
 	if err != nil && err != gorm.ErrRecordNotFound {
 		return nil, err
@@ -598,37 +709,101 @@ func (m *itemManager) CreatePurchase(ctx context.Context, req CreatePurchaseRequ
 			ItemID:      req.ItemID,
 			IsPurchased: true,
 		}
-		if err := m.userItemRepository.Insert(ctx, userItem); err != nil {
-			return nil, err
-		}
-		return &CreatePurchaseResponse{
-			ItemID:  req.ItemID,
-			BuyerID: req.BuyerID,
-		}, nil
+		err = m.userItemRepository.Insert(ctx, userItem)
 	} else {
 		userItem.IsPurchased = true
-		if err := m.userItemRepository.Update(ctx, userItem); err != nil {
-			return nil, err
+		err = m.userItemRepository.Update(ctx, userItem)
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// another buyer was recorded at the same time
+		return nil, ErrItemAlreadyPurchased
+	} else if err != nil {
+		return nil, err
+	}
+	return &CreatePurchaseResponse{
+		ItemID:  req.ItemID,
+		BuyerID: req.BuyerID,
+	}, nil
+}
+
+func (m *itemManager) checkItemConversation(ctx context.Context, itemID uuid.UUID, userID uuid.UUID) error {
+	conversations, err := m.conversationPort.GetItemConversations(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	for _, conversation := range conversations {
+		for _, member := range conversation.Members {
+			if member.MemberID == userID {
+				return nil
+			}
 		}
-		return &CreatePurchaseResponse{
-			ItemID:  req.ItemID,
-			BuyerID: req.BuyerID,
-		}, nil
 	}
+	return ErrBuyerNotInConversation
 }
 
-func (m *itemManager) SearchItems(ctx context.Context, req SearchItemsRequest) ([]ItemBlock, error) {
-	items, err := m.itemRepository.SearchItems(ctx, req.Keyword, req.PriceRange, req.SizeRange, req.KaratIDs, req.CategoryIDs)
+func (m *itemManager) SearchItems(ctx context.Context, req SearchItemsRequest) (*ItemBlocksPage, error) {
+	page, limit, err := toRepositoryPage(req.PageRequest, req.Sort)
 	if err != nil {
 		return nil, err
 	}
-	return m.repoItemIntoItemBlocks(ctx, items), nil
+	var blockedUserIDs []uuid.UUID
+	if req.UserID != nil {
+		userBlockedIDs, err := m.userPort.GetBlockedUserIDs(ctx, *req.UserID)
+		if err != nil {
+			return nil, err
+		}
+		blockedUserIDs = userBlockedIDs
+	}
+	items, err := m.itemRepository.SearchItems(ctx, req.Keyword, req.PriceRange, req.SizeRange, req.KaratIDs, req.CategoryIDs, blockedUserIDs, page)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := nextPage(items, limit, req.Sort)
+	if err != nil {
+		return nil, err
+	}
+	return &ItemBlocksPage{
+		Items:      m.repoItemIntoItemBlocks(ctx, items),
+		NextCursor: next,
+	}, nil
 }
 
 func (m *itemManager) DeleteItem(ctx context.Context, itemID uuid.UUID) error {
 	return m.itemRepository.DeleteItem(ctx, itemID)
 }
 
+func (m *itemManager) BumpItem(ctx context.Context, req BumpItemRequest) (*BumpItemResponse, error) {
+	item, err := m.itemRepository.GetItem(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.OwnerID != req.UserID {
+		return nil, ErrNotItemOwner
+	}
+	if item.ModeratedAt != nil {
+		return nil, ErrItemModerated
+	}
+	now := time.Now()
+	// a new item counts as bumped when it was listed
+	lastBumpedAt := item.CreatedAt
+	if item.BumpedAt != nil {
+		lastBumpedAt = *item.BumpedAt
+	}
+	if next := lastBumpedAt.Add(bumpCooldown); now.Before(next) {
+		return nil, &common.RetryAfterError{
+			Err:        ErrBumpCooldown,
+			RetryAfter: next.Sub(now),
+		}
+	}
+	if err := m.itemRepository.BumpItem(ctx, item.ID, now); err != nil {
+		return nil, err
+	}
+	return &BumpItemResponse{
+		BumpedAt:   now,
+		NextBumpAt: now.Add(bumpCooldown),
+	}, nil
+}
+
 func (m *itemManager) repoItemIntoItemBlocks(ctx context.Context, repoItems []repository.Item) []ItemBlock {
 	var userOtherItems []ItemBlock = make([]ItemBlock, len(repoItems))
 	for i, item := range repoItems {
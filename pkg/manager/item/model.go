@@ -91,9 +91,24 @@ type Geofence struct {
 	Name string
 }
 
+// PageRequest asks for the items after the cursor of the previous page, newest first unless a sort is given
+type PageRequest struct {
+	// Cursor is the NextCursor of the previous page, empty for the first page
+	Cursor string
+	Limit  int
+}
+
+type ItemBlocksPage struct {
+	Items []ItemBlock
+	// NextCursor is nil on the last page
+	NextCursor *string
+}
+
 type GetItemsRequest struct {
 	Location common.Location
 	UserID   uuid.UUID
+	Sort     ItemSort
+	PageRequest
 }
 
 type UploadItemImagesRequest struct {
@@ -111,6 +126,7 @@ type GetItemRequest struct {
 
 type GetFavoriteItemsRequest struct {
 	UserID uuid.UUID
+	PageRequest
 }
 type FavoriteItemRequest struct {
 	UserID     uuid.UUID
@@ -123,10 +139,12 @@ type FavoriteItemResponse struct {
 
 type GetUserItemsRequest struct {
 	UserID uuid.UUID
+	PageRequest
 }
 
 type GetPurchasedItemsRequest struct {
 	UserID uuid.UUID
+	PageRequest
 }
 
 type UpdateItemRequest struct {
@@ -160,9 +178,14 @@ type IncrementConversationCountRequest struct {
 	UserID uuid.UUID
 }
 
+// Karat is localized to the locale of the request, Name falls back to the plain name without any translation
 type Karat struct {
-	ID      uuid.UUID
-	Name    string
+	ID          uuid.UUID
+	Name        string
+	Description string
+	// Locales holds every translation, for the clients that pick one themselves.
+	//
+	// Deprecated: kept for one release, Name and Description are localized already.
 	Locales map[string]KaratLocale
 }
 
@@ -171,9 +194,14 @@ type KaratLocale struct {
 	Name        string
 }
 
+// Category is localized to the locale of the request, Name falls back to the plain name without any translation
 type Category struct {
-	ID      uuid.UUID
-	Name    string
+	ID          uuid.UUID
+	Name        string
+	Description string
+	// Locales holds every translation, for the clients that pick one themselves.
+	//
+	// Deprecated: kept for one release, Name and Description are localized already.
 	Locales map[string]CategoryLocale
 }
 
@@ -208,8 +236,10 @@ type GetItemBuyersRequest struct {
 }
 
 type CreatePurchaseRequest struct {
-	ItemID  uuid.UUID
-	BuyerID uuid.UUID
+	ItemID uuid.UUID
+	// SellerID is the caller, only the owner can record who bought the item
+	SellerID uuid.UUID
+	BuyerID  uuid.UUID
 }
 
 type CreatePurchaseResponse struct {
@@ -223,26 +253,43 @@ type SearchItemsRequest struct {
 	SizeRange   []float32
 	KaratIDs    []uuid.UUID
 	CategoryIDs []uuid.UUID
+	// UserID is nil when searching without signing in
+	UserID *uuid.UUID
+	Sort   ItemSort
+	PageRequest
+}
+
+type BumpItemRequest struct {
+	ItemID uuid.UUID
+	UserID uuid.UUID
+}
+
+type BumpItemResponse struct {
+	BumpedAt time.Time
+	// NextBumpAt is when the item can be bumped again
+	NextBumpAt time.Time
 }
 
 type ItemManager interface {
 	AddItem(ctx context.Context, item AddItemRequest) (*AddItemResponse, error)
 	UploadItemImages(ctx context.Context, req UploadItemImagesRequest) (*UploadItemImagesResponse, error)
-	GetItems(ctx context.Context, req GetItemsRequest) ([]ItemBlock, error)
+	GetItems(ctx context.Context, req GetItemsRequest) (*ItemBlocksPage, error)
 	GetItem(ctx context.Context, req GetItemRequest) (*GetItemResponse, error)
-	GetFavoriteItems(ctx context.Context, req GetFavoriteItemsRequest) ([]ItemBlock, error)
-	GetUserItems(ctx context.Context, req GetUserItemsRequest) ([]ItemBlock, error)
+	GetFavoriteItems(ctx context.Context, req GetFavoriteItemsRequest) (*ItemBlocksPage, error)
+	GetUserItems(ctx context.Context, req GetUserItemsRequest) (*ItemBlocksPage, error)
 	FavoriteItem(ctx context.Context, req FavoriteItemRequest) (*FavoriteItemResponse, error)
 	IncrementConversationCount(ctx context.Context, req IncrementConversationCountRequest) error
-	GetPurchasedItems(ctx context.Context, req GetPurchasedItemsRequest) ([]ItemBlock, error)
+	GetPurchasedItems(ctx context.Context, req GetPurchasedItemsRequest) (*ItemBlocksPage, error)
 	UpdateItem(ctx context.Context, req UpdateItemRequest) (*UpdateItemResponse, error)
 	GetAllKarats(ctx context.Context) ([]Karat, error)
 	GetAllCategories(ctx context.Context) ([]Category, error)
 	GetSimilarItems(ctx context.Context, req GetSimilarItemsRequest) (*GetSimilarItemsResponse, error)
 	GetItemBuyers(ctx context.Context, req GetItemBuyersRequest) ([]ItemBuyer, error)
 	CreatePurchase(ctx context.Context, req CreatePurchaseRequest) (*CreatePurchaseResponse, error)
-	SearchItems(ctx context.Context, req SearchItemsRequest) ([]ItemBlock, error)
+	SearchItems(ctx context.Context, req SearchItemsRequest) (*ItemBlocksPage, error)
 	DeleteItem(ctx context.Context, itemID uuid.UUID) error
+	// BumpItem moves the item of the owner to the top of the recently bumped sort, at most once per bumpCooldown
+	BumpItem(ctx context.Context, req BumpItemRequest) (*BumpItemResponse, error)
 }
 
 type ItemStatus string
@@ -253,7 +300,65 @@ const (
 	ItemStatusReserved ItemStatus = "Reserved"
 )
 
-var ErrInvalidItemStatus = fmt.Errorf("invalid item status")
+type ItemSort string
+
+const (
+	ItemSortNewest         ItemSort = "newest"
+	ItemSortPriceAsc       ItemSort = "price_asc"
+	ItemSortPriceDesc      ItemSort = "price_desc"
+	ItemSortPricePerGram   ItemSort = "price_per_gram"
+	ItemSortMostFavorited  ItemSort = "most_favorited"
+	ItemSortRecentlyBumped ItemSort = "recently_bumped"
+)
+
+var ErrInvalidItemStatus = fmt.Errorf("%w: invalid item status", common.ErrInvalidInput)
+var ErrNotItemOwner = fmt.Errorf("%w: not the owner of the item", common.ErrForbidden)
+var ErrInvalidBuyer = fmt.Errorf("%w: owner cannot purchase own item", common.ErrInvalidInput)
+var ErrBuyerNotInConversation = fmt.Errorf("%w: the buyer has no conversation about the item", common.ErrForbidden)
+var ErrItemAlreadyPurchased = fmt.Errorf("%w: the item is already purchased by another user", common.ErrConflict)
+var ErrInvalidCursor = fmt.Errorf("%w: invalid cursor", common.ErrInvalidInput)
+var ErrItemModerated = fmt.Errorf("%w: item was hidden by a moderator", common.ErrForbidden)
+var ErrInvalidSort = fmt.Errorf("%w: invalid sort", common.ErrInvalidInput)
+var ErrBumpCooldown = fmt.Errorf("%w: item was bumped recently", common.ErrTooManyAttempts)
+
+func init() {
+	common.RegisterErrorMessages(ErrInvalidCursor, map[common.Locale]string{
+		common.LocaleUz: "sahifa kursori noto'g'ri",
+		common.LocaleRu: "неверный курсор страницы",
+	})
+	common.RegisterErrorMessages(ErrInvalidSort, map[common.Locale]string{
+		common.LocaleUz: "saralash turi noto'g'ri",
+		common.LocaleRu: "неверный порядок сортировки",
+	})
+	common.RegisterErrorMessages(ErrBumpCooldown, map[common.Locale]string{
+		common.LocaleUz: "e'lon yaqinda ko'tarilgan",
+		common.LocaleRu: "объявление недавно поднималось",
+	})
+	common.RegisterErrorMessages(ErrInvalidItemStatus, map[common.Locale]string{
+		common.LocaleUz: "e'lon holati noto'g'ri",
+		common.LocaleRu: "неверный статус объявления",
+	})
+	common.RegisterErrorMessages(ErrNotItemOwner, map[common.Locale]string{
+		common.LocaleUz: "siz e'lon egasi emassiz",
+		common.LocaleRu: "вы не владелец объявления",
+	})
+	common.RegisterErrorMessages(ErrInvalidBuyer, map[common.Locale]string{
+		common.LocaleUz: "egasi o'z e'lonini sotib ololmaydi",
+		common.LocaleRu: "владелец не может купить своё объявление",
+	})
+	common.RegisterErrorMessages(ErrBuyerNotInConversation, map[common.Locale]string{
+		common.LocaleUz: "xaridor e'lon bo'yicha suhbatlashmagan",
+		common.LocaleRu: "у покупателя нет переписки по объявлению",
+	})
+	common.RegisterErrorMessages(ErrItemAlreadyPurchased, map[common.Locale]string{
+		common.LocaleUz: "e'lon boshqa foydalanuvchi tomonidan sotib olingan",
+		common.LocaleRu: "объявление уже куплено другим пользователем",
+	})
+	common.RegisterErrorMessages(ErrItemModerated, map[common.Locale]string{
+		common.LocaleUz: "e'lon moderator tomonidan yashirilgan",
+		common.LocaleRu: "объявление скрыто модератором",
+	})
+}
 
 func ParseItemStatus(itemStatus string) (*ItemStatus, error) {
 	switch ItemStatus(itemStatus) {
@@ -264,3 +369,15 @@ func ParseItemStatus(itemStatus string) (*ItemStatus, error) {
 		return nil, ErrInvalidItemStatus
 	}
 }
+
+// ParseItemSort returns the sort of a list, newest when none is given
+func ParseItemSort(sort string) (ItemSort, error) {
+	switch ItemSort(sort) {
+	case "":
+		return ItemSortNewest, nil
+	case ItemSortNewest, ItemSortPriceAsc, ItemSortPriceDesc, ItemSortPricePerGram, ItemSortMostFavorited, ItemSortRecentlyBumped:
+		return ItemSort(sort), nil
+	default:
+		return "", ErrInvalidSort
+	}
+}
@@ -0,0 +1,114 @@
+package item_manager
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"ketalk-api/pkg/manager/item/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// cursor is the position of the last item of a page, it is handed to clients base64 encoded
+// so they don't depend on what it holds. Value is the value of the sort key, which is why
+// a cursor only continues the sort it was made for.
+type cursor struct {
+	Sort  ItemSort        `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uuid.UUID       `json:"id"`
+}
+
+func encodeCursor(item repository.Item, sort ItemSort) (string, error) {
+	sortValue, err := json.Marshal(repository.SortValue(item, repository.ItemSort(sort)))
+	if err != nil {
+		return "", err
+	}
+	value, err := json.Marshal(cursor{
+		Sort:  sort,
+		Value: sortValue,
+		ID:    item.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(value), nil
+}
+
+func decodeCursor(value string, sort ItemSort) (*repository.ItemCursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(bytes, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	var sortValue interface{}
+	switch sort {
+	case ItemSortPriceAsc, ItemSortPriceDesc, ItemSortMostFavorited:
+		var v uint32
+		err = json.Unmarshal(c.Value, &v)
+		sortValue = v
+	case ItemSortPricePerGram:
+		// items without a weight have no price per gram
+		var v *float64
+		err = json.Unmarshal(c.Value, &v)
+		if v != nil {
+			sortValue = *v
+		}
+	default:
+		var v time.Time
+		err = json.Unmarshal(c.Value, &v)
+		sortValue = v
+	}
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &repository.ItemCursor{
+		Value: sortValue,
+		ID:    c.ID,
+	}, nil
+}
+
+// toRepositoryPage asks the repository for one item more than the limit, to tell whether there is a next page
+func toRepositoryPage(req PageRequest, sort ItemSort) (repository.Page, int, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultPageLimit
+	} else if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	page := repository.Page{
+		Sort:  repository.ItemSort(sort),
+		Limit: limit + 1,
+	}
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, sort)
+		if err != nil {
+			return repository.Page{}, 0, err
+		}
+		page.After = after
+	}
+	return page, limit, nil
+}
+
+// nextPage drops the extra item fetched by toRepositoryPage and returns the cursor of the next page, nil on the last one
+func nextPage(items []repository.Item, limit int, sort ItemSort) ([]repository.Item, *string, error) {
+	if len(items) <= limit {
+		return items, nil, nil
+	}
+	items = items[:limit]
+	next, err := encodeCursor(items[limit-1], sort)
+	if err != nil {
+		return nil, nil, err
+	}
+	return items, &next, nil
+}
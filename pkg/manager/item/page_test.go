@@ -0,0 +1,96 @@
+package item_manager
+
+import (
+	"encoding/base64"
+	"errors"
+	"ketalk-api/common"
+	"ketalk-api/pkg/manager/item/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 10, 12, 30, 15, 123456000, time.UTC)
+	bumpedAt := createdAt.Add(3 * time.Hour)
+	item := repository.Item{
+		ID:            uuid.New(),
+		Price:         1500,
+		Weight:        2.5,
+		FavoriteCount: 42,
+		BumpedAt:      &bumpedAt,
+	}
+	item.CreatedAt = createdAt
+	notBumped := item
+	notBumped.BumpedAt = nil
+	noWeight := item
+	noWeight.Weight = 0
+
+	tests := []struct {
+		name string
+		item repository.Item
+		sort ItemSort
+		want interface{}
+	}{
+		{"newest", item, ItemSortNewest, createdAt},
+		{"price asc", item, ItemSortPriceAsc, uint32(1500)},
+		{"price desc", item, ItemSortPriceDesc, uint32(1500)},
+		{"price per gram", item, ItemSortPricePerGram, float64(600)},
+		{"price per gram without a weight", noWeight, ItemSortPricePerGram, nil},
+		{"most favorited", item, ItemSortMostFavorited, uint32(42)},
+		{"recently bumped", item, ItemSortRecentlyBumped, bumpedAt},
+		{"recently bumped never bumped", notBumped, ItemSortRecentlyBumped, createdAt},
+	}
+	for _, tt := range tests {
+		encoded, err := encodeCursor(tt.item, tt.sort)
+		if err != nil {
+			t.Errorf("%s: encodeCursor failed: %v", tt.name, err)
+			continue
+		}
+		decoded, err := decodeCursor(encoded, tt.sort)
+		if err != nil {
+			t.Errorf("%s: decodeCursor failed: %v", tt.name, err)
+			continue
+		}
+		if decoded.ID != tt.item.ID {
+			t.Errorf("%s: decoded id %v, want %v", tt.name, decoded.ID, tt.item.ID)
+		}
+		if want, ok := tt.want.(time.Time); ok {
+			if got, ok := decoded.Value.(time.Time); !ok || !got.Equal(want) {
+				t.Errorf("%s: decoded value %v, want %v", tt.name, decoded.Value, want)
+			}
+		} else if decoded.Value != tt.want {
+			t.Errorf("%s: decoded value %#v, want %#v", tt.name, decoded.Value, tt.want)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	item := repository.Item{ID: uuid.New(), Price: 1500}
+	priceCursor, err := encodeCursor(item, ItemSortPriceAsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(value string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   ItemSort
+	}{
+		{"not base64", "not a cursor!", ItemSortNewest},
+		{"not json", encode("cursor"), ItemSortNewest},
+		{"other sort", priceCursor, ItemSortPriceDesc},
+		{"value of another type", encode(`{"s":"price_asc","v":"2024-03-10T12:30:15Z","id":"` + item.ID.String() + `"}`), ItemSortPriceAsc},
+		{"cursor without a sort", encode(`{"t":"2024-03-10T12:30:15Z","id":"` + item.ID.String() + `"}`), ItemSortNewest},
+	}
+	for _, tt := range tests {
+		_, err := decodeCursor(tt.cursor, tt.sort)
+		if !errors.Is(err, ErrInvalidCursor) || !errors.Is(err, common.ErrInvalidInput) {
+			t.Errorf("%s: decodeCursor error %v, want %v", tt.name, err, ErrInvalidCursor)
+		}
+	}
+}
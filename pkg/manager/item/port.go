@@ -6,17 +6,20 @@ import (
 	"ketalk-api/pkg/manager/port"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type itemPort struct {
 	itemRepo      repository.ItemRepository
 	itemImageRepo repository.ItemImageRepository
+	userItemRepo  repository.UserItemRepository
 }
 
-func NewItemPort(itemRepo repository.ItemRepository, itemImageRepo repository.ItemImageRepository) port.ItemPort {
+func NewItemPort(itemRepo repository.ItemRepository, itemImageRepo repository.ItemImageRepository, userItemRepo repository.UserItemRepository) port.ItemPort {
 	return &itemPort{
 		itemRepo,
 		itemImageRepo,
+		userItemRepo,
 	}
 }
 
@@ -45,3 +48,110 @@ func (p *itemPort) GetCovertImage(ctx context.Context, itemID uuid.UUID) (string
 func (p *itemPort) IncrementMessageCount(ctx context.Context, itemID uuid.UUID) error {
 	return p.itemRepo.IncrementMessageCount(ctx, itemID)
 }
+
+func (p *itemPort) HideUserItems(ctx context.Context, userID uuid.UUID) error {
+	return p.itemRepo.HideUserItems(ctx, userID)
+}
+
+func (p *itemPort) HideModeratedItem(ctx context.Context, itemID uuid.UUID) error {
+	return p.itemRepo.HideModeratedItem(ctx, itemID)
+}
+
+func (p *itemPort) ExportUserItems(ctx context.Context, userID uuid.UUID) (*port.UserItemsExport, error) {
+	items, err := p.itemRepo.GetUserItems(ctx, userID, repository.Page{})
+	if err != nil {
+		return nil, err
+	}
+	exportedItems := make([]port.ExportedItem, len(items))
+	for i, item := range items {
+		images, err := p.itemImageRepo.GetItemImages(ctx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		exportedItems[i] = toExportedItem(item)
+		for _, image := range images {
+			if image.UploadedToCloud {
+				exportedItems[i].ImageKeys = append(exportedItems[i].ImageKeys, image.Key)
+			}
+		}
+	}
+	favorites, err := p.userItemRepo.GetUserFavoriteItems(ctx, userID, repository.Page{})
+	if err != nil {
+		return nil, err
+	}
+	purchases, err := p.userItemRepo.GetPurchasedItems(ctx, userID, repository.Page{})
+	if err != nil {
+		return nil, err
+	}
+	return &port.UserItemsExport{
+		Items:     exportedItems,
+		Favorites: toExportedItems(favorites),
+		Purchases: toExportedItems(purchases),
+	}, nil
+}
+
+func toExportedItems(items []repository.Item) []port.ExportedItem {
+	exported := make([]port.ExportedItem, len(items))
+	for i, item := range items {
+		exported[i] = toExportedItem(item)
+	}
+	return exported
+}
+
+func toExportedItem(item repository.Item) port.ExportedItem {
+	return port.ExportedItem{
+		ID:          item.ID,
+		Title:       item.Title,
+		Description: item.Description,
+		Price:       item.Price,
+		Negotiable:  item.Negotiable,
+		ItemStatus:  item.ItemStatus,
+		Size:        item.Size,
+		Weight:      item.Weight,
+		KaratID:     item.KaratID,
+		CategoryID:  item.CategoryID,
+		GeofenceID:  item.GeofenceID,
+		CreatedAt:   item.CreatedAt,
+	}
+}
+
+func (p *itemPort) GetUserItemStats(ctx context.Context, userID uuid.UUID) (*port.UserItemStats, error) {
+	counts, err := p.itemRepo.CountUserItemsByStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats := &port.UserItemStats{}
+	for _, count := range counts {
+		switch ItemStatus(count.ItemStatus) {
+		case ItemStatusActive, ItemStatusReserved:
+			stats.ActiveCount += count.Count
+		case ItemStatusSold:
+			stats.SoldCount += count.Count
+		}
+	}
+	stats.LastItemUpdateAt, err = p.itemRepo.GetLastUserItemUpdate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (p *itemPort) GetPurchase(ctx context.Context, itemID uuid.UUID, buyerID uuid.UUID) (*port.Purchase, error) {
+	item, err := p.itemRepo.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	userItem, err := p.userItemRepo.GetUserItem(ctx, buyerID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !userItem.IsPurchased {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &port.Purchase{
+		ID:       userItem.ID,
+		ItemID:   item.ID,
+		BuyerID:  userItem.UserID,
+		SellerID: item.OwnerID,
+	}, nil
+}
@@ -3,18 +3,23 @@ package repository
 import (
 	"context"
 	"fmt"
+	"ketalk-api/pkg/config"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type itemRepository struct {
 	*gorm.DB
+	dbConfig config.Postgres
 }
 
-func NewItemRepository(ctx context.Context, db *gorm.DB) ItemRepository {
+func NewItemRepository(ctx context.Context, db *gorm.DB, dbConfig config.Postgres) ItemRepository {
 	return &itemRepository{
 		db,
+		dbConfig,
 	}
 }
 
@@ -52,18 +57,22 @@ func (r *itemRepository) Update(ctx context.Context, item *Item) error {
 	return nil
 }
 
-func (r *itemRepository) GetItems(ctx context.Context, GeofenceID uuid.UUID, userID uuid.UUID) ([]Item, error) {
+func (r *itemRepository) GetItems(ctx context.Context, GeofenceID uuid.UUID, userID uuid.UUID, blockedOwnerIDs []uuid.UUID, page Page) ([]Item, error) {
 	var items []Item = make([]Item, 0)
-	resp := r.Where("geofence_id = ? AND owner_id != ? and is_hidden = false", GeofenceID, userID).Find(&items)
+	query := r.Where("geofence_id = ? AND owner_id != ? and is_hidden = false", GeofenceID, userID)
+	if len(blockedOwnerIDs) > 0 {
+		query = query.Where("owner_id NOT IN ?", blockedOwnerIDs)
+	}
+	resp := paginate(query, page).Find(&items)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
 	return items, nil
 }
 
-func (r *itemRepository) SearchItems(ctx context.Context, keyword string, priceRange []uint32, sizeRange []float32, karatIds []uuid.UUID, categoryIds []uuid.UUID) ([]Item, error) {
+func (r *itemRepository) SearchItems(ctx context.Context, keyword string, priceRange []uint32, sizeRange []float32, karatIds []uuid.UUID, categoryIds []uuid.UUID, blockedOwnerIDs []uuid.UUID, page Page) ([]Item, error) {
 	var items []Item = make([]Item, 0)
-	query := r.Where("price BETWEEN ? AND ? AND size BETWEEN ? AND ?", priceRange[0], priceRange[1], sizeRange[0], sizeRange[1])
+	query := r.Where("price BETWEEN ? AND ? AND size BETWEEN ? AND ? AND is_hidden = false", priceRange[0], priceRange[1], sizeRange[0], sizeRange[1])
 	if keyword != "" {
 		query = query.Where("title LIKE ?", fmt.Sprintf("%%%s%%", keyword))
 	}
@@ -73,7 +82,10 @@ func (r *itemRepository) SearchItems(ctx context.Context, keyword string, priceR
 	if len(categoryIds) > 0 {
 		query = query.Where("category_id IN ?", categoryIds)
 	}
-	resp := query.Find(&items)
+	if len(blockedOwnerIDs) > 0 {
+		query = query.Where("owner_id NOT IN ?", blockedOwnerIDs)
+	}
+	resp := paginate(query, page).Find(&items)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
@@ -89,9 +101,9 @@ func (r *itemRepository) GetItem(ctx context.Context, itemID uuid.UUID) (*Item,
 	return &item, nil
 }
 
-func (r *itemRepository) GetUserItems(ctx context.Context, userID uuid.UUID) ([]Item, error) {
+func (r *itemRepository) GetUserItems(ctx context.Context, userID uuid.UUID, page Page) ([]Item, error) {
 	var items []Item = make([]Item, 0)
-	resp := r.Where("owner_id = ?", userID).Find(&items)
+	resp := paginate(r.Where("owner_id = ?", userID), page).Find(&items)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
@@ -107,9 +119,9 @@ func (r *itemRepository) GetLimitedUserItems(ctx context.Context, userID uuid.UU
 	return items, nil
 }
 
-func (r *itemRepository) GetLimitedItemsByCategoryOrKarat(ctx context.Context, userIDToExlude uuid.UUID, categoryID uuid.UUID, karatID uuid.UUID, limit int) ([]Item, error) {
+func (r *itemRepository) GetLimitedItemsByCategoryOrKarat(ctx context.Context, ownerIDsToExclude []uuid.UUID, categoryID uuid.UUID, karatID uuid.UUID, limit int) ([]Item, error) {
 	var items []Item = make([]Item, 0)
-	resp := r.Where("(category_id = ? OR karat_id = ?) AND owner_id != ?", categoryID, karatID, userIDToExlude).Limit(limit).Find(&items)
+	resp := r.Where("(category_id = ? OR karat_id = ?) AND owner_id NOT IN ? AND is_hidden = false", categoryID, karatID, ownerIDsToExclude).Limit(limit).Find(&items)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
@@ -142,6 +154,103 @@ func (r *itemRepository) DeleteItem(ctx context.Context, itemId uuid.UUID) error
 	return resp.Error
 }
 
+func (r *itemRepository) HideUserItems(ctx context.Context, userID uuid.UUID) error {
+	return r.Model(&Item{}).Where("owner_id = ?", userID).Update("is_hidden", true).Error
+}
+
+func (r *itemRepository) HideModeratedItem(ctx context.Context, itemID uuid.UUID) error {
+	res := r.Model(&Item{}).Where("id = ?", itemID).Updates(map[string]interface{}{
+		"is_hidden":    true,
+		"moderated_at": gorm.Expr("COALESCE(moderated_at, ?)", time.Now()),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *itemRepository) CountUserItemsByStatus(ctx context.Context, userID uuid.UUID) ([]ItemStatusCount, error) {
+	var counts []ItemStatusCount = make([]ItemStatusCount, 0)
+	resp := r.Model(&Item{}).
+		Select("item_status, COUNT(*) AS count").
+		Where("owner_id = ? AND is_hidden = false", userID).
+		Group("item_status").
+		Scan(&counts)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return counts, nil
+}
+
+// GetLastUserItemUpdate returns nil when the user never listed an item
+func (r *itemRepository) GetLastUserItemUpdate(ctx context.Context, userID uuid.UUID) (*time.Time, error) {
+	var lastUpdate *time.Time
+	resp := r.Model(&Item{}).Select("MAX(updated_at)").Where("owner_id = ?", userID).Scan(&lastUpdate)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return lastUpdate, nil
+}
+
+func (r *itemRepository) BumpItem(ctx context.Context, itemID uuid.UUID, bumpedAt time.Time) error {
+	res := r.Model(&Item{}).Where("id = ?", itemID).Update("bumped_at", bumpedAt)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+type sortIndex struct {
+	name string
+	// columns is static sql, it can not be passed as a parameter
+	columns string
+}
+
+// sortIndexes back the sorts of the feed and search, see sortKeys. The feed lists the visible items of a geofence,
+// so every sort has a variant for it.
+var sortIndexes = []sortIndex{
+	{"idx_item_sort_created_at", "(created_at, id)"},
+	{"idx_item_sort_price", "(price, id)"},
+	{"idx_item_sort_price_per_gram", "((price::float8 / NULLIF(weight::float4, 0)::float8) NULLS LAST, id)"},
+	{"idx_item_sort_favorite_count", "(favorite_count, id)"},
+	{"idx_item_sort_bumped_at", "((COALESCE(bumped_at, created_at)), id)"},
+	{"idx_item_feed_created_at", "(geofence_id, created_at, id) WHERE is_hidden = false"},
+	{"idx_item_feed_price", "(geofence_id, price, id) WHERE is_hidden = false"},
+	{"idx_item_feed_price_per_gram", "(geofence_id, (price::float8 / NULLIF(weight::float4, 0)::float8) NULLS LAST, id) WHERE is_hidden = false"},
+	{"idx_item_feed_favorite_count", "(geofence_id, favorite_count, id) WHERE is_hidden = false"},
+	{"idx_item_feed_bumped_at", "(geofence_id, (COALESCE(bumped_at, created_at)), id) WHERE is_hidden = false"},
+}
+
+// replacedSortIndexes were created by earlier versions, the price per gram one left out items without a weight
+var replacedSortIndexes = []string{
+	"idx_item_created_at",
+	"idx_item_price",
+	"idx_item_price_per_gram",
+	"idx_item_favorite_count",
+	"idx_item_bumped_at",
+}
+
 func (r *itemRepository) Migrate() error {
-	return r.AutoMigrate(&Item{})
+	if err := r.AutoMigrate(&Item{}); err != nil {
+		return err
+	}
+	schema := clause.Table{Name: r.dbConfig.GetSchema()}
+	table := clause.Table{Name: fmt.Sprintf("%s.item", r.dbConfig.GetSchema())}
+	for _, name := range replacedSortIndexes {
+		if err := r.Exec("DROP INDEX IF EXISTS ?.?", schema, clause.Table{Name: name}).Error; err != nil {
+			return err
+		}
+	}
+	for _, index := range sortIndexes {
+		if err := r.Exec("CREATE INDEX IF NOT EXISTS ? ON ? "+index.columns, clause.Table{Name: index.name}, table).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"ketalk-api/common"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -26,23 +27,39 @@ type Item struct {
 	KaratID       uuid.UUID
 	CategoryID    uuid.UUID
 	GeofenceID    uuid.UUID
+	// ModeratedAt is set when a moderator hid the item, the owner cannot show it again
+	ModeratedAt *time.Time
+	// BumpedAt is when the owner last moved the item up in the recently bumped sort, nil if never
+	BumpedAt *time.Time
 	common.CreatedUpdatedDeleted
 }
 
+// ItemStatusCount is the number of visible items of a user with the status
+type ItemStatusCount struct {
+	ItemStatus string
+	Count      int64
+}
+
 type ItemRepository interface {
 	AddItem(ctx context.Context, item *Item) error
 	Update(ctx context.Context, item *Item) error
-	GetItems(ctx context.Context, GeofenceID uuid.UUID, userID uuid.UUID) ([]Item, error)
-	GetUserItems(ctx context.Context, userID uuid.UUID) ([]Item, error)
+	// blockedOwnerIDs are the users the caller blocked, their items are left out
+	GetItems(ctx context.Context, GeofenceID uuid.UUID, userID uuid.UUID, blockedOwnerIDs []uuid.UUID, page Page) ([]Item, error)
+	GetUserItems(ctx context.Context, userID uuid.UUID, page Page) ([]Item, error)
 	GetItem(ctx context.Context, itemId uuid.UUID) (*Item, error)
 	IncrementFavoriteCount(ctx context.Context, itemId uuid.UUID) error
 	DecrementFavoriteCount(ctx context.Context, itemId uuid.UUID) error
 	IncrementMessageCount(ctx context.Context, itemId uuid.UUID) error
 	DecrementMessageCount(ctx context.Context, itemId uuid.UUID) error
 	GetLimitedUserItems(ctx context.Context, userID uuid.UUID, limit int) ([]Item, error)
-	GetLimitedItemsByCategoryOrKarat(ctx context.Context, userIDToExlude uuid.UUID, categoryID uuid.UUID, karatID uuid.UUID, limit int) ([]Item, error)
-	SearchItems(ctx context.Context, keyword string, priceRange []uint32, sizeRange []float32, karatIds []uuid.UUID, categoryIds []uuid.UUID) ([]Item, error)
+	GetLimitedItemsByCategoryOrKarat(ctx context.Context, ownerIDsToExclude []uuid.UUID, categoryID uuid.UUID, karatID uuid.UUID, limit int) ([]Item, error)
+	SearchItems(ctx context.Context, keyword string, priceRange []uint32, sizeRange []float32, karatIds []uuid.UUID, categoryIds []uuid.UUID, blockedOwnerIDs []uuid.UUID, page Page) ([]Item, error)
 	DeleteItem(ctx context.Context, itemId uuid.UUID) error
+	HideUserItems(ctx context.Context, userID uuid.UUID) error
+	HideModeratedItem(ctx context.Context, itemID uuid.UUID) error
+	BumpItem(ctx context.Context, itemID uuid.UUID, bumpedAt time.Time) error
+	CountUserItemsByStatus(ctx context.Context, userID uuid.UUID) ([]ItemStatusCount, error)
+	GetLastUserItemUpdate(ctx context.Context, userID uuid.UUID) (*time.Time, error)
 	Migrate() error
 }
 
@@ -58,7 +75,7 @@ type ItemImage struct {
 type UserItem struct {
 	ID          uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
 	UserID      uuid.UUID
-	ItemID      uuid.UUID
+	ItemID      uuid.UUID `gorm:"uniqueIndex:idx_user_item_purchased,where:is_purchased = true"`
 	IsFavorite  bool
 	IsPurchased bool
 	common.CreatedUpdatedDeleted
@@ -78,9 +95,9 @@ type UserItemRepository interface {
 	Insert(ctx context.Context, userItem *UserItem) error
 	Update(ctx context.Context, userItem *UserItem) error
 	GetUserItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) (*UserItem, error)
-	GetUserFavoriteItems(ctx context.Context, userID uuid.UUID) ([]Item, error)
+	GetUserFavoriteItems(ctx context.Context, userID uuid.UUID, page Page) ([]Item, error)
 	PurchaseItem(ctx context.Context, userID uuid.UUID, itemID uuid.UUID) error
-	GetPurchasedItems(ctx context.Context, userID uuid.UUID) ([]Item, error)
+	GetPurchasedItems(ctx context.Context, userID uuid.UUID, page Page) ([]Item, error)
 	GetItemBuyer(ctx context.Context, itemID uuid.UUID) ([]UserItem, error)
 	Migrate() error
 }
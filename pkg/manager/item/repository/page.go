@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ItemSort string
+
+const (
+	ItemSortNewest         ItemSort = "newest"
+	ItemSortPriceAsc       ItemSort = "price_asc"
+	ItemSortPriceDesc      ItemSort = "price_desc"
+	ItemSortPricePerGram   ItemSort = "price_per_gram"
+	ItemSortMostFavorited  ItemSort = "most_favorited"
+	ItemSortRecentlyBumped ItemSort = "recently_bumped"
+)
+
+type sortKey struct {
+	// expression is what the items are ordered by, the id breaks ties
+	expression string
+	descending bool
+	// nullable expressions put the items without a value last
+	nullable bool
+}
+
+// sortKeys have to match the indexes created in itemRepository.Migrate.
+// Weight is cast through float4 so the price per gram is computed the same way as from the scanned float32,
+// items without a weight have no price per gram.
+var sortKeys = map[ItemSort]sortKey{
+	ItemSortNewest:         {expression: "item.created_at", descending: true},
+	ItemSortPriceAsc:       {expression: "item.price"},
+	ItemSortPriceDesc:      {expression: "item.price", descending: true},
+	ItemSortPricePerGram:   {expression: "(item.price::float8 / NULLIF(item.weight::float4, 0)::float8)", nullable: true},
+	ItemSortMostFavorited:  {expression: "item.favorite_count", descending: true},
+	ItemSortRecentlyBumped: {expression: "COALESCE(item.bumped_at, item.created_at)", descending: true},
+}
+
+// ItemCursor is the last item of the previous page, Value is its value of the sort key, nil when it has none
+type ItemCursor struct {
+	Value interface{}
+	ID    uuid.UUID
+}
+
+// Page selects the items after the cursor in the order of the sort, a zero Page lists every item newest first
+type Page struct {
+	Sort  ItemSort
+	After *ItemCursor
+	Limit int
+}
+
+func paginate(query *gorm.DB, page Page) *gorm.DB {
+	key, ok := sortKeys[page.Sort]
+	if !ok {
+		key = sortKeys[ItemSortNewest]
+	}
+	direction, comparison := "ASC", ">"
+	if key.descending {
+		direction, comparison = "DESC", "<"
+	}
+	nulls := ""
+	if key.nullable {
+		nulls = " NULLS LAST"
+	}
+	if page.After != nil {
+		switch {
+		case key.nullable && page.After.Value == nil:
+			// only items without a value are left, in the order of the id
+			query = query.Where(fmt.Sprintf("%s IS NULL AND item.id %s ?", key.expression, comparison), page.After.ID)
+		case key.nullable:
+			query = query.Where(fmt.Sprintf("((%s, item.id) %s (?, ?) OR %s IS NULL)", key.expression, comparison, key.expression), page.After.Value, page.After.ID)
+		default:
+			query = query.Where(fmt.Sprintf("(%s, item.id) %s (?, ?)", key.expression, comparison), page.After.Value, page.After.ID)
+		}
+	}
+	query = query.Order(fmt.Sprintf("%s %s%s", key.expression, direction, nulls)).Order(fmt.Sprintf("item.id %s", direction))
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	return query
+}
+
+// SortValue is the value of the sort key of the item, the way the expression of the sort computes it
+func SortValue(item Item, sort ItemSort) interface{} {
+	switch sort {
+	case ItemSortPriceAsc, ItemSortPriceDesc:
+		return item.Price
+	case ItemSortPricePerGram:
+		if item.Weight == 0 {
+			return nil
+		}
+		return float64(item.Price) / float64(item.Weight)
+	case ItemSortMostFavorited:
+		return item.FavoriteCount
+	case ItemSortRecentlyBumped:
+		if item.BumpedAt != nil {
+			return *item.BumpedAt
+		}
+		return item.CreatedAt
+	default:
+		return item.CreatedAt
+	}
+}
@@ -47,24 +47,24 @@ func (r *userItemRepository) Update(ctx context.Context, userItem *UserItem) err
 	return nil
 }
 
-func (r *userItemRepository) GetUserFavoriteItems(ctx context.Context, userID uuid.UUID) ([]Item, error) {
+func (r *userItemRepository) GetUserFavoriteItems(ctx context.Context, userID uuid.UUID, page Page) ([]Item, error) {
 	var userItems []Item = make([]Item, 0)
-	resp := r.Model(&Item{}).
+	query := r.Model(&Item{}).
 		InnerJoins(fmt.Sprintf("INNER JOIN %s.%s on user_item.item_id = item.id", r.dbConfig.GetSchema(), "user_item")).
-		Where("user_item.user_id = ? and is_favorite = ?", userID, true).
-		Find(&userItems)
+		Where("user_item.user_id = ? and is_favorite = ?", userID, true)
+	resp := paginate(query, page).Find(&userItems)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
 	return userItems, nil
 }
 
-func (r *userItemRepository) GetPurchasedItems(ctx context.Context, userID uuid.UUID) ([]Item, error) {
+func (r *userItemRepository) GetPurchasedItems(ctx context.Context, userID uuid.UUID, page Page) ([]Item, error) {
 	var userItems []Item = make([]Item, 0)
-	resp := r.Model(&Item{}).
+	query := r.Model(&Item{}).
 		InnerJoins(fmt.Sprintf("INNER JOIN %s.%s on user_item.item_id = item.id", r.dbConfig.GetSchema(), "user_item")).
-		Where("user_item.user_id = ? and is_purchased = ?", userID, true).
-		Find(&userItems)
+		Where("user_item.user_id = ? and is_purchased = ?", userID, true)
+	resp := paginate(query, page).Find(&userItems)
 	if resp.Error != nil {
 		return nil, resp.Error
 	}
@@ -5,25 +5,33 @@ import (
 	"fmt"
 	"ketalk-api/common"
 	"ketalk-api/jwt"
+	auth_redis "ketalk-api/pkg/manager/auth/redis"
 	"ketalk-api/pkg/manager/port"
+	"log"
 	"net/http"
+	"time"
 
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type middleware struct {
 	userPort port.UserPort
+	redis    auth_redis.TokenRevocation
 }
 
-func NewMiddleware(userPort port.UserPort) common.Middleware {
+func NewMiddleware(userPort port.UserPort, redis auth_redis.TokenRevocation) common.Middleware {
 	return &middleware{
 		userPort: userPort,
+		redis:    redis,
 	}
 }
 
+var ErrTokenRevoked = fmt.Errorf("token is revoked")
+
 func (m *middleware) AuthMiddleware(cfg jwt.Config) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		req := ctx.Request
@@ -41,8 +49,17 @@ func (m *middleware) AuthMiddleware(cfg jwt.Config) gin.HandlerFunc {
 		}
 
 		contextWithToken, err := jwt.ParseJWTToken(req.Context(), cfg, token)
+		if err == nil {
+			// revoked tokens are treated like invalid ones
+			err = m.checkRevocation(contextWithToken)
+		}
 		if err != nil {
-			// todo: we may want to return err if jwt token validation failed
+			log.Printf("rejected access token: %v\n", err)
+			// continue unauthenticated, public routes like the token refresh must still work with a stale token
+			// and HandlerWithAuth responds with 401 if the route needs a user
+			if cfg.Strict {
+				ctx.Request = req.WithContext(context.WithValue(req.Context(), tokenErrorKey{}, err))
+			}
 			ctx.Next()
 			return
 		}
@@ -52,7 +69,13 @@ func (m *middleware) AuthMiddleware(cfg jwt.Config) gin.HandlerFunc {
 	}
 }
 
-func (m *middleware) ValidateUserAuthorization(ctx context.Context) error {
+// tokenErrorKey holds why the access token of the request was rejected
+type tokenErrorKey struct{}
+
+// checkRevocation fails for tokens on the denylist and tokens issued before the user's sign out from everywhere
+// or from the device the token was issued for.
+// It also fails when the denylist can't be read, we don't want revoked tokens to pass while redis is down.
+func (m *middleware) checkRevocation(ctx context.Context) error {
 	tokenData, err := jwt.GetJWTToken(ctx)
 	if err != nil {
 		return err
@@ -61,20 +84,79 @@ func (m *middleware) ValidateUserAuthorization(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("invalid token")
 	}
-	_, err = m.userPort.GetUser(ctx, userId)
+	var issuedAt time.Time
+	if tokenData.IssuedAt != nil {
+		issuedAt = tokenData.IssuedAt.Time
+	}
+	revoked, err := m.redis.IsTokenRevoked(ctx, tokenData.ID, userId, tokenData.DeviceID, issuedAt)
 	if err != nil {
 		return err
 	}
+	if revoked {
+		return ErrTokenRevoked
+	}
 	return nil
 }
 
+func (m *middleware) ValidateUserAuthorization(ctx context.Context) error {
+	_, err := m.authorizedUser(ctx)
+	return err
+}
+
+func (m *middleware) authorizedUser(ctx context.Context) (*port.User, error) {
+	tokenData, err := jwt.GetJWTToken(ctx)
+	if err != nil {
+		if tokenErr, ok := ctx.Value(tokenErrorKey{}).(error); ok {
+			err = tokenErr
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrUnauthorized, err)
+	}
+	userId, err := uuid.Parse(tokenData.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid token", common.ErrUnauthorized)
+	}
+	user, err := m.userPort.GetUser(ctx, userId)
+	if err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("%w: user not found", common.ErrUnauthorized)
+	} else if err != nil {
+		return nil, err
+	}
+	if user.IsSuspended(time.Now()) {
+		return nil, common.ErrAccountSuspended
+	}
+	return user, nil
+}
+
 func (m *middleware) HandlerWithAuth(handler common.HandlerFunc) common.HandlerFunc {
 	return func(ctx *gin.Context, req *http.Request) (interface{}, error) {
-		err := m.ValidateUserAuthorization(req.Context())
+		user, err := m.authorizedUser(req.Context())
 		if err != nil {
 			return nil, err
 		}
-		return handler(ctx, req)
+		return handler(ctx, withUserLocale(ctx, user))
+	}
+}
+
+// withUserLocale answers in the locale the user chose, over the Accept-Language of the request
+func withUserLocale(ctx *gin.Context, user *port.User) *http.Request {
+	if user.Locale != "" {
+		ctx.Request = ctx.Request.WithContext(common.WithLocale(ctx.Request.Context(), user.Locale))
+	}
+	return ctx.Request
+}
+
+// HandlerWithRole only lets users with at least the given role through.
+// The role is read from the database rather than the token, so a demoted user loses access immediately.
+func (m *middleware) HandlerWithRole(role common.Role, handler common.HandlerFunc) common.HandlerFunc {
+	return func(ctx *gin.Context, req *http.Request) (interface{}, error) {
+		user, err := m.authorizedUser(req.Context())
+		if err != nil {
+			return nil, err
+		}
+		if !user.Role.Includes(role) {
+			return nil, fmt.Errorf("%w: requires %s role", common.ErrForbidden, role)
+		}
+		return handler(ctx, withUserLocale(ctx, user))
 	}
 }
 
@@ -0,0 +1,319 @@
+package moderation_manager
+
+import (
+	"context"
+	"errors"
+	"ketalk-api/common"
+	"ketalk-api/pkg/manager/moderation/repository"
+	"ketalk-api/pkg/manager/port"
+	"log"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxDetailsLength = 1000
+	maxReportsPage   = 100
+	maxActionsPage   = 100
+)
+
+type moderationManager struct {
+	reportRepository           repository.ReportRepository
+	moderationActionRepository repository.ModerationActionRepository
+	itemPort                   port.ItemPort
+	userPort                   port.UserPort
+	conversationPort           port.ConversationPort
+	authPort                   port.AuthPort
+	cfg                        Config
+}
+
+func NewModerationManager(reportRepository repository.ReportRepository, moderationActionRepository repository.ModerationActionRepository, itemPort port.ItemPort, userPort port.UserPort, conversationPort port.ConversationPort, authPort port.AuthPort, cfg Config) ModerationManager {
+	return &moderationManager{
+		reportRepository,
+		moderationActionRepository,
+		itemPort,
+		userPort,
+		conversationPort,
+		authPort,
+		cfg,
+	}
+}
+
+func (m *moderationManager) CreateReport(ctx context.Context, req CreateReportRequest) (*Report, error) {
+	targetType := repository.TargetType(req.TargetType)
+	if !reasons[req.Reason] {
+		return nil, ErrInvalidReason
+	}
+	if utf8.RuneCountInString(req.Details) > maxDetailsLength {
+		return nil, ErrDetailsTooLong
+	}
+	ownerID, _, err := m.getTarget(ctx, targetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID == req.ReporterID {
+		return nil, ErrCannotReportOwn
+	}
+	if targetType == repository.TargetTypeMessage {
+		// only the other member of the conversation can report a message
+		message, err := m.conversationPort.GetMessage(ctx, req.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(message.MemberIDs, req.ReporterID) {
+			return nil, ErrTargetNotFound
+		}
+	}
+
+	report := &repository.Report{
+		ReporterID: req.ReporterID,
+		TargetType: targetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+		Status:     repository.ReportStatusOpen,
+	}
+	created, err := m.reportRepository.Create(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyReported
+	}
+	if targetType == repository.TargetTypeItem {
+		m.autoHideItem(ctx, req.TargetID)
+	}
+	return toReport(report), nil
+}
+
+// autoHideItem hides an item reported by enough users, the reports stay in the queue for a moderator.
+// Failing here does not fail the report, a moderator still sees the item in the queue.
+func (m *moderationManager) autoHideItem(ctx context.Context, itemID uuid.UUID) {
+	if m.cfg.AutoHideReports <= 0 {
+		return
+	}
+	count, err := m.reportRepository.CountTargetReports(ctx, repository.TargetTypeItem, itemID)
+	if err != nil {
+		log.Printf("failed to count reports of item %s: %v\n", itemID, err)
+		return
+	}
+	// reports counted in parallel can skip the threshold, hiding the item again does no harm
+	if count < m.cfg.AutoHideReports {
+		return
+	}
+	if err := m.itemPort.HideModeratedItem(ctx, itemID); err != nil {
+		log.Printf("failed to auto hide item %s: %v\n", itemID, err)
+		return
+	}
+	if err := m.moderationActionRepository.Create(ctx, &repository.ModerationAction{
+		Action:     repository.ModerationActionAutoHideItem,
+		TargetType: repository.TargetTypeItem,
+		TargetID:   itemID,
+	}); err != nil {
+		log.Printf("failed to audit auto hide of item %s: %v\n", itemID, err)
+	}
+}
+
+func (m *moderationManager) GetReports(ctx context.Context, req GetReportsRequest) ([]Report, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxReportsPage {
+		limit = maxReportsPage
+	}
+	reports, err := m.reportRepository.GetReports(ctx, repository.ReportFilter{
+		Status:     repository.ReportStatus(req.Status),
+		TargetType: repository.TargetType(req.TargetType),
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]Report, len(reports))
+	for i := range reports {
+		resp[i] = *toReport(&reports[i])
+	}
+	return resp, nil
+}
+
+func (m *moderationManager) GetReport(ctx context.Context, reportID uuid.UUID) (*ReportDetail, error) {
+	report, err := m.getReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	detail := &ReportDetail{
+		Report: *toReport(report),
+	}
+	detail.ReportCount, err = m.reportRepository.CountTargetReports(ctx, report.TargetType, report.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	// the target can be gone by the time the report is reviewed
+	detail.TargetOwnerID, detail.Content, err = m.getTarget(ctx, report.TargetType, report.TargetID)
+	if err != nil && !errors.Is(err, ErrTargetNotFound) {
+		return nil, err
+	}
+	return detail, nil
+}
+
+func (m *moderationManager) DismissReport(ctx context.Context, req DismissReportRequest) error {
+	report, err := m.getReport(ctx, req.ReportID)
+	if err != nil {
+		return err
+	}
+	if report.Status != repository.ReportStatusOpen {
+		return ErrReportAlreadyResolved
+	}
+	if err := m.reportRepository.Resolve(ctx, report.ID, repository.ReportStatusDismissed, req.ModeratorID); err != nil {
+		return err
+	}
+	return m.audit(ctx, req.ModeratorID, repository.ModerationActionDismissReport, report.TargetType, report.TargetID, &report.ID, req.Note)
+}
+
+func (m *moderationManager) HideItem(ctx context.Context, req HideItemRequest) error {
+	err := m.itemPort.HideModeratedItem(ctx, req.ItemID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTargetNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := m.resolveReports(ctx, req.ModeratorID, repository.TargetTypeItem, req.ItemID, req.ReportID); err != nil {
+		return err
+	}
+	return m.audit(ctx, req.ModeratorID, repository.ModerationActionHideItem, repository.TargetTypeItem, req.ItemID, req.ReportID, req.Note)
+}
+
+func (m *moderationManager) SuspendUser(ctx context.Context, req SuspendUserRequest) error {
+	user, err := m.userPort.GetUser(ctx, req.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTargetNotFound
+	} else if err != nil {
+		return err
+	}
+	if user.Role.Includes(common.RoleModerator) {
+		return ErrCannotSuspendModerator
+	}
+	if err := m.userPort.SuspendUser(ctx, req.UserID, req.Until); err != nil {
+		return err
+	}
+	if err := m.authPort.RevokeUserTokens(ctx, req.UserID); err != nil {
+		return err
+	}
+	if err := m.resolveReports(ctx, req.ModeratorID, repository.TargetTypeUser, req.UserID, req.ReportID); err != nil {
+		return err
+	}
+	return m.audit(ctx, req.ModeratorID, repository.ModerationActionSuspendUser, repository.TargetTypeUser, req.UserID, req.ReportID, req.Note)
+}
+
+func (m *moderationManager) GetActions(ctx context.Context, req GetActionsRequest) ([]ModerationAction, error) {
+	limit := req.Limit
+	if limit <= 0 || limit > maxActionsPage {
+		limit = maxActionsPage
+	}
+	actions, err := m.moderationActionRepository.GetActions(ctx, repository.ModerationActionFilter{
+		TargetType:  repository.TargetType(req.TargetType),
+		TargetID:    req.TargetID,
+		ModeratorID: req.ModeratorID,
+		Since:       req.Since,
+		Limit:       limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]ModerationAction, len(actions))
+	for i, action := range actions {
+		resp[i] = ModerationAction{
+			ID:          action.ID,
+			ModeratorID: action.ModeratorID,
+			Action:      string(action.Action),
+			TargetType:  string(action.TargetType),
+			TargetID:    action.TargetID,
+			ReportID:    action.ReportID,
+			Note:        action.Note,
+			CreatedAt:   action.CreatedAt,
+		}
+	}
+	return resp, nil
+}
+
+// resolveReports resolves the open reports of the target, and the report the action was taken for,
+// e.g. a reported message whose sender got suspended.
+func (m *moderationManager) resolveReports(ctx context.Context, moderatorID uuid.UUID, targetType repository.TargetType, targetID uuid.UUID, reportID *uuid.UUID) error {
+	if err := m.reportRepository.ResolveTargetReports(ctx, targetType, targetID, moderatorID); err != nil {
+		return err
+	}
+	if reportID == nil {
+		return nil
+	}
+	report, err := m.getReport(ctx, *reportID)
+	if err != nil {
+		return err
+	}
+	if report.Status != repository.ReportStatusOpen {
+		return nil
+	}
+	return m.reportRepository.Resolve(ctx, report.ID, repository.ReportStatusResolved, moderatorID)
+}
+
+func (m *moderationManager) audit(ctx context.Context, moderatorID uuid.UUID, action repository.ModerationActionType, targetType repository.TargetType, targetID uuid.UUID, reportID *uuid.UUID, note string) error {
+	return m.moderationActionRepository.Create(ctx, &repository.ModerationAction{
+		ModeratorID: &moderatorID,
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		ReportID:    reportID,
+		Note:        note,
+	})
+}
+
+func (m *moderationManager) getReport(ctx context.Context, reportID uuid.UUID) (*repository.Report, error) {
+	report, err := m.reportRepository.GetReport(ctx, reportID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReportNotFound
+	}
+	return report, err
+}
+
+// getTarget returns the user responsible for the target and its content
+func (m *moderationManager) getTarget(ctx context.Context, targetType repository.TargetType, targetID uuid.UUID) (uuid.UUID, string, error) {
+	var err error
+	switch targetType {
+	case repository.TargetTypeItem:
+		var item *port.Item
+		if item, err = m.itemPort.GetItem(ctx, targetID); err == nil {
+			return item.OwnerID, item.Title, nil
+		}
+	case repository.TargetTypeUser:
+		var user *port.User
+		if user, err = m.userPort.GetUser(ctx, targetID); err == nil {
+			return user.ID, user.Username, nil
+		}
+	case repository.TargetTypeMessage:
+		var message *port.Message
+		if message, err = m.conversationPort.GetMessage(ctx, targetID); err == nil {
+			return message.SenderID, message.Message, nil
+		}
+	default:
+		return uuid.Nil, "", ErrInvalidTargetType
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, "", ErrTargetNotFound
+	}
+	return uuid.Nil, "", err
+}
+
+func toReport(report *repository.Report) *Report {
+	return &Report{
+		ID:         report.ID,
+		ReporterID: report.ReporterID,
+		TargetType: string(report.TargetType),
+		TargetID:   report.TargetID,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     string(report.Status),
+		ResolvedBy: report.ResolvedBy,
+		ResolvedAt: report.ResolvedAt,
+		CreatedAt:  report.CreatedAt,
+	}
+}
@@ -0,0 +1,177 @@
+package moderation_manager
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	// AutoHideReports hides an item once that many users reported it, 0 turns it off
+	AutoHideReports int64 `yaml:"autoHideReports" env:"MODERATION_AUTO_HIDE_REPORTS" env-default:"0"`
+}
+
+// report reasons a client can choose from
+const (
+	ReasonSpam          = "spam"
+	ReasonScam          = "scam"
+	ReasonProhibited    = "prohibited"
+	ReasonCounterfeit   = "counterfeit"
+	ReasonHarassment    = "harassment"
+	ReasonInappropriate = "inappropriate"
+	ReasonOther         = "other"
+)
+
+var reasons = map[string]bool{
+	ReasonSpam:          true,
+	ReasonScam:          true,
+	ReasonProhibited:    true,
+	ReasonCounterfeit:   true,
+	ReasonHarassment:    true,
+	ReasonInappropriate: true,
+	ReasonOther:         true,
+}
+
+type CreateReportRequest struct {
+	ReporterID uuid.UUID
+	TargetType string
+	TargetID   uuid.UUID
+	Reason     string
+	Details    string
+}
+
+type Report struct {
+	ID         uuid.UUID
+	ReporterID uuid.UUID
+	TargetType string
+	TargetID   uuid.UUID
+	Reason     string
+	Details    string
+	Status     string
+	ResolvedBy *uuid.UUID
+	ResolvedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// ReportDetail is what a moderator reviews before acting on a report
+type ReportDetail struct {
+	Report
+	// ReportCount is the number of reports of the target that were not dismissed
+	ReportCount int64
+	// Content is the title of the item, the name of the user or the text of the message
+	Content string
+	// TargetOwnerID is the owner of the item, the sender of the message or the user itself
+	TargetOwnerID uuid.UUID
+}
+
+type GetReportsRequest struct {
+	Status     string
+	TargetType string
+	Limit      int
+}
+
+type DismissReportRequest struct {
+	ModeratorID uuid.UUID
+	ReportID    uuid.UUID
+	Note        string
+}
+
+type HideItemRequest struct {
+	ModeratorID uuid.UUID
+	ItemID      uuid.UUID
+	// ReportID is the report the action was taken for, if any
+	ReportID *uuid.UUID
+	Note     string
+}
+
+type SuspendUserRequest struct {
+	ModeratorID uuid.UUID
+	UserID      uuid.UUID
+	// Until is nil for an indefinite suspension
+	Until *time.Time
+	// ReportID is the report the action was taken for, if any
+	ReportID *uuid.UUID
+	Note     string
+}
+
+type GetActionsRequest struct {
+	TargetType  string
+	TargetID    *uuid.UUID
+	ModeratorID *uuid.UUID
+	Since       *time.Time
+	Limit       int
+}
+
+type ModerationAction struct {
+	ID          uuid.UUID
+	ModeratorID *uuid.UUID
+	Action      string
+	TargetType  string
+	TargetID    uuid.UUID
+	ReportID    *uuid.UUID
+	Note        string
+	CreatedAt   time.Time
+}
+
+var ErrInvalidTargetType = fmt.Errorf("%w: invalid report target type", common.ErrInvalidInput)
+var ErrInvalidReason = fmt.Errorf("%w: invalid report reason", common.ErrInvalidInput)
+var ErrDetailsTooLong = fmt.Errorf("%w: report details must be at most %d characters", common.ErrInvalidInput, maxDetailsLength)
+var ErrCannotReportOwn = fmt.Errorf("%w: cannot report own content", common.ErrInvalidInput)
+var ErrAlreadyReported = fmt.Errorf("%w: target is already reported", common.ErrConflict)
+var ErrReportNotFound = fmt.Errorf("%w: report not found", common.ErrNotFound)
+var ErrTargetNotFound = fmt.Errorf("%w: report target not found", common.ErrNotFound)
+var ErrReportAlreadyResolved = fmt.Errorf("%w: report is already resolved", common.ErrConflict)
+var ErrCannotSuspendModerator = fmt.Errorf("%w: moderators cannot be suspended", common.ErrForbidden)
+
+func init() {
+	common.RegisterErrorMessages(ErrInvalidTargetType, map[common.Locale]string{
+		common.LocaleUz: "shikoyat obyekti turi noto'g'ri",
+		common.LocaleRu: "неверный тип объекта жалобы",
+	})
+	common.RegisterErrorMessages(ErrInvalidReason, map[common.Locale]string{
+		common.LocaleUz: "shikoyat sababi noto'g'ri",
+		common.LocaleRu: "неверная причина жалобы",
+	})
+	common.RegisterErrorMessages(ErrDetailsTooLong, map[common.Locale]string{
+		common.LocaleUz: fmt.Sprintf("shikoyat tafsilotlari ko'pi bilan %d belgi bo'lishi kerak", maxDetailsLength),
+		common.LocaleRu: fmt.Sprintf("подробности жалобы должны быть не длиннее %d символов", maxDetailsLength),
+	})
+	common.RegisterErrorMessages(ErrCannotReportOwn, map[common.Locale]string{
+		common.LocaleUz: "o'z kontentingiz ustidan shikoyat qila olmaysiz",
+		common.LocaleRu: "нельзя пожаловаться на свой контент",
+	})
+	common.RegisterErrorMessages(ErrAlreadyReported, map[common.Locale]string{
+		common.LocaleUz: "shikoyat allaqachon yuborilgan",
+		common.LocaleRu: "жалоба уже отправлена",
+	})
+	common.RegisterErrorMessages(ErrReportNotFound, map[common.Locale]string{
+		common.LocaleUz: "shikoyat topilmadi",
+		common.LocaleRu: "жалоба не найдена",
+	})
+	common.RegisterErrorMessages(ErrTargetNotFound, map[common.Locale]string{
+		common.LocaleUz: "shikoyat obyekti topilmadi",
+		common.LocaleRu: "объект жалобы не найден",
+	})
+	common.RegisterErrorMessages(ErrReportAlreadyResolved, map[common.Locale]string{
+		common.LocaleUz: "shikoyat allaqachon ko'rib chiqilgan",
+		common.LocaleRu: "жалоба уже рассмотрена",
+	})
+	common.RegisterErrorMessages(ErrCannotSuspendModerator, map[common.Locale]string{
+		common.LocaleUz: "moderatorlarni to'xtatib bo'lmaydi",
+		common.LocaleRu: "модераторов нельзя заблокировать",
+	})
+}
+
+type ModerationManager interface {
+	CreateReport(ctx context.Context, req CreateReportRequest) (*Report, error)
+	GetReports(ctx context.Context, req GetReportsRequest) ([]Report, error)
+	GetReport(ctx context.Context, reportID uuid.UUID) (*ReportDetail, error)
+	DismissReport(ctx context.Context, req DismissReportRequest) error
+	HideItem(ctx context.Context, req HideItemRequest) error
+	// SuspendUser locks the user out and ends every session of the user
+	SuspendUser(ctx context.Context, req SuspendUserRequest) error
+	GetActions(ctx context.Context, req GetActionsRequest) ([]ModerationAction, error)
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TargetType string
+
+const (
+	TargetTypeItem    TargetType = "item"
+	TargetTypeUser    TargetType = "user"
+	TargetTypeMessage TargetType = "message"
+)
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusDismissed ReportStatus = "dismissed"
+	// ReportStatusResolved is set when a moderator acted on the reported target
+	ReportStatusResolved ReportStatus = "resolved"
+)
+
+// Report is a user's complaint about an item, a user or a message,
+// a user can report the same target once.
+type Report struct {
+	ID         uuid.UUID    `gorm:"primaryKey;default:gen_random_uuid()"`
+	ReporterID uuid.UUID    `gorm:"uniqueIndex:idx_report_reporter_target"`
+	TargetType TargetType   `gorm:"uniqueIndex:idx_report_reporter_target;index:idx_report_target"`
+	TargetID   uuid.UUID    `gorm:"uniqueIndex:idx_report_reporter_target;index:idx_report_target"`
+	Reason     string       `gorm:"not null"`
+	Details    string       `gorm:"not null;default:''"`
+	Status     ReportStatus `gorm:"not null;default:open;index"`
+	ResolvedBy *uuid.UUID
+	ResolvedAt *time.Time
+	common.CreatedUpdated
+}
+
+type ReportFilter struct {
+	Status     ReportStatus
+	TargetType TargetType
+	Limit      int
+}
+
+type ReportRepository interface {
+	// Create returns false when the reporter already reported the target
+	Create(ctx context.Context, report *Report) (bool, error)
+	GetReport(ctx context.Context, reportID uuid.UUID) (*Report, error)
+	// GetReports returns the oldest reports first, so the queue is worked in order
+	GetReports(ctx context.Context, filter ReportFilter) ([]Report, error)
+	// CountTargetReports counts the reports of the target that were not dismissed
+	CountTargetReports(ctx context.Context, targetType TargetType, targetID uuid.UUID) (int64, error)
+	Resolve(ctx context.Context, reportID uuid.UUID, status ReportStatus, moderatorID uuid.UUID) error
+	// ResolveTargetReports resolves every open report of the target
+	ResolveTargetReports(ctx context.Context, targetType TargetType, targetID uuid.UUID, moderatorID uuid.UUID) error
+	Migrate() error
+}
+
+type ModerationActionType string
+
+const (
+	ModerationActionDismissReport ModerationActionType = "dismiss_report"
+	ModerationActionHideItem      ModerationActionType = "hide_item"
+	ModerationActionAutoHideItem  ModerationActionType = "auto_hide_item"
+	ModerationActionSuspendUser   ModerationActionType = "suspend_user"
+)
+
+// ModerationAction is the audit record of everything done to a reported target
+type ModerationAction struct {
+	ID uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	// ModeratorID is nil for actions taken automatically
+	ModeratorID *uuid.UUID `gorm:"index"`
+	Action      ModerationActionType
+	TargetType  TargetType `gorm:"index:idx_moderation_action_target"`
+	TargetID    uuid.UUID  `gorm:"index:idx_moderation_action_target"`
+	ReportID    *uuid.UUID
+	Note        string
+	common.CreatedUpdated
+}
+
+type ModerationActionFilter struct {
+	TargetType  TargetType
+	TargetID    *uuid.UUID
+	ModeratorID *uuid.UUID
+	Since       *time.Time
+	Limit       int
+}
+
+type ModerationActionRepository interface {
+	Create(ctx context.Context, action *ModerationAction) error
+	GetActions(ctx context.Context, filter ModerationActionFilter) ([]ModerationAction, error)
+	Migrate() error
+}
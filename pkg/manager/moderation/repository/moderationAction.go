@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+
+	"gorm.io/gorm"
+)
+
+type moderationActionRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationActionRepository(db *gorm.DB) ModerationActionRepository {
+	return &moderationActionRepository{
+		db,
+	}
+}
+
+func (r *moderationActionRepository) Create(ctx context.Context, action *ModerationAction) error {
+	resp := r.db.Create(action)
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if resp.RowsAffected != 1 {
+		return common.ErrMoreThanOneRowUpdated
+	}
+	return nil
+}
+
+func (r *moderationActionRepository) GetActions(ctx context.Context, filter ModerationActionFilter) ([]ModerationAction, error) {
+	var actions []ModerationAction = make([]ModerationAction, 0)
+	query := r.db.Model(&ModerationAction{})
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.ModeratorID != nil {
+		query = query.Where("moderator_id = ?", *filter.ModeratorID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	resp := query.Order("created_at DESC").Limit(filter.Limit).Find(&actions)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return actions, nil
+}
+
+func (r *moderationActionRepository) Migrate() error {
+	return r.db.AutoMigrate(&ModerationAction{})
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{
+		db,
+	}
+}
+
+func (r *reportRepository) Create(ctx context.Context, report *Report) (bool, error) {
+	resp := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(report)
+	if resp.Error != nil {
+		return false, resp.Error
+	}
+	return resp.RowsAffected == 1, nil
+}
+
+func (r *reportRepository) GetReport(ctx context.Context, reportID uuid.UUID) (*Report, error) {
+	var report Report
+	resp := r.db.Where("id = ?", reportID).First(&report)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &report, nil
+}
+
+func (r *reportRepository) GetReports(ctx context.Context, filter ReportFilter) ([]Report, error) {
+	var reports []Report = make([]Report, 0)
+	query := r.db.Model(&Report{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	resp := query.Order("created_at ASC").Limit(filter.Limit).Find(&reports)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return reports, nil
+}
+
+func (r *reportRepository) CountTargetReports(ctx context.Context, targetType TargetType, targetID uuid.UUID) (int64, error) {
+	var count int64
+	resp := r.db.Model(&Report{}).
+		Where("target_type = ? AND target_id = ? AND status != ?", targetType, targetID, ReportStatusDismissed).
+		Count(&count)
+	if resp.Error != nil {
+		return 0, resp.Error
+	}
+	return count, nil
+}
+
+func (r *reportRepository) Resolve(ctx context.Context, reportID uuid.UUID, status ReportStatus, moderatorID uuid.UUID) error {
+	resp := r.db.Model(&Report{}).Where("id = ?", reportID).Updates(map[string]interface{}{
+		"status":      status,
+		"resolved_by": moderatorID,
+		"resolved_at": time.Now(),
+	})
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if resp.RowsAffected != 1 {
+		return common.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *reportRepository) ResolveTargetReports(ctx context.Context, targetType TargetType, targetID uuid.UUID, moderatorID uuid.UUID) error {
+	return r.db.Model(&Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, ReportStatusOpen).
+		Updates(map[string]interface{}{
+			"status":      ReportStatusResolved,
+			"resolved_by": moderatorID,
+			"resolved_at": time.Now(),
+		}).Error
+}
+
+func (r *reportRepository) Migrate() error {
+	return r.db.AutoMigrate(&Report{})
+}
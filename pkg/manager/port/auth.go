@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	DeviceID   string
+	DeviceOS   string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+type AuthPort interface {
+	// RevokeUserTokens deletes every refresh token of the user and denies the access tokens issued so far
+	RevokeUserTokens(ctx context.Context, userID uuid.UUID) error
+	GetUserSessions(ctx context.Context, userID uuid.UUID) ([]Session, error)
+}
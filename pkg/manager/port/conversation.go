@@ -18,6 +18,43 @@ type Member struct {
 	MemberID uuid.UUID
 }
 
+type ExportedMessage struct {
+	ID        uuid.UUID
+	SenderID  uuid.UUID
+	Message   string
+	CreatedAt time.Time
+}
+
+type ExportedConversation struct {
+	ID        uuid.UUID
+	ItemID    uuid.UUID
+	CreatedAt time.Time
+	Messages  []ExportedMessage
+}
+
+type Message struct {
+	ID             uuid.UUID
+	ConversationID uuid.UUID
+	SenderID       uuid.UUID
+	Message        string
+	CreatedAt      time.Time
+	MemberIDs      []uuid.UUID
+}
+
+type ResponseStats struct {
+	ReceivedConversations  int64
+	RespondedConversations int64
+	// AverageResponseTime is nil when the user never responded
+	AverageResponseTime *time.Duration
+	LastMessagedAt      *time.Time
+}
+
 type ConversationPort interface {
 	GetItemConversations(ctx context.Context, itemID uuid.UUID) ([]Conversation, error)
+	// AnonymizeSender replaces the sender of the user's messages with common.DeletedUserID
+	AnonymizeSender(ctx context.Context, userID uuid.UUID) error
+	ExportUserConversations(ctx context.Context, userID uuid.UUID) ([]ExportedConversation, error)
+	GetUserResponseStats(ctx context.Context, userID uuid.UUID) (*ResponseStats, error)
+	// GetMessage returns the message with the members of its conversation
+	GetMessage(ctx context.Context, messageID uuid.UUID) (*Message, error)
 }
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -17,8 +18,54 @@ type Item struct {
 	OwnerID uuid.UUID
 }
 
+type ExportedItem struct {
+	ID          uuid.UUID
+	Title       string
+	Description string
+	Price       uint32
+	Negotiable  bool
+	ItemStatus  string
+	Size        float32
+	Weight      float32
+	KaratID     uuid.UUID
+	CategoryID  uuid.UUID
+	GeofenceID  uuid.UUID
+	CreatedAt   time.Time
+	// ImageKeys are only set for the user's own items and refer to storage.ContainerItems
+	ImageKeys []string
+}
+
+type UserItemsExport struct {
+	Items     []ExportedItem
+	Favorites []ExportedItem
+	Purchases []ExportedItem
+}
+
+// Purchase is the transaction of an item between its owner and a buyer
+type Purchase struct {
+	ID       uuid.UUID
+	ItemID   uuid.UUID
+	BuyerID  uuid.UUID
+	SellerID uuid.UUID
+}
+
+type UserItemStats struct {
+	// ActiveCount includes the reserved items, they are still listed
+	ActiveCount int64
+	SoldCount   int64
+	// LastItemUpdateAt is nil when the user never listed an item
+	LastItemUpdateAt *time.Time
+}
+
 type ItemPort interface {
 	GetItem(ctx context.Context, itemId uuid.UUID) (*Item, error)
 	GetCovertImage(ctx context.Context, itemId uuid.UUID) (string, error)
 	IncrementMessageCount(ctx context.Context, itemId uuid.UUID) error
+	HideUserItems(ctx context.Context, userID uuid.UUID) error
+	// HideModeratedItem hides the item for good, the owner cannot show it again
+	HideModeratedItem(ctx context.Context, itemID uuid.UUID) error
+	ExportUserItems(ctx context.Context, userID uuid.UUID) (*UserItemsExport, error)
+	GetUserItemStats(ctx context.Context, userID uuid.UUID) (*UserItemStats, error)
+	// GetPurchase returns gorm.ErrRecordNotFound when the buyer did not purchase the item
+	GetPurchase(ctx context.Context, itemID uuid.UUID, buyerID uuid.UUID) (*Purchase, error)
 }
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type RatingSummary struct {
+	Count int64
+	// Average is nil when the user has no reviews
+	Average *float64
+}
+
+type ReviewPort interface {
+	GetRatingSummary(ctx context.Context, userID uuid.UUID) (*RatingSummary, error)
+}
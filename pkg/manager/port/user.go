@@ -2,15 +2,25 @@ package port
 
 import (
 	"context"
+	"fmt"
+	"ketalk-api/common"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type CreateOrGetUserRequest struct {
+	Username      string
+	Email         string
+	Image         *string
+	Password      *string
+	EmailVerified bool
+	GeofenceID    uuid.UUID
+}
+
+type CreateUserWithPhoneRequest struct {
 	Username   string
-	Email      string
-	Image      *string
-	Password   *string
+	Phone      string
 	GeofenceID uuid.UUID
 }
 
@@ -21,9 +31,150 @@ type User struct {
 	Image      *string
 	Password   *string
 	GeofenceID uuid.UUID
+	// Verified is required for posting items and starting conversations,
+	// it is set once either the email or the phone number is verified
+	Verified      bool
+	EmailVerified bool
+	Role          common.Role
+	Phone         *string
+	// SuspendedAt is set while a moderator suspended the user, until SuspendedUntil or indefinitely when it is nil
+	SuspendedAt    *time.Time
+	SuspendedUntil *time.Time
+	// Locale is empty until the user picks one
+	Locale common.Locale
+}
+
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil))
+}
+
+// Identity is an account of an identity provider linked to a user
+type Identity struct {
+	UserID     uuid.UUID
+	Provider   string
+	ExternalID string
+	Email      string
+	LinkedAt   time.Time
+}
+
+type Block struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	BlockedAt time.Time
+}
+
+type NotificationChannel string
+
+const (
+	NotificationChannelPush  NotificationChannel = "push"
+	NotificationChannelEmail NotificationChannel = "email"
+	NotificationChannelInApp NotificationChannel = "in_app"
+)
+
+type NotificationEvent string
+
+const (
+	NotificationEventNewMessage     NotificationEvent = "new_message"
+	NotificationEventPriceDrop      NotificationEvent = "price_drop"
+	NotificationEventItemSold       NotificationEvent = "item_sold"
+	NotificationEventReviewReceived NotificationEvent = "review_received"
+)
+
+type NotificationPreferences struct {
+	Push           bool
+	Email          bool
+	InApp          bool
+	NewMessage     bool
+	PriceDrop      bool
+	ItemSold       bool
+	ReviewReceived bool
+	// QuietHours is nil when the user did not set any
+	QuietHours *QuietHours
+}
+
+// QuietHours wrap past midnight when End is before Start, e.g. 22:00 to 07:00
+type QuietHours struct {
+	// Start and End are minutes since midnight in Location
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+// Contains reports whether the time falls into the quiet hours, the end is excluded
+func (q *QuietHours) Contains(t time.Time) bool {
+	local := t.In(q.Location)
+	minute := local.Hour()*60 + local.Minute()
+	if q.Start <= q.End {
+		return minute >= q.Start && minute < q.End
+	}
+	return minute >= q.Start || minute < q.End
+}
+
+// Allows reports whether a notification of the event can be sent on the channel at the given time.
+// In-app notifications are only seen in the app, so quiet hours do not hold them back.
+func (p *NotificationPreferences) Allows(channel NotificationChannel, event NotificationEvent, now time.Time) bool {
+	var channelEnabled, eventEnabled bool
+	switch channel {
+	case NotificationChannelPush:
+		channelEnabled = p.Push
+	case NotificationChannelEmail:
+		channelEnabled = p.Email
+	case NotificationChannelInApp:
+		channelEnabled = p.InApp
+	}
+	switch event {
+	case NotificationEventNewMessage:
+		eventEnabled = p.NewMessage
+	case NotificationEventPriceDrop:
+		eventEnabled = p.PriceDrop
+	case NotificationEventItemSold:
+		eventEnabled = p.ItemSold
+	case NotificationEventReviewReceived:
+		eventEnabled = p.ReviewReceived
+	}
+	if !channelEnabled || !eventEnabled {
+		return false
+	}
+	return channel == NotificationChannelInApp || p.QuietHours == nil || !p.QuietHours.Contains(now)
+}
+
+var ErrIdentityAlreadyLinked = fmt.Errorf("%w: identity is already linked", common.ErrConflict)
+var ErrLastLoginMethod = fmt.Errorf("%w: the last login method can not be removed", common.ErrConflict)
+
+func init() {
+	common.RegisterErrorMessages(ErrIdentityAlreadyLinked, map[common.Locale]string{
+		common.LocaleUz: "bu hisob allaqachon bog'langan",
+		common.LocaleRu: "этот аккаунт уже привязан",
+	})
+	common.RegisterErrorMessages(ErrLastLoginMethod, map[common.Locale]string{
+		common.LocaleUz: "oxirgi kirish usulini o'chirib bo'lmaydi",
+		common.LocaleRu: "нельзя удалить последний способ входа",
+	})
 }
 
 type UserPort interface {
+	// CreateOrGetUser does not return an existing user whose email is unverified when the request has a verified email
 	CreateOrGetUser(ctx context.Context, req CreateOrGetUserRequest) (*User, error)
 	GetUser(ctx context.Context, userId uuid.UUID) (*User, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByPhone(ctx context.Context, phone string) (*User, error)
+	CreateUserWithPhone(ctx context.Context, req CreateUserWithPhoneRequest) (*User, error)
+	SetPhone(ctx context.Context, userId uuid.UUID, phone string) error
+	UpdatePassword(ctx context.Context, userId uuid.UUID, password string) error
+	SetEmailVerified(ctx context.Context, userId uuid.UUID) error
+	GetIdentity(ctx context.Context, provider string, externalID string) (*Identity, error)
+	GetUserIdentities(ctx context.Context, userId uuid.UUID) ([]Identity, error)
+	// LinkIdentity returns ErrIdentityAlreadyLinked when the identity or another one of the provider is linked already
+	LinkIdentity(ctx context.Context, identity Identity) error
+	// UnlinkIdentity returns ErrLastLoginMethod when the user could not sign in anymore without the identity,
+	// and gorm.ErrRecordNotFound when it is not linked
+	UnlinkIdentity(ctx context.Context, userId uuid.UUID, provider string) error
+	GetBlockedUserIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error)
+	// GetBlock returns gorm.ErrRecordNotFound when the blocker did not block the user
+	GetBlock(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (*Block, error)
+	HasBlockBetween(ctx context.Context, userId uuid.UUID, otherUserId uuid.UUID) (bool, error)
+	// SuspendUser locks the user out until the given time, or indefinitely when it is nil
+	SuspendUser(ctx context.Context, userId uuid.UUID, until *time.Time) error
+	// GetNotificationPreferences returns the defaults, everything enabled, when the user did not change them
+	GetNotificationPreferences(ctx context.Context, userId uuid.UUID) (*NotificationPreferences, error)
 }
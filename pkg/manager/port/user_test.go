@@ -0,0 +1,38 @@
+package port
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	tashkent := time.FixedZone("Asia/Tashkent", 5*60*60)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 10, hour, minute, 0, 0, tashkent)
+	}
+	night := &QuietHours{Start: 22 * 60, End: 7 * 60, Location: tashkent}
+	afternoon := &QuietHours{Start: 13 * 60, End: 15 * 60, Location: tashkent}
+	tests := []struct {
+		name  string
+		hours *QuietHours
+		t     time.Time
+		want  bool
+	}{
+		{"before the start", night, at(21, 59), false},
+		{"at the start", night, at(22, 0), true},
+		{"before midnight", night, at(23, 30), true},
+		{"at midnight", night, at(0, 0), true},
+		{"after midnight", night, at(3, 15), true},
+		{"the end is excluded", night, at(7, 0), false},
+		{"day time", night, at(12, 0), false},
+		{"in the location of the user", night, time.Date(2024, 3, 10, 18, 0, 0, 0, time.UTC), true},
+		{"same day inside", afternoon, at(14, 0), true},
+		{"same day at the end", afternoon, at(15, 0), false},
+		{"same day at night", afternoon, at(23, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.hours.Contains(tt.t); got != tt.want {
+			t.Errorf("%s: Contains(%s) = %v, want %v", tt.name, tt.t.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package review_manager
+
+import (
+	"context"
+	"errors"
+	"ketalk-api/common"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/pkg/manager/review/repository"
+	"ketalk-api/storage"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	minRating           = 1
+	maxRating           = 5
+	maxReviewTextLength = 1000
+	// reviewEditWindow is how long after creation a review can be changed
+	reviewEditWindow = 7 * 24 * time.Hour
+)
+
+type reviewManager struct {
+	reviewRepository repository.ReviewRepository
+	itemPort         port.ItemPort
+	userPort         port.UserPort
+	blobStorage      storage.Storage
+}
+
+func NewReviewManager(reviewRepository repository.ReviewRepository, itemPort port.ItemPort, userPort port.UserPort, blobStorage storage.Storage) ReviewManager {
+	return &reviewManager{
+		reviewRepository,
+		itemPort,
+		userPort,
+		blobStorage,
+	}
+}
+
+func (m *reviewManager) CreateReview(ctx context.Context, req CreateReviewRequest) (*Review, error) {
+	if err := validateReview(req.Rating, req.Text); err != nil {
+		return nil, err
+	}
+	item, err := m.itemPort.GetItem(ctx, req.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the buyer reviews the seller and the seller reviews the buyer
+	role := repository.ReviewerRoleBuyer
+	buyerID := req.ReviewerID
+	if item.OwnerID == req.ReviewerID {
+		if req.BuyerID == nil {
+			return nil, ErrBuyerRequired
+		}
+		role = repository.ReviewerRoleSeller
+		buyerID = *req.BuyerID
+	}
+	purchase, err := m.itemPort.GetPurchase(ctx, req.ItemID, buyerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotPurchased
+	} else if err != nil {
+		return nil, err
+	}
+	revieweeID := purchase.SellerID
+	if role == repository.ReviewerRoleSeller {
+		revieweeID = purchase.BuyerID
+	}
+
+	_, err = m.reviewRepository.GetPurchaseReview(ctx, purchase.ID, req.ReviewerID)
+	if err == nil {
+		return nil, ErrAlreadyReviewed
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	review := &repository.Review{
+		PurchaseID:   purchase.ID,
+		ReviewerID:   req.ReviewerID,
+		RevieweeID:   revieweeID,
+		ItemID:       purchase.ItemID,
+		ReviewerRole: role,
+		Rating:       uint8(req.Rating),
+		Text:         req.Text,
+	}
+	if err := m.reviewRepository.Create(ctx, review); errors.Is(err, gorm.ErrDuplicatedKey) {
+		// the same review was created in parallel
+		return nil, ErrAlreadyReviewed
+	} else if err != nil {
+		return nil, err
+	}
+	return m.toReview(ctx, review), nil
+}
+
+func (m *reviewManager) UpdateReview(ctx context.Context, req UpdateReviewRequest) (*Review, error) {
+	if err := validateReview(req.Rating, req.Text); err != nil {
+		return nil, err
+	}
+	review, err := m.reviewRepository.GetReview(ctx, req.ReviewID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrReviewNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if review.ReviewerID != req.ReviewerID {
+		return nil, ErrReviewNotFound
+	}
+	if time.Since(review.CreatedAt) > reviewEditWindow {
+		return nil, ErrReviewEditWindowClosed
+	}
+	now := time.Now()
+	review.Rating = uint8(req.Rating)
+	review.Text = req.Text
+	review.EditedAt = &now
+	if err := m.reviewRepository.Update(ctx, review); err != nil {
+		return nil, err
+	}
+	return m.toReview(ctx, review), nil
+}
+
+func (m *reviewManager) GetUserReviews(ctx context.Context, userID uuid.UUID) (*UserReviews, error) {
+	summary, err := m.reviewRepository.GetRatingSummary(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := m.reviewRepository.GetUserReviews(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &UserReviews{
+		Summary: RatingSummary{
+			Count:   summary.Count,
+			Average: summary.Average,
+		},
+		Reviews: make([]Review, len(reviews)),
+	}
+	for i := range reviews {
+		resp.Reviews[i] = *m.toReview(ctx, &reviews[i])
+	}
+	return resp, nil
+}
+
+func (m *reviewManager) toReview(ctx context.Context, review *repository.Review) *Review {
+	// reviews of deleted users still count, they are shown without the reviewer
+	reviewer := Reviewer{
+		ID: common.DeletedUserID,
+	}
+	user, err := m.userPort.GetUser(ctx, review.ReviewerID)
+	if err == nil {
+		reviewer = Reviewer{
+			ID:   user.ID,
+			Name: user.Username,
+		}
+		if user.Image != nil {
+			image := m.blobStorage.GetUserImage(*user.Image)
+			reviewer.Avatar = &image
+		}
+	}
+	return &Review{
+		ID:            review.ID,
+		ItemID:        review.ItemID,
+		Reviewer:      reviewer,
+		RevieweeID:    review.RevieweeID,
+		ReviewerRole:  string(review.ReviewerRole),
+		Rating:        int(review.Rating),
+		Text:          review.Text,
+		CreatedAt:     review.CreatedAt,
+		EditedAt:      review.EditedAt,
+		EditableUntil: review.CreatedAt.Add(reviewEditWindow),
+	}
+}
+
+func validateReview(rating int, text string) error {
+	if rating < minRating || rating > maxRating {
+		return ErrInvalidRating
+	}
+	if utf8.RuneCountInString(text) > maxReviewTextLength {
+		return ErrReviewTooLong
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package review_manager
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateReviewRequest struct {
+	ReviewerID uuid.UUID
+	ItemID     uuid.UUID
+	// BuyerID is required when the seller reviews, an item can be sold to several buyers
+	BuyerID *uuid.UUID
+	Rating  int
+	Text    string
+}
+
+type UpdateReviewRequest struct {
+	ReviewID   uuid.UUID
+	ReviewerID uuid.UUID
+	Rating     int
+	Text       string
+}
+
+type Reviewer struct {
+	ID     uuid.UUID
+	Name   string
+	Avatar *string
+}
+
+type Review struct {
+	ID           uuid.UUID
+	ItemID       uuid.UUID
+	Reviewer     Reviewer
+	RevieweeID   uuid.UUID
+	ReviewerRole string
+	Rating       int
+	Text         string
+	CreatedAt    time.Time
+	EditedAt     *time.Time
+	// EditableUntil is when the review can no longer be changed
+	EditableUntil time.Time
+}
+
+type RatingSummary struct {
+	Count   int64
+	Average *float64
+}
+
+type UserReviews struct {
+	Summary RatingSummary
+	Reviews []Review
+}
+
+var ErrInvalidRating = fmt.Errorf("%w: rating must be between %d and %d", common.ErrInvalidInput, minRating, maxRating)
+var ErrReviewTooLong = fmt.Errorf("%w: review text must be at most %d characters", common.ErrInvalidInput, maxReviewTextLength)
+var ErrNotPurchased = fmt.Errorf("%w: the item was not purchased", common.ErrForbidden)
+var ErrBuyerRequired = fmt.Errorf("%w: buyer is required to review as the seller", common.ErrInvalidInput)
+var ErrAlreadyReviewed = fmt.Errorf("%w: purchase is already reviewed", common.ErrConflict)
+var ErrReviewNotFound = fmt.Errorf("%w: review not found", common.ErrNotFound)
+var ErrReviewEditWindowClosed = fmt.Errorf("%w: review can no longer be edited", common.ErrForbidden)
+
+func init() {
+	common.RegisterErrorMessages(ErrInvalidRating, map[common.Locale]string{
+		common.LocaleUz: fmt.Sprintf("baho %d dan %d gacha bo'lishi kerak", minRating, maxRating),
+		common.LocaleRu: fmt.Sprintf("оценка должна быть от %d до %d", minRating, maxRating),
+	})
+	common.RegisterErrorMessages(ErrReviewTooLong, map[common.Locale]string{
+		common.LocaleUz: fmt.Sprintf("sharh ko'pi bilan %d belgi bo'lishi kerak", maxReviewTextLength),
+		common.LocaleRu: fmt.Sprintf("отзыв должен быть не длиннее %d символов", maxReviewTextLength),
+	})
+	common.RegisterErrorMessages(ErrNotPurchased, map[common.Locale]string{
+		common.LocaleUz: "e'lon sotib olinmagan",
+		common.LocaleRu: "объявление не было куплено",
+	})
+	common.RegisterErrorMessages(ErrBuyerRequired, map[common.Locale]string{
+		common.LocaleUz: "sotuvchi sifatida baholash uchun xaridor ko'rsatilishi kerak",
+		common.LocaleRu: "чтобы оставить отзыв как продавец, укажите покупателя",
+	})
+	common.RegisterErrorMessages(ErrAlreadyReviewed, map[common.Locale]string{
+		common.LocaleUz: "xaridga allaqachon sharh qoldirilgan",
+		common.LocaleRu: "отзыв о покупке уже оставлен",
+	})
+	common.RegisterErrorMessages(ErrReviewNotFound, map[common.Locale]string{
+		common.LocaleUz: "sharh topilmadi",
+		common.LocaleRu: "отзыв не найден",
+	})
+	common.RegisterErrorMessages(ErrReviewEditWindowClosed, map[common.Locale]string{
+		common.LocaleUz: "sharhni endi tahrirlab bo'lmaydi",
+		common.LocaleRu: "отзыв больше нельзя изменить",
+	})
+}
+
+type ReviewManager interface {
+	// CreateReview reviews the other party of the purchase of the item
+	CreateReview(ctx context.Context, req CreateReviewRequest) (*Review, error)
+	UpdateReview(ctx context.Context, req UpdateReviewRequest) (*Review, error)
+	GetUserReviews(ctx context.Context, userID uuid.UUID) (*UserReviews, error)
+}
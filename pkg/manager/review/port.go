@@ -0,0 +1,30 @@
+package review_manager
+
+import (
+	"context"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/pkg/manager/review/repository"
+
+	"github.com/google/uuid"
+)
+
+type reviewPort struct {
+	reviewRepository repository.ReviewRepository
+}
+
+func NewReviewPort(reviewRepository repository.ReviewRepository) port.ReviewPort {
+	return &reviewPort{
+		reviewRepository,
+	}
+}
+
+func (p *reviewPort) GetRatingSummary(ctx context.Context, userID uuid.UUID) (*port.RatingSummary, error) {
+	summary, err := p.reviewRepository.GetRatingSummary(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &port.RatingSummary{
+		Count:   summary.Count,
+		Average: summary.Average,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReviewerRole string
+
+const (
+	ReviewerRoleBuyer  ReviewerRole = "buyer"
+	ReviewerRoleSeller ReviewerRole = "seller"
+)
+
+// Review is left by one party of a purchase about the other one,
+// each party can review a purchase once.
+type Review struct {
+	ID uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	// PurchaseID is the user item marked as purchased
+	PurchaseID   uuid.UUID `gorm:"uniqueIndex:idx_review_purchase_reviewer"`
+	ReviewerID   uuid.UUID `gorm:"uniqueIndex:idx_review_purchase_reviewer"`
+	RevieweeID   uuid.UUID `gorm:"index"`
+	ItemID       uuid.UUID
+	ReviewerRole ReviewerRole
+	Rating       uint8
+	Text         string
+	EditedAt     *time.Time
+	common.CreatedUpdatedDeleted
+}
+
+type RatingSummary struct {
+	Count int64
+	// Average is nil when the user has no reviews
+	Average *float64
+}
+
+type ReviewRepository interface {
+	Create(ctx context.Context, review *Review) error
+	Update(ctx context.Context, review *Review) error
+	GetReview(ctx context.Context, reviewID uuid.UUID) (*Review, error)
+	GetPurchaseReview(ctx context.Context, purchaseID uuid.UUID, reviewerID uuid.UUID) (*Review, error)
+	GetUserReviews(ctx context.Context, revieweeID uuid.UUID) ([]Review, error)
+	GetRatingSummary(ctx context.Context, revieweeID uuid.UUID) (*RatingSummary, error)
+	Migrate() error
+}
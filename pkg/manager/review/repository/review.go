@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{
+		db,
+	}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review *Review) error {
+	res := r.db.Create(review)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return common.ErrMoreThanOneRowUpdated
+	}
+	return nil
+}
+
+func (r *reviewRepository) Update(ctx context.Context, review *Review) error {
+	res := r.db.Model(review).Where("id = ?", review.ID).Updates(map[string]interface{}{
+		"rating":    review.Rating,
+		"text":      review.Text,
+		"edited_at": review.EditedAt,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return common.ErrMoreThanOneRowUpdated
+	}
+	return nil
+}
+
+func (r *reviewRepository) GetReview(ctx context.Context, reviewID uuid.UUID) (*Review, error) {
+	var review Review
+	resp := r.db.Where("id = ?", reviewID).First(&review)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) GetPurchaseReview(ctx context.Context, purchaseID uuid.UUID, reviewerID uuid.UUID) (*Review, error) {
+	var review Review
+	resp := r.db.Where("purchase_id = ? AND reviewer_id = ?", purchaseID, reviewerID).First(&review)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &review, nil
+}
+
+func (r *reviewRepository) GetUserReviews(ctx context.Context, revieweeID uuid.UUID) ([]Review, error) {
+	var reviews []Review = make([]Review, 0)
+	resp := r.db.Where("reviewee_id = ?", revieweeID).Order("created_at DESC").Find(&reviews)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return reviews, nil
+}
+
+func (r *reviewRepository) GetRatingSummary(ctx context.Context, revieweeID uuid.UUID) (*RatingSummary, error) {
+	var summary RatingSummary
+	resp := r.db.Model(&Review{}).
+		Select("COUNT(*) AS count, AVG(rating) AS average").
+		Where("reviewee_id = ?", revieweeID).
+		Scan(&summary)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &summary, nil
+}
+
+func (r *reviewRepository) Migrate() error {
+	return r.db.AutoMigrate(&Review{})
+}
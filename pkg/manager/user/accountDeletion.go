@@ -0,0 +1,156 @@
+package user_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/pkg/manager/user/repository"
+	"ketalk-api/storage"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	accountDeletionPollInterval = time.Minute
+	// accountDeletionLease is how long a claimed job is hidden from other workers
+	accountDeletionLease       = 5 * time.Minute
+	accountDeletionMaxAttempts = 10
+	accountDeletionBackoff     = 30 * time.Second
+	accountDeletionMaxBackoff  = time.Hour
+)
+
+type AccountDeletionWorker interface {
+	// Run processes due jobs until the context is done
+	Run(ctx context.Context)
+	// Notify wakes the worker up so a new job does not wait for the next poll
+	Notify()
+}
+
+type accountDeletionStep struct {
+	name string
+	run  func(ctx context.Context, userID uuid.UUID) error
+}
+
+type accountDeletionWorker struct {
+	accountDeletionRepository repository.AccountDeletionRepository
+	userRepository            repository.Repository
+	authPort                  port.AuthPort
+	itemPort                  port.ItemPort
+	conversationPort          port.ConversationPort
+	blobStorage               storage.Storage
+	wake                      chan struct{}
+}
+
+func NewAccountDeletionWorker(accountDeletionRepository repository.AccountDeletionRepository, userRepository repository.Repository, authPort port.AuthPort, itemPort port.ItemPort, conversationPort port.ConversationPort, blobStorage storage.Storage) AccountDeletionWorker {
+	return &accountDeletionWorker{
+		accountDeletionRepository,
+		userRepository,
+		authPort,
+		itemPort,
+		conversationPort,
+		blobStorage,
+		make(chan struct{}, 1),
+	}
+}
+
+func (w *accountDeletionWorker) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *accountDeletionWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(accountDeletionPollInterval)
+	defer ticker.Stop()
+	for {
+		w.processDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+func (w *accountDeletionWorker) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deletion, err := w.accountDeletionRepository.ClaimNext(ctx, accountDeletionLease)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return
+		} else if err != nil {
+			log.Printf("failed to claim account deletion: %v\n", err)
+			return
+		}
+		w.process(ctx, deletion)
+	}
+}
+
+// steps are run in order and each one is idempotent, the job records the last completed step
+// so a retry only repeats the step that failed
+func (w *accountDeletionWorker) steps() []accountDeletionStep {
+	return []accountDeletionStep{
+		{"delete user", w.userRepository.SoftDeleteUser},
+		{"revoke tokens", w.authPort.RevokeUserTokens},
+		{"hide items", w.itemPort.HideUserItems},
+		{"anonymize messages", w.conversationPort.AnonymizeSender},
+		{"delete profile images", w.deleteProfileImages},
+		{"delete data exports", w.deleteDataExports},
+	}
+}
+
+func (w *accountDeletionWorker) process(ctx context.Context, deletion *repository.AccountDeletion) {
+	steps := w.steps()
+	for i := deletion.Step; i < len(steps); i++ {
+		if err := steps[i].run(ctx, deletion.UserID); err != nil {
+			w.retry(ctx, deletion, fmt.Errorf("%s: %w", steps[i].name, err))
+			return
+		}
+		if err := w.accountDeletionRepository.SetStep(ctx, deletion.ID, i+1); err != nil {
+			w.retry(ctx, deletion, err)
+			return
+		}
+	}
+	if err := w.accountDeletionRepository.Complete(ctx, deletion.ID); err != nil {
+		log.Printf("failed to complete account deletion %s: %v\n", deletion.ID, err)
+	}
+}
+
+func (w *accountDeletionWorker) retry(ctx context.Context, deletion *repository.AccountDeletion, cause error) {
+	log.Printf("account deletion %s failed on attempt %d: %v\n", deletion.ID, deletion.Attempts, cause)
+	var err error
+	if deletion.Attempts >= accountDeletionMaxAttempts {
+		err = w.accountDeletionRepository.Fail(ctx, deletion.ID, cause.Error())
+	} else {
+		err = w.accountDeletionRepository.Retry(ctx, deletion.ID, cause.Error(), time.Now().Add(backoff(deletion.Attempts)))
+	}
+	if err != nil {
+		// the lease expires on its own, so the job is picked up again anyway
+		log.Printf("failed to release account deletion %s: %v\n", deletion.ID, err)
+	}
+}
+
+func (w *accountDeletionWorker) deleteProfileImages(ctx context.Context, userID uuid.UUID) error {
+	// profile images are uploaded as "<userID>/<uuid>", see GetPresignedUrl
+	return w.blobStorage.DeleteObjects(ctx, fmt.Sprintf("%s/", userID), storage.ContainerProfiles)
+}
+
+func (w *accountDeletionWorker) deleteDataExports(ctx context.Context, userID uuid.UUID) error {
+	return w.blobStorage.DeleteObjects(ctx, fmt.Sprintf("%s/", userID), storage.ContainerExports)
+}
+
+func backoff(attempts int) time.Duration {
+	d := accountDeletionBackoff
+	for i := 1; i < attempts && d < accountDeletionMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > accountDeletionMaxBackoff {
+		return accountDeletionMaxBackoff
+	}
+	return d
+}
@@ -0,0 +1,62 @@
+package user_manager
+
+import (
+	"context"
+	"errors"
+	"ketalk-api/pkg/manager/user/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (m *userManager) BlockUser(ctx context.Context, req BlockUserRequest) error {
+	if req.UserID == req.BlockedUserID {
+		return ErrCannotBlockSelf
+	}
+	_, err := m.repository.GetUser(ctx, req.BlockedUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrUserNotFound
+	} else if err != nil {
+		return err
+	}
+	return m.userBlockRepository.Create(ctx, &repository.UserBlock{
+		BlockerID: req.UserID,
+		BlockedID: req.BlockedUserID,
+	})
+}
+
+func (m *userManager) UnblockUser(ctx context.Context, req BlockUserRequest) error {
+	err := m.userBlockRepository.Delete(ctx, req.UserID, req.BlockedUserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrBlockNotFound
+	}
+	return err
+}
+
+func (m *userManager) GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]BlockedUser, error) {
+	blocks, err := m.userBlockRepository.GetBlocks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	blockedUsers := make([]BlockedUser, 0, len(blocks))
+	for _, block := range blocks {
+		user, err := m.repository.GetUser(ctx, block.BlockedID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		var url *string
+		if user.Image != nil {
+			image := m.azureBlobStorage.GetUserImage(*user.Image)
+			url = &image
+		}
+		blockedUsers = append(blockedUsers, BlockedUser{
+			ID:        user.ID,
+			Username:  user.Username,
+			Image:     url,
+			BlockedAt: block.CreatedAt,
+		})
+	}
+	return blockedUsers, nil
+}
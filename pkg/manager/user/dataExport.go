@@ -0,0 +1,401 @@
+package user_manager
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"ketalk-api/common"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/pkg/manager/user/repository"
+	"ketalk-api/storage"
+	"log"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	dataExportPollInterval = time.Minute
+	// dataExportLease is how long a claimed export is hidden from other workers
+	dataExportLease       = 15 * time.Minute
+	dataExportMaxAttempts = 5
+	// dataExportPurgeBatch is how many expired archives are removed per poll
+	dataExportPurgeBatch = 100
+)
+
+type ExportConfig struct {
+	// ExpiryDuration is how long the archive can be downloaded after it was built
+	ExpiryDuration time.Duration `yaml:"expiryDuration" env:"USER_EXPORT_EXPIRY_DURATION" env-default:"72h"`
+}
+
+type DataExportWorker interface {
+	// Run builds due exports and removes expired archives until the context is done
+	Run(ctx context.Context)
+	// Notify wakes the worker up so a new export does not wait for the next poll
+	Notify()
+}
+
+type dataExportWorker struct {
+	dataExportRepository   repository.DataExportRepository
+	userRepository         repository.Repository
+	userGeofenceRepository repository.UserGeofenceRepository
+	geofencePort           port.GeofencePort
+	authPort               port.AuthPort
+	itemPort               port.ItemPort
+	conversationPort       port.ConversationPort
+	blobStorage            storage.Storage
+	cfg                    ExportConfig
+	wake                   chan struct{}
+}
+
+func NewDataExportWorker(dataExportRepository repository.DataExportRepository, userRepository repository.Repository, userGeofenceRepository repository.UserGeofenceRepository, geofencePort port.GeofencePort, authPort port.AuthPort, itemPort port.ItemPort, conversationPort port.ConversationPort, blobStorage storage.Storage, cfg ExportConfig) DataExportWorker {
+	return &dataExportWorker{
+		dataExportRepository,
+		userRepository,
+		userGeofenceRepository,
+		geofencePort,
+		authPort,
+		itemPort,
+		conversationPort,
+		blobStorage,
+		cfg,
+		make(chan struct{}, 1),
+	}
+}
+
+func (w *dataExportWorker) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (w *dataExportWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(dataExportPollInterval)
+	defer ticker.Stop()
+	for {
+		w.processDue(ctx)
+		w.purgeExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.wake:
+		}
+	}
+}
+
+func (w *dataExportWorker) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		export, err := w.dataExportRepository.ClaimNext(ctx, dataExportLease)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return
+		} else if err != nil {
+			log.Printf("failed to claim data export: %v\n", err)
+			return
+		}
+		w.process(ctx, export)
+	}
+}
+
+func (w *dataExportWorker) process(ctx context.Context, export *repository.DataExport) {
+	objectName := fmt.Sprintf("%s/%s.zip", export.UserID, export.ID)
+	if err := w.buildAndUpload(ctx, export.UserID, objectName); err != nil {
+		w.retry(ctx, export, err)
+		return
+	}
+	if err := w.dataExportRepository.Complete(ctx, export.ID, objectName, time.Now().Add(w.cfg.ExpiryDuration)); err != nil {
+		log.Printf("failed to complete data export %s: %v\n", export.ID, err)
+	}
+}
+
+func (w *dataExportWorker) retry(ctx context.Context, export *repository.DataExport, cause error) {
+	log.Printf("data export %s failed on attempt %d: %v\n", export.ID, export.Attempts, cause)
+	var err error
+	if export.Attempts >= dataExportMaxAttempts {
+		err = w.dataExportRepository.Fail(ctx, export.ID, cause.Error())
+	} else {
+		err = w.dataExportRepository.Retry(ctx, export.ID, cause.Error(), time.Now().Add(backoff(export.Attempts)))
+	}
+	if err != nil {
+		// the lease expires on its own, so the export is picked up again anyway
+		log.Printf("failed to release data export %s: %v\n", export.ID, err)
+	}
+}
+
+func (w *dataExportWorker) purgeExpired(ctx context.Context) {
+	exports, err := w.dataExportRepository.GetExpiredExports(ctx, dataExportPurgeBatch)
+	if err != nil {
+		log.Printf("failed to get expired data exports: %v\n", err)
+		return
+	}
+	for _, export := range exports {
+		if err := w.blobStorage.DeleteObjects(ctx, export.ObjectName, storage.ContainerExports); err != nil {
+			log.Printf("failed to delete data export %s: %v\n", export.ID, err)
+			continue
+		}
+		if err := w.dataExportRepository.SetExpired(ctx, export.ID); err != nil {
+			log.Printf("failed to expire data export %s: %v\n", export.ID, err)
+		}
+	}
+}
+
+// buildAndUpload writes the archive to a temporary file first, so exports with many images are not held in memory
+func (w *dataExportWorker) buildAndUpload(ctx context.Context, userID uuid.UUID, objectName string) error {
+	file, err := os.CreateTemp("", "export-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	if err := w.writeArchive(ctx, archive, userID); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return w.blobStorage.PutObject(ctx, objectName, storage.ContainerExports, file, size, "application/zip")
+}
+
+func (w *dataExportWorker) writeArchive(ctx context.Context, archive *zip.Writer, userID uuid.UUID) error {
+	user, err := w.userRepository.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	profile := exportedProfile{
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		EmailVerified:   user.EmailVerified,
+		Phone:           user.Phone,
+		PhoneVerifiedAt: user.PhoneVerifiedAt,
+		Image:           user.Image,
+		Role:            user.Role,
+		CreatedAt:       user.CreatedAt,
+	}
+	userGeofence, err := w.userGeofenceRepository.GetUserGeofence(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if userGeofence != nil {
+		geofence, err := w.geofencePort.GetGeofenceById(ctx, userGeofence.GeofenceID)
+		if err != nil {
+			return err
+		}
+		profile.Geofence = &exportedGeofence{
+			ID:   geofence.ID,
+			Name: geofence.Name,
+		}
+	}
+
+	userItems, err := w.itemPort.ExportUserItems(ctx, userID)
+	if err != nil {
+		return err
+	}
+	conversations, err := w.conversationPort.ExportUserConversations(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sessions, err := w.authPort.GetUserSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name  string
+		value interface{}
+	}{
+		{"profile.json", profile},
+		{"items.json", toExportedItems(userItems.Items)},
+		{"favorites.json", toExportedItems(userItems.Favorites)},
+		{"purchases.json", toExportedItems(userItems.Purchases)},
+		{"conversations.json", toExportedConversations(conversations)},
+		{"sessions.json", toExportedSessions(sessions)},
+	}
+	for _, f := range files {
+		if err := writeJSON(archive, f.name, f.value); err != nil {
+			return err
+		}
+	}
+
+	var missingImages []string
+	// images linked from an identity provider are not stored by us
+	if user.Image != nil && !strings.Contains(*user.Image, "http") {
+		name := path.Join("images", "profile", path.Base(*user.Image))
+		if err := w.copyImage(ctx, archive, name, *user.Image, storage.ContainerProfiles); err != nil {
+			log.Printf("failed to export profile image %s: %v\n", *user.Image, err)
+			missingImages = append(missingImages, name)
+		}
+	}
+	for _, item := range userItems.Items {
+		for _, key := range item.ImageKeys {
+			name := path.Join("images", "items", item.ID.String(), path.Base(key))
+			if err := w.copyImage(ctx, archive, name, key, storage.ContainerItems); err != nil {
+				log.Printf("failed to export item image %s: %v\n", key, err)
+				missingImages = append(missingImages, name)
+			}
+		}
+	}
+	// a missing image should not fail the whole export, the archive lists what could not be included
+	if len(missingImages) > 0 {
+		return writeJSON(archive, "missing_images.json", missingImages)
+	}
+	return nil
+}
+
+func (w *dataExportWorker) copyImage(ctx context.Context, archive *zip.Writer, name, key, containerName string) error {
+	body, err := w.blobStorage.GetObject(ctx, key, containerName)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	writer, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, body)
+	return err
+}
+
+func writeJSON(archive *zip.Writer, name string, value interface{}) error {
+	writer, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+type exportedGeofence struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type exportedProfile struct {
+	ID              uuid.UUID         `json:"id"`
+	Username        string            `json:"username"`
+	Email           string            `json:"email"`
+	EmailVerified   bool              `json:"emailVerified"`
+	Phone           *string           `json:"phone"`
+	PhoneVerifiedAt *time.Time        `json:"phoneVerifiedAt"`
+	Image           *string           `json:"image"`
+	Role            common.Role       `json:"role"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	Geofence        *exportedGeofence `json:"geofence"`
+}
+
+type exportedItem struct {
+	ID          uuid.UUID `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Price       uint32    `json:"price"`
+	Negotiable  bool      `json:"negotiable"`
+	ItemStatus  string    `json:"itemStatus"`
+	Size        float32   `json:"size"`
+	Weight      float32   `json:"weight"`
+	KaratID     uuid.UUID `json:"karatId"`
+	CategoryID  uuid.UUID `json:"categoryId"`
+	GeofenceID  uuid.UUID `json:"geofenceId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Images      []string  `json:"images,omitempty"`
+}
+
+type exportedMessage struct {
+	ID        uuid.UUID `json:"id"`
+	SenderID  uuid.UUID `json:"senderId"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type exportedConversation struct {
+	ID        uuid.UUID         `json:"id"`
+	ItemID    uuid.UUID         `json:"itemId"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Messages  []exportedMessage `json:"messages"`
+}
+
+type exportedSession struct {
+	DeviceID   string    `json:"deviceId"`
+	DeviceOS   string    `json:"deviceOs"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+}
+
+func toExportedItems(items []port.ExportedItem) []exportedItem {
+	exported := make([]exportedItem, len(items))
+	for i, item := range items {
+		images := make([]string, len(item.ImageKeys))
+		for j, key := range item.ImageKeys {
+			images[j] = path.Join("images", "items", item.ID.String(), path.Base(key))
+		}
+		exported[i] = exportedItem{
+			ID:          item.ID,
+			Title:       item.Title,
+			Description: item.Description,
+			Price:       item.Price,
+			Negotiable:  item.Negotiable,
+			ItemStatus:  item.ItemStatus,
+			Size:        item.Size,
+			Weight:      item.Weight,
+			KaratID:     item.KaratID,
+			CategoryID:  item.CategoryID,
+			GeofenceID:  item.GeofenceID,
+			CreatedAt:   item.CreatedAt,
+			Images:      images,
+		}
+	}
+	return exported
+}
+
+func toExportedConversations(conversations []port.ExportedConversation) []exportedConversation {
+	exported := make([]exportedConversation, len(conversations))
+	for i, conversation := range conversations {
+		messages := make([]exportedMessage, len(conversation.Messages))
+		for j, message := range conversation.Messages {
+			messages[j] = exportedMessage{
+				ID:        message.ID,
+				SenderID:  message.SenderID,
+				Message:   message.Message,
+				CreatedAt: message.CreatedAt,
+			}
+		}
+		exported[i] = exportedConversation{
+			ID:        conversation.ID,
+			ItemID:    conversation.ItemID,
+			CreatedAt: conversation.CreatedAt,
+			Messages:  messages,
+		}
+	}
+	return exported
+}
+
+func toExportedSessions(sessions []port.Session) []exportedSession {
+	exported := make([]exportedSession, len(sessions))
+	for i, session := range sessions {
+		exported[i] = exportedSession{
+			DeviceID:   session.DeviceID,
+			DeviceOS:   session.DeviceOS,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+		}
+	}
+	return exported
+}
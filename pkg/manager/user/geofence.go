@@ -0,0 +1,102 @@
+package user_manager
+
+import (
+	"context"
+	"ketalk-api/common"
+	"ketalk-api/pkg/manager/user/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GeofenceConfig struct {
+	// ChangeCooldown is how long a user has to stay in a neighborhood before switching to another one
+	ChangeCooldown time.Duration `yaml:"changeCooldown" env:"USER_GEOFENCE_CHANGE_COOLDOWN" env-default:"720h"`
+}
+
+func (m *userManager) VerifyGeofence(ctx context.Context, req VerifyGeofenceRequest) (*GeofenceVerification, error) {
+	current, err := m.userGeofenceRepository.GetUserGeofence(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	geofence, err := m.geofencePort.GetGeofenceByLocation(ctx, req.Location)
+	if err != nil {
+		return nil, err
+	}
+	history, err := m.userGeofenceRepository.GetUserGeofenceHistory(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// the geofence picked at signup is not a change, the cooldown starts with the first switch
+	var nextChangeAt *time.Time
+	if t := current.CreatedAt.Add(m.geofenceCfg.ChangeCooldown); len(history) > 1 && now.Before(t) {
+		nextChangeAt = &t
+	}
+
+	resp := &GeofenceVerification{
+		Geofence: Geofence{
+			ID:   geofence.ID,
+			Name: geofence.Name,
+		},
+		VerifiedAt:   now,
+		NextChangeAt: nextChangeAt,
+	}
+	if geofence.ID == current.GeofenceID {
+		if err := m.userGeofenceRepository.SetVerified(ctx, current.ID, now); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	if nextChangeAt != nil {
+		return nil, &common.RetryAfterError{
+			Err:        ErrGeofenceChangeCooldown,
+			RetryAfter: nextChangeAt.Sub(now),
+		}
+	}
+	if err := m.userGeofenceRepository.Replace(ctx, &repository.UserGeofence{
+		UserID:     req.UserID,
+		GeofenceID: geofence.ID,
+		VerifiedAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+	next := now.Add(m.geofenceCfg.ChangeCooldown)
+	resp.Changed = true
+	resp.NextChangeAt = &next
+	return resp, nil
+}
+
+func (m *userManager) GetGeofenceHistory(ctx context.Context, userID uuid.UUID) ([]GeofenceHistoryEntry, error) {
+	history, err := m.userGeofenceRepository.GetUserGeofenceHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string)
+	resp := make([]GeofenceHistoryEntry, len(history))
+	for i, userGeofence := range history {
+		name, ok := names[userGeofence.GeofenceID]
+		if !ok {
+			geofence, err := m.geofencePort.GetGeofenceById(ctx, userGeofence.GeofenceID)
+			if err != nil {
+				return nil, err
+			}
+			name = geofence.Name
+			names[userGeofence.GeofenceID] = name
+		}
+		resp[i] = GeofenceHistoryEntry{
+			Geofence: Geofence{
+				ID:   userGeofence.GeofenceID,
+				Name: name,
+			},
+			From:       userGeofence.CreatedAt,
+			VerifiedAt: userGeofence.VerifiedAt,
+		}
+		if userGeofence.DeletedAt.Valid {
+			until := userGeofence.DeletedAt.Time
+			resp[i].Until = &until
+		}
+	}
+	return resp, nil
+}
@@ -1,28 +1,76 @@
 package user_manager
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"ketalk-api/pkg/manager/port"
+	user_redis "ketalk-api/pkg/manager/user/redis"
 	"ketalk-api/pkg/manager/user/repository"
 	"ketalk-api/storage"
+	"log"
+	"net/http"
+	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// maxDownloadUrlExpiry is the longest expiry a presigned link can have
+const maxDownloadUrlExpiry = 7 * 24 * time.Hour
+
+const (
+	// pendingUploadTTL is how long an image name from GetPresignedUrl can be set, as long as its upload link is valid
+	pendingUploadTTL = 24 * time.Hour
+	// maxProfileImageSize is the largest profile image in bytes
+	maxProfileImageSize = 5 << 20
+)
+
+// profileImageContentTypes are the images a profile image can be, svg is left out as it can carry scripts
+var profileImageContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/heic": true,
+}
+
 type userManager struct {
-	repository             repository.Repository
-	userGeofenceRepository repository.UserGeofenceRepository
-	geofencePort           port.GeofencePort
-	azureBlobStorage       storage.Storage
+	repository                repository.Repository
+	userGeofenceRepository    repository.UserGeofenceRepository
+	userBlockRepository       repository.UserBlockRepository
+	notificationRepository    repository.NotificationPreferenceRepository
+	accountDeletionRepository repository.AccountDeletionRepository
+	dataExportRepository      repository.DataExportRepository
+	geofencePort              port.GeofencePort
+	itemPort                  port.ItemPort
+	conversationPort          port.ConversationPort
+	reviewPort                port.ReviewPort
+	azureBlobStorage          storage.Storage
+	redis                     user_redis.RedisClient
+	accountDeletionWorker     AccountDeletionWorker
+	dataExportWorker          DataExportWorker
+	geofenceCfg               GeofenceConfig
 }
 
-func NewUserManager(repository repository.Repository, userGeofenceRepository repository.UserGeofenceRepository, geofencePort port.GeofencePort, azureBlobStorage storage.Storage) UserManager {
+func NewUserManager(repository repository.Repository, userGeofenceRepository repository.UserGeofenceRepository, userBlockRepository repository.UserBlockRepository, notificationRepository repository.NotificationPreferenceRepository, accountDeletionRepository repository.AccountDeletionRepository, dataExportRepository repository.DataExportRepository, geofencePort port.GeofencePort, itemPort port.ItemPort, conversationPort port.ConversationPort, reviewPort port.ReviewPort, azureBlobStorage storage.Storage, redis user_redis.RedisClient, accountDeletionWorker AccountDeletionWorker, dataExportWorker DataExportWorker, geofenceCfg GeofenceConfig) UserManager {
 	return &userManager{
 		repository,
 		userGeofenceRepository,
+		userBlockRepository,
+		notificationRepository,
+		accountDeletionRepository,
+		dataExportRepository,
 		geofencePort,
+		itemPort,
+		conversationPort,
+		reviewPort,
 		azureBlobStorage,
+		redis,
+		accountDeletionWorker,
+		dataExportWorker,
+		geofenceCfg,
 	}
 }
 
@@ -50,6 +98,9 @@ func (m *userManager) GetUser(ctx context.Context, userID uuid.UUID) (*User, err
 		Username: user.Username,
 		Email:    user.Email,
 		Image:    url,
+		Phone:    user.Phone,
+		Verified: user.EmailVerified || user.PhoneVerifiedAt != nil,
+		Locale:   user.Locale,
 		Geofence: Geofence{
 			ID:   geofence.ID,
 			Name: geofence.Name,
@@ -58,9 +109,12 @@ func (m *userManager) GetUser(ctx context.Context, userID uuid.UUID) (*User, err
 }
 
 func (m *userManager) Update(ctx context.Context, req UpdateUserRequest) (*User, error) {
-	if req.Name == nil && req.Image == nil {
+	if req.Name == nil && req.Image == nil && req.Locale == nil {
 		return nil, fmt.Errorf("empty update request is not allowed")
 	}
+	if req.Locale != nil && *req.Locale != "" && !req.Locale.IsValid() {
+		return nil, ErrInvalidLocale
+	}
 	user, err := m.repository.GetUser(ctx, req.UserID)
 	if err != nil {
 		return nil, err
@@ -68,12 +122,34 @@ func (m *userManager) Update(ctx context.Context, req UpdateUserRequest) (*User,
 	if req.Name != nil {
 		user.Username = *req.Name
 	}
-	if req.Image != nil {
-		user.Image = req.Image
+	// clients send the current image back with other changes, it has been accepted already
+	var upload *string
+	if req.Image != nil && (user.Image == nil || *req.Image != *user.Image) {
+		image, err := m.acceptProfileImage(ctx, req.UserID, *req.Image)
+		if err != nil {
+			return nil, err
+		}
+		upload = req.Image
+		user.Image = &image
 	}
 	if err := m.repository.UpdateUser(ctx, user); err != nil {
 		return nil, err
 	}
+	if req.Locale != nil {
+		if err := m.repository.SetLocale(ctx, req.UserID, *req.Locale); err != nil {
+			return nil, err
+		}
+		user.Locale = *req.Locale
+	}
+	if upload != nil {
+		if err := m.redis.DeletePendingUpload(ctx, req.UserID, *upload); err != nil {
+			log.Printf("failed to delete pending upload %s: %v\n", *upload, err)
+		}
+		// the upload link of the blob is valid for a day, the copy is what the profile points to
+		if err := m.azureBlobStorage.DeleteObjects(ctx, *upload, storage.ContainerProfiles); err != nil {
+			log.Printf("failed to delete upload %s: %v\n", *upload, err)
+		}
+	}
 	userGeofence, err := m.userGeofenceRepository.GetUserGeofence(ctx, req.UserID)
 	if err != nil {
 		return nil, err
@@ -88,6 +164,9 @@ func (m *userManager) Update(ctx context.Context, req UpdateUserRequest) (*User,
 		Username: user.Username,
 		Email:    user.Email,
 		Image:    user.Image,
+		Phone:    user.Phone,
+		Verified: user.EmailVerified || user.PhoneVerifiedAt != nil,
+		Locale:   user.Locale,
 		Geofence: Geofence{
 			ID:   geofence.ID,
 			Name: geofence.Name,
@@ -101,8 +180,144 @@ func (m *userManager) GetPresignedUrl(ctx context.Context, req GetPresignedUrlRe
 	if err != nil {
 		return nil, err
 	}
+	if err := m.redis.SetPendingUpload(ctx, req.UserID, blob, pendingUploadTTL); err != nil {
+		return nil, err
+	}
 	return &GetPresignedUrlResponse{
 		Url:       url,
 		ImageName: blob,
 	}, nil
 }
+
+// acceptProfileImage accepts only an image name handed out to the user by GetPresignedUrl, once the upload is done
+// and the object is an image that is not too large. The image is copied to a name the user has no upload link for,
+// the copy is what gets checked, so the upload can not be replaced after the check. The name of the copy is returned.
+func (m *userManager) acceptProfileImage(ctx context.Context, userID uuid.UUID, upload string) (string, error) {
+	pending, err := m.redis.IsPendingUpload(ctx, userID, upload)
+	if err != nil {
+		return "", err
+	}
+	if !pending {
+		return "", ErrUnknownUpload
+	}
+	info, err := m.azureBlobStorage.StatObject(ctx, upload, storage.ContainerProfiles)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return "", ErrUploadNotFound
+	} else if err != nil {
+		return "", err
+	}
+	if info.Size > maxProfileImageSize {
+		return "", ErrImageTooLarge
+	}
+
+	body, err := m.azureBlobStorage.GetObject(ctx, upload, storage.ContainerProfiles)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+	// the upload may have grown since the stat
+	content, err := io.ReadAll(io.LimitReader(body, maxProfileImageSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(content) > maxProfileImageSize {
+		return "", ErrImageTooLarge
+	}
+	// the content type of the upload is set by the client, the content tells what it is
+	contentType := sniffImageType(content)
+	if !profileImageContentTypes[contentType] {
+		return "", ErrInvalidImageType
+	}
+
+	image := fmt.Sprintf("%s/%s", userID, uuid.New())
+	if err := m.azureBlobStorage.PutObject(ctx, image, storage.ContainerProfiles, bytes.NewReader(content), int64(len(content)), contentType); err != nil {
+		return "", err
+	}
+	return image, nil
+}
+
+// sniffImageType detects the content type from the first bytes, http.DetectContentType doesn't know heic
+func sniffImageType(content []byte) string {
+	if len(content) >= 12 && string(content[4:8]) == "ftyp" {
+		switch string(content[8:12]) {
+		case "heic", "heix", "hevc", "hevx", "mif1", "msf1":
+			return "image/heic"
+		}
+	}
+	return http.DetectContentType(content)
+}
+
+func (m *userManager) SetRole(ctx context.Context, req SetRoleRequest) error {
+	if !req.Role.IsValid() {
+		return ErrInvalidRole
+	}
+	return m.repository.SetRole(ctx, req.UserID, req.Role)
+}
+
+func (m *userManager) DeleteUser(ctx context.Context, userID uuid.UUID) (*DeleteUserResponse, error) {
+	deletion, err := m.accountDeletionRepository.CreateOrGet(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	m.accountDeletionWorker.Notify()
+	return &DeleteUserResponse{
+		JobID:  deletion.ID,
+		Status: string(deletion.Status),
+	}, nil
+}
+
+func (m *userManager) RequestDataExport(ctx context.Context, userID uuid.UUID) (*DataExport, error) {
+	// an export that is still being built is returned instead of starting another one
+	export, err := m.dataExportRepository.GetActiveExport(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		export = &repository.DataExport{
+			UserID:    userID,
+			Status:    repository.DataExportStatusPending,
+			NextRunAt: time.Now(),
+		}
+		if err := m.dataExportRepository.Create(ctx, export); err != nil {
+			return nil, err
+		}
+		m.dataExportWorker.Notify()
+	} else if err != nil {
+		return nil, err
+	}
+	return m.toDataExport(ctx, export)
+}
+
+func (m *userManager) GetDataExport(ctx context.Context, req GetDataExportRequest) (*DataExport, error) {
+	export, err := m.dataExportRepository.GetExport(ctx, req.ExportID, req.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDataExportNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return m.toDataExport(ctx, export)
+}
+
+func (m *userManager) toDataExport(ctx context.Context, export *repository.DataExport) (*DataExport, error) {
+	resp := &DataExport{
+		ID:        export.ID,
+		Status:    string(export.Status),
+		CreatedAt: export.CreatedAt,
+		ExpiresAt: export.ExpiresAt,
+	}
+	if export.Status != repository.DataExportStatusCompleted {
+		return resp, nil
+	}
+	validFor := time.Until(*export.ExpiresAt)
+	if validFor <= 0 {
+		// the archive is removed by the worker shortly
+		resp.Status = string(repository.DataExportStatusExpired)
+		return resp, nil
+	}
+	if validFor > maxDownloadUrlExpiry {
+		validFor = maxDownloadUrlExpiry
+	}
+	url, err := m.azureBlobStorage.GeneratePresignedUrlToRead(ctx, export.ObjectName, storage.ContainerExports, validFor)
+	if err != nil {
+		return nil, err
+	}
+	resp.Url = &url
+	return resp, nil
+}
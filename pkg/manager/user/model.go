@@ -2,6 +2,9 @@ package user_manager
 
 import (
 	"context"
+	"fmt"
+	"ketalk-api/common"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,6 +14,10 @@ type User struct {
 	Username string
 	Email    string
 	Image    *string
+	Phone    *string
+	Verified bool
+	// Locale is empty when the user did not pick one
+	Locale   common.Locale
 	Geofence Geofence
 }
 
@@ -19,10 +26,106 @@ type Geofence struct {
 	Name string
 }
 
+type PublicProfile struct {
+	ID          uuid.UUID
+	Username    string
+	Image       *string
+	Verified    bool
+	Geofence    Geofence
+	MemberSince time.Time
+	Stats       UserStats
+	Rating      Rating
+}
+
+type Rating struct {
+	Count int64
+	// Average is nil until the user is reviewed
+	Average *float64
+}
+
+type UserStats struct {
+	ActiveCount int64
+	SoldCount   int64
+	// AverageResponseTime and ResponseRate are nil until the user received a conversation
+	AverageResponseTime *time.Duration
+	// ResponseRate is the share of received conversations the user answered, between 0 and 1
+	ResponseRate *float64
+	LastActiveAt *time.Time
+}
+
+type BlockUserRequest struct {
+	UserID        uuid.UUID
+	BlockedUserID uuid.UUID
+}
+
+type BlockedUser struct {
+	ID        uuid.UUID
+	Username  string
+	Image     *string
+	BlockedAt time.Time
+}
+
+type VerifyGeofenceRequest struct {
+	UserID   uuid.UUID
+	Location common.Location
+}
+
+type GeofenceVerification struct {
+	Geofence   Geofence
+	VerifiedAt time.Time
+	// Changed is set when the user was moved to another geofence
+	Changed bool
+	// NextChangeAt is when the user can switch again, nil when there is no cooldown
+	NextChangeAt *time.Time
+}
+
+type GeofenceHistoryEntry struct {
+	Geofence Geofence
+	From     time.Time
+	// Until is nil for the current geofence
+	Until      *time.Time
+	VerifiedAt *time.Time
+}
+
+type NotificationPreferences struct {
+	Channels NotificationChannels
+	Events   NotificationEvents
+	// QuietHours is nil when the user did not set any
+	QuietHours *QuietHours
+}
+
+type NotificationChannels struct {
+	Push  bool
+	Email bool
+	InApp bool
+}
+
+type NotificationEvents struct {
+	NewMessage     bool
+	PriceDrop      bool
+	ItemSold       bool
+	ReviewReceived bool
+}
+
+// QuietHours hold back push and email notifications, Start and End are written as 15:04 in Timezone
+// and wrap past midnight when End is before Start
+type QuietHours struct {
+	Start    string
+	End      string
+	Timezone string
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	UserID uuid.UUID
+	NotificationPreferences
+}
+
 type UpdateUserRequest struct {
 	UserID uuid.UUID
 	Name   *string
 	Image  *string
+	// Locale is cleared with an empty string
+	Locale *common.Locale
 }
 
 type GetPresignedUrlRequest struct {
@@ -34,8 +137,124 @@ type GetPresignedUrlResponse struct {
 	ImageName string
 }
 
+type SetRoleRequest struct {
+	UserID uuid.UUID
+	Role   common.Role
+}
+
+type DeleteUserResponse struct {
+	JobID  uuid.UUID
+	Status string
+}
+
+type GetDataExportRequest struct {
+	UserID   uuid.UUID
+	ExportID uuid.UUID
+}
+
+type DataExport struct {
+	ID     uuid.UUID
+	Status string
+	// Url is the download link, it is only set once the export is completed
+	Url       *string
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+}
+
+var ErrInvalidRole = fmt.Errorf("%w: invalid role", common.ErrInvalidInput)
+var ErrDataExportNotFound = fmt.Errorf("%w: data export not found", common.ErrNotFound)
+var ErrUserNotFound = fmt.Errorf("%w: user not found", common.ErrNotFound)
+var ErrCannotBlockSelf = fmt.Errorf("%w: cannot block yourself", common.ErrInvalidInput)
+var ErrBlockNotFound = fmt.Errorf("%w: user is not blocked", common.ErrNotFound)
+var ErrUnknownUpload = fmt.Errorf("%w: image was not uploaded through a presigned url of the user", common.ErrForbidden)
+var ErrUploadNotFound = fmt.Errorf("%w: uploaded image not found", common.ErrNotFound)
+var ErrInvalidImageType = fmt.Errorf("%w: uploaded file is not a supported image", common.ErrInvalidInput)
+var ErrImageTooLarge = fmt.Errorf("%w: uploaded image must be at most %d bytes", common.ErrInvalidInput, maxProfileImageSize)
+var ErrInvalidLocale = fmt.Errorf("%w: unsupported locale", common.ErrInvalidInput)
+var ErrInvalidQuietHours = fmt.Errorf("%w: invalid quiet hours", common.ErrInvalidInput)
+var ErrInvalidTimezone = fmt.Errorf("%w: invalid timezone", common.ErrInvalidInput)
+var ErrUnverifiedAccountExists = fmt.Errorf("%w: an account with this email exists but its email is not verified, sign in with its password and link the provider", common.ErrConflict)
+var ErrGeofenceChangeCooldown = fmt.Errorf("%w: neighborhood was changed recently", common.ErrTooManyAttempts)
+
+func init() {
+	common.RegisterErrorMessages(ErrInvalidLocale, map[common.Locale]string{
+		common.LocaleUz: "til qo'llab-quvvatlanmaydi",
+		common.LocaleRu: "язык не поддерживается",
+	})
+	common.RegisterErrorMessages(ErrInvalidRole, map[common.Locale]string{
+		common.LocaleUz: "rol noto'g'ri",
+		common.LocaleRu: "неверная роль",
+	})
+	common.RegisterErrorMessages(ErrDataExportNotFound, map[common.Locale]string{
+		common.LocaleUz: "ma'lumotlar eksporti topilmadi",
+		common.LocaleRu: "экспорт данных не найден",
+	})
+	common.RegisterErrorMessages(ErrUserNotFound, map[common.Locale]string{
+		common.LocaleUz: "foydalanuvchi topilmadi",
+		common.LocaleRu: "пользователь не найден",
+	})
+	common.RegisterErrorMessages(ErrCannotBlockSelf, map[common.Locale]string{
+		common.LocaleUz: "o'zingizni bloklay olmaysiz",
+		common.LocaleRu: "нельзя заблокировать себя",
+	})
+	common.RegisterErrorMessages(ErrBlockNotFound, map[common.Locale]string{
+		common.LocaleUz: "foydalanuvchi bloklanmagan",
+		common.LocaleRu: "пользователь не заблокирован",
+	})
+	common.RegisterErrorMessages(ErrUnknownUpload, map[common.Locale]string{
+		common.LocaleUz: "rasm foydalanuvchining yuklash havolasi orqali yuklanmagan",
+		common.LocaleRu: "изображение загружено не по ссылке пользователя",
+	})
+	common.RegisterErrorMessages(ErrUploadNotFound, map[common.Locale]string{
+		common.LocaleUz: "yuklangan rasm topilmadi",
+		common.LocaleRu: "загруженное изображение не найдено",
+	})
+	common.RegisterErrorMessages(ErrInvalidImageType, map[common.Locale]string{
+		common.LocaleUz: "yuklangan fayl qo'llab-quvvatlanadigan rasm emas",
+		common.LocaleRu: "загруженный файл не является поддерживаемым изображением",
+	})
+	common.RegisterErrorMessages(ErrImageTooLarge, map[common.Locale]string{
+		common.LocaleUz: fmt.Sprintf("yuklangan rasm ko'pi bilan %d bayt bo'lishi kerak", maxProfileImageSize),
+		common.LocaleRu: fmt.Sprintf("загруженное изображение должно быть не больше %d байт", maxProfileImageSize),
+	})
+	common.RegisterErrorMessages(ErrInvalidQuietHours, map[common.Locale]string{
+		common.LocaleUz: "tinch soatlar noto'g'ri",
+		common.LocaleRu: "неверные тихие часы",
+	})
+	common.RegisterErrorMessages(ErrInvalidTimezone, map[common.Locale]string{
+		common.LocaleUz: "vaqt mintaqasi noto'g'ri",
+		common.LocaleRu: "неверный часовой пояс",
+	})
+	common.RegisterErrorMessages(ErrUnverifiedAccountExists, map[common.Locale]string{
+		common.LocaleUz: "bu email bilan tasdiqlanmagan hisob mavjud, uning paroli bilan kiring va provayderni bog'lang",
+		common.LocaleRu: "аккаунт с этим email существует, но email не подтверждён, войдите по паролю и привяжите провайдера",
+	})
+	common.RegisterErrorMessages(ErrGeofenceChangeCooldown, map[common.Locale]string{
+		common.LocaleUz: "hudud yaqinda o'zgartirilgan",
+		common.LocaleRu: "район недавно менялся",
+	})
+}
+
 type UserManager interface {
 	GetUser(ctx context.Context, userID uuid.UUID) (*User, error)
+	// GetPublicProfile returns what other users can see of the user, with the stats cached for a while
+	GetPublicProfile(ctx context.Context, userID uuid.UUID) (*PublicProfile, error)
 	Update(ctx context.Context, req UpdateUserRequest) (*User, error)
 	GetPresignedUrl(ctx context.Context, req GetPresignedUrlRequest) (*GetPresignedUrlResponse, error)
+	SetRole(ctx context.Context, req SetRoleRequest) error
+	// DeleteUser schedules the deletion of the account, the work is done by the AccountDeletionWorker
+	DeleteUser(ctx context.Context, userID uuid.UUID) (*DeleteUserResponse, error)
+	// RequestDataExport schedules an archive of everything stored about the user, the work is done by the DataExportWorker
+	RequestDataExport(ctx context.Context, userID uuid.UUID) (*DataExport, error)
+	GetDataExport(ctx context.Context, req GetDataExportRequest) (*DataExport, error)
+	BlockUser(ctx context.Context, req BlockUserRequest) error
+	UnblockUser(ctx context.Context, req BlockUserRequest) error
+	GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]BlockedUser, error)
+	// VerifyGeofence records that the user is in its geofence, or moves the user to the geofence of
+	// the location once the cooldown since the last change is over
+	VerifyGeofence(ctx context.Context, req VerifyGeofenceRequest) (*GeofenceVerification, error)
+	GetGeofenceHistory(ctx context.Context, userID uuid.UUID) ([]GeofenceHistoryEntry, error)
+	GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*NotificationPreferences, error)
+	// UpdateNotificationPreferences replaces the preferences of the user
+	UpdateNotificationPreferences(ctx context.Context, req UpdateNotificationPreferencesRequest) (*NotificationPreferences, error)
 }
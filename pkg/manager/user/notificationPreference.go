@@ -0,0 +1,138 @@
+package user_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"ketalk-api/pkg/manager/port"
+	"ketalk-api/pkg/manager/user/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// quietHoursLayout is how the start and the end of quiet hours are written
+const quietHoursLayout = "15:04"
+
+func (m *userManager) GetNotificationPreferences(ctx context.Context, userID uuid.UUID) (*NotificationPreferences, error) {
+	preference, err := getNotificationPreference(ctx, m.notificationRepository, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toNotificationPreferences(preference), nil
+}
+
+func (m *userManager) UpdateNotificationPreferences(ctx context.Context, req UpdateNotificationPreferencesRequest) (*NotificationPreferences, error) {
+	preference := &repository.NotificationPreference{
+		UserID:         req.UserID,
+		Push:           req.Channels.Push,
+		Email:          req.Channels.Email,
+		InApp:          req.Channels.InApp,
+		NewMessage:     req.Events.NewMessage,
+		PriceDrop:      req.Events.PriceDrop,
+		ItemSold:       req.Events.ItemSold,
+		ReviewReceived: req.Events.ReviewReceived,
+	}
+	if req.QuietHours != nil {
+		start, err := parseQuietHoursTime(req.QuietHours.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseQuietHoursTime(req.QuietHours.End)
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, ErrInvalidQuietHours
+		}
+		if _, err := time.LoadLocation(req.QuietHours.Timezone); err != nil || req.QuietHours.Timezone == "" {
+			return nil, ErrInvalidTimezone
+		}
+		preference.QuietHoursStart = &start
+		preference.QuietHoursEnd = &end
+		preference.QuietHoursTimezone = req.QuietHours.Timezone
+	}
+	if err := m.notificationRepository.Save(ctx, preference); err != nil {
+		return nil, err
+	}
+	return toNotificationPreferences(preference), nil
+}
+
+// getNotificationPreference returns the stored preference of the user or the defaults
+func getNotificationPreference(ctx context.Context, notificationRepository repository.NotificationPreferenceRepository, userID uuid.UUID) (*repository.NotificationPreference, error) {
+	preference, err := notificationRepository.GetPreference(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &repository.NotificationPreference{
+			UserID:         userID,
+			Push:           true,
+			Email:          true,
+			InApp:          true,
+			NewMessage:     true,
+			PriceDrop:      true,
+			ItemSold:       true,
+			ReviewReceived: true,
+		}, nil
+	}
+	return preference, err
+}
+
+func parseQuietHoursTime(value string) (int, error) {
+	t, err := time.Parse(quietHoursLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidQuietHours, value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatQuietHoursTime(minutes int) string {
+	return time.Date(0, 1, 1, minutes/60, minutes%60, 0, 0, time.UTC).Format(quietHoursLayout)
+}
+
+func toNotificationPreferences(preference *repository.NotificationPreference) *NotificationPreferences {
+	resp := &NotificationPreferences{
+		Channels: NotificationChannels{
+			Push:  preference.Push,
+			Email: preference.Email,
+			InApp: preference.InApp,
+		},
+		Events: NotificationEvents{
+			NewMessage:     preference.NewMessage,
+			PriceDrop:      preference.PriceDrop,
+			ItemSold:       preference.ItemSold,
+			ReviewReceived: preference.ReviewReceived,
+		},
+	}
+	if preference.QuietHoursStart != nil && preference.QuietHoursEnd != nil {
+		resp.QuietHours = &QuietHours{
+			Start:    formatQuietHoursTime(*preference.QuietHoursStart),
+			End:      formatQuietHoursTime(*preference.QuietHoursEnd),
+			Timezone: preference.QuietHoursTimezone,
+		}
+	}
+	return resp
+}
+
+func toPortNotificationPreferences(preference *repository.NotificationPreference) (*port.NotificationPreferences, error) {
+	resp := &port.NotificationPreferences{
+		Push:           preference.Push,
+		Email:          preference.Email,
+		InApp:          preference.InApp,
+		NewMessage:     preference.NewMessage,
+		PriceDrop:      preference.PriceDrop,
+		ItemSold:       preference.ItemSold,
+		ReviewReceived: preference.ReviewReceived,
+	}
+	if preference.QuietHoursStart != nil && preference.QuietHoursEnd != nil {
+		location, err := time.LoadLocation(preference.QuietHoursTimezone)
+		if err != nil {
+			return nil, err
+		}
+		resp.QuietHours = &port.QuietHours{
+			Start:    *preference.QuietHoursStart,
+			End:      *preference.QuietHoursEnd,
+			Location: location,
+		}
+	}
+	return resp, nil
+}
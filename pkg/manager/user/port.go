@@ -2,8 +2,10 @@ package user_manager
 
 import (
 	"context"
+	"errors"
 	"ketalk-api/pkg/manager/port"
 	"ketalk-api/pkg/manager/user/repository"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -12,12 +14,18 @@ import (
 type userPort struct {
 	userRepository         repository.Repository
 	userGeofenceRepository repository.UserGeofenceRepository
+	userIdentityRepository repository.UserIdentityRepository
+	userBlockRepository    repository.UserBlockRepository
+	notificationRepository repository.NotificationPreferenceRepository
 }
 
-func NewUserPort(userRepository repository.Repository, userGeofenceRepository repository.UserGeofenceRepository) port.UserPort {
+func NewUserPort(userRepository repository.Repository, userGeofenceRepository repository.UserGeofenceRepository, userIdentityRepository repository.UserIdentityRepository, userBlockRepository repository.UserBlockRepository, notificationRepository repository.NotificationPreferenceRepository) port.UserPort {
 	return &userPort{
 		userRepository,
 		userGeofenceRepository,
+		userIdentityRepository,
+		userBlockRepository,
+		notificationRepository,
 	}
 }
 
@@ -25,12 +33,24 @@ func (p *userPort) CreateOrGetUser(ctx context.Context, req port.CreateOrGetUser
 	// get user by email
 	user, err := p.userRepository.GetUserByEmail(ctx, req.Email)
 	if err == nil {
+		// whoever signed up with the email first may not own it, adopting the account
+		// would leave their password working on the account of the real owner
+		if req.EmailVerified && !user.EmailVerified {
+			return nil, ErrUnverifiedAccountExists
+		}
 		return &port.User{
-			ID:       user.ID,
-			Username: user.Username,
-			Email:    user.Email,
-			Password: user.Password,
-			Image:    user.Image,
+			ID:             user.ID,
+			Username:       user.Username,
+			Email:          user.Email,
+			Password:       user.Password,
+			Image:          user.Image,
+			Verified:       isVerified(user),
+			EmailVerified:  user.EmailVerified,
+			Role:           user.Role,
+			Phone:          user.Phone,
+			SuspendedAt:    user.SuspendedAt,
+			SuspendedUntil: user.SuspendedUntil,
+			Locale:         user.Locale,
 		}, nil
 	}
 	if err != gorm.ErrRecordNotFound {
@@ -39,10 +59,11 @@ func (p *userPort) CreateOrGetUser(ctx context.Context, req port.CreateOrGetUser
 
 	// else create user
 	user = &repository.User{
-		Username: req.Username,
-		Email:    req.Email,
-		Password: req.Password,
-		Image:    req.Image,
+		Username:      req.Username,
+		Email:         req.Email,
+		Password:      req.Password,
+		Image:         req.Image,
+		EmailVerified: req.EmailVerified,
 	}
 	if err = p.userRepository.CreateUser(ctx, user); err != nil {
 		return nil, err
@@ -57,11 +78,18 @@ func (p *userPort) CreateOrGetUser(ctx context.Context, req port.CreateOrGetUser
 	}
 
 	return &port.User{
-		ID:         user.ID,
-		Username:   user.Username,
-		Email:      user.Email,
-		Image:      user.Image,
-		GeofenceID: userGeofence.GeofenceID,
+		ID:             user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		Image:          user.Image,
+		GeofenceID:     userGeofence.GeofenceID,
+		Verified:       isVerified(user),
+		EmailVerified:  user.EmailVerified,
+		Role:           user.Role,
+		Phone:          user.Phone,
+		SuspendedAt:    user.SuspendedAt,
+		SuspendedUntil: user.SuspendedUntil,
+		Locale:         user.Locale,
 	}, nil
 }
 
@@ -75,10 +103,220 @@ func (p *userPort) GetUser(ctx context.Context, userId uuid.UUID) (*port.User, e
 		return nil, err
 	}
 	return &port.User{
-		ID:         user.ID,
-		Username:   user.Username,
-		Email:      user.Email,
-		Image:      user.Image,
-		GeofenceID: userGeofence.GeofenceID,
+		ID:             user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		Password:       user.Password,
+		Image:          user.Image,
+		GeofenceID:     userGeofence.GeofenceID,
+		Verified:       isVerified(user),
+		EmailVerified:  user.EmailVerified,
+		Role:           user.Role,
+		Phone:          user.Phone,
+		SuspendedAt:    user.SuspendedAt,
+		SuspendedUntil: user.SuspendedUntil,
+		Locale:         user.Locale,
 	}, nil
 }
+
+func (p *userPort) GetUserByEmail(ctx context.Context, email string) (*port.User, error) {
+	user, err := p.userRepository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return &port.User{
+		ID:             user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		Password:       user.Password,
+		Image:          user.Image,
+		Verified:       isVerified(user),
+		EmailVerified:  user.EmailVerified,
+		Role:           user.Role,
+		Phone:          user.Phone,
+		SuspendedAt:    user.SuspendedAt,
+		SuspendedUntil: user.SuspendedUntil,
+		Locale:         user.Locale,
+	}, nil
+}
+
+func (p *userPort) UpdatePassword(ctx context.Context, userId uuid.UUID, password string) error {
+	return p.userRepository.UpdatePassword(ctx, userId, password)
+}
+
+func (p *userPort) SetEmailVerified(ctx context.Context, userId uuid.UUID) error {
+	return p.userRepository.SetEmailVerified(ctx, userId)
+}
+
+func (p *userPort) CreateUserWithPhone(ctx context.Context, req port.CreateUserWithPhoneRequest) (*port.User, error) {
+	now := time.Now()
+	user := &repository.User{
+		Username:        req.Username,
+		Phone:           &req.Phone,
+		PhoneVerifiedAt: &now,
+	}
+	if err := p.userRepository.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+
+	userGeofence := &repository.UserGeofence{
+		UserID:     user.ID,
+		GeofenceID: req.GeofenceID,
+	}
+	if err := p.userGeofenceRepository.Create(ctx, userGeofence); err != nil {
+		return nil, err
+	}
+
+	return &port.User{
+		ID:             user.ID,
+		Username:       user.Username,
+		Image:          user.Image,
+		GeofenceID:     userGeofence.GeofenceID,
+		Verified:       isVerified(user),
+		EmailVerified:  user.EmailVerified,
+		Role:           user.Role,
+		Phone:          user.Phone,
+		SuspendedAt:    user.SuspendedAt,
+		SuspendedUntil: user.SuspendedUntil,
+		Locale:         user.Locale,
+	}, nil
+}
+
+func (p *userPort) GetUserByPhone(ctx context.Context, phone string) (*port.User, error) {
+	user, err := p.userRepository.GetUserByPhone(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	return &port.User{
+		ID:             user.ID,
+		Username:       user.Username,
+		Email:          user.Email,
+		Image:          user.Image,
+		Verified:       isVerified(user),
+		EmailVerified:  user.EmailVerified,
+		Role:           user.Role,
+		Phone:          user.Phone,
+		SuspendedAt:    user.SuspendedAt,
+		SuspendedUntil: user.SuspendedUntil,
+		Locale:         user.Locale,
+	}, nil
+}
+
+func (p *userPort) SetPhone(ctx context.Context, userId uuid.UUID, phone string) error {
+	return p.userRepository.SetPhone(ctx, userId, phone)
+}
+
+// isVerified reports whether the user proved to own either the email or the phone number
+func isVerified(user *repository.User) bool {
+	return user.EmailVerified || user.PhoneVerifiedAt != nil
+}
+
+func (p *userPort) GetIdentity(ctx context.Context, provider string, externalID string) (*port.Identity, error) {
+	identity, err := p.userIdentityRepository.GetIdentity(ctx, provider, externalID)
+	if err != nil {
+		return nil, err
+	}
+	return toPortIdentity(identity), nil
+}
+
+func (p *userPort) GetUserIdentities(ctx context.Context, userId uuid.UUID) ([]port.Identity, error) {
+	identities, err := p.userIdentityRepository.GetUserIdentities(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]port.Identity, len(identities))
+	for i := range identities {
+		resp[i] = *toPortIdentity(&identities[i])
+	}
+	return resp, nil
+}
+
+func (p *userPort) LinkIdentity(ctx context.Context, identity port.Identity) error {
+	err := p.userIdentityRepository.Create(ctx, &repository.UserIdentity{
+		Provider:   identity.Provider,
+		ExternalID: identity.ExternalID,
+		UserID:     identity.UserID,
+		Email:      identity.Email,
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return port.ErrIdentityAlreadyLinked
+	}
+	return err
+}
+
+func (p *userPort) UnlinkIdentity(ctx context.Context, userId uuid.UUID, provider string) error {
+	return p.userIdentityRepository.Delete(ctx, userId, provider, func(user *repository.User, identities []repository.UserIdentity) error {
+		linked := false
+		for _, identity := range identities {
+			if identity.Provider == provider {
+				linked = true
+			}
+		}
+		if !linked {
+			return gorm.ErrRecordNotFound
+		}
+		loginMethods := len(identities)
+		// a password on an unverified email may belong to whoever registered the email first
+		if user.Password != nil && user.EmailVerified {
+			loginMethods++
+		}
+		if user.Phone != nil {
+			loginMethods++
+		}
+		if loginMethods <= 1 {
+			return port.ErrLastLoginMethod
+		}
+		return nil
+	})
+}
+
+func toPortIdentity(identity *repository.UserIdentity) *port.Identity {
+	return &port.Identity{
+		UserID:     identity.UserID,
+		Provider:   identity.Provider,
+		ExternalID: identity.ExternalID,
+		Email:      identity.Email,
+		LinkedAt:   identity.CreatedAt,
+	}
+}
+
+func (p *userPort) GetBlockedUserIDs(ctx context.Context, userId uuid.UUID) ([]uuid.UUID, error) {
+	blocks, err := p.userBlockRepository.GetBlocks(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(blocks))
+	for i, block := range blocks {
+		ids[i] = block.BlockedID
+	}
+	return ids, nil
+}
+
+func (p *userPort) GetBlock(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (*port.Block, error) {
+	block, err := p.userBlockRepository.GetBlock(ctx, blockerId, blockedId)
+	if err != nil {
+		return nil, err
+	}
+	return &port.Block{
+		BlockerID: block.BlockerID,
+		BlockedID: block.BlockedID,
+		BlockedAt: block.CreatedAt,
+	}, nil
+}
+
+func (p *userPort) HasBlockBetween(ctx context.Context, userId uuid.UUID, otherUserId uuid.UUID) (bool, error) {
+	return p.userBlockRepository.HasBlockBetween(ctx, userId, otherUserId)
+}
+
+func (p *userPort) SuspendUser(ctx context.Context, userId uuid.UUID, until *time.Time) error {
+	now := time.Now()
+	return p.userRepository.SetSuspension(ctx, userId, &now, until)
+}
+
+func (p *userPort) GetNotificationPreferences(ctx context.Context, userId uuid.UUID) (*port.NotificationPreferences, error) {
+	preference, err := getNotificationPreference(ctx, p.notificationRepository, userId)
+	if err != nil {
+		return nil, err
+	}
+	return toPortNotificationPreferences(preference)
+}
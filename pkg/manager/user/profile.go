@@ -0,0 +1,113 @@
+package user_manager
+
+import (
+	"context"
+	"errors"
+	user_redis "ketalk-api/pkg/manager/user/redis"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// userStatsCacheTTL is how long the stats of a profile can lag behind
+const userStatsCacheTTL = 10 * time.Minute
+
+func (m *userManager) GetPublicProfile(ctx context.Context, userID uuid.UUID) (*PublicProfile, error) {
+	user, err := m.repository.GetUser(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var url *string
+	if user.Image != nil {
+		image := m.azureBlobStorage.GetUserImage(*user.Image)
+		url = &image
+	}
+	userGeofence, err := m.userGeofenceRepository.GetUserGeofence(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	geofence, err := m.geofencePort.GetGeofenceById(ctx, userGeofence.GeofenceID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := m.getUserStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	// the rating is not cached, a new review shows up right away
+	rating, err := m.reviewPort.GetRatingSummary(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicProfile{
+		ID:       user.ID,
+		Username: user.Username,
+		Image:    url,
+		Verified: user.EmailVerified || user.PhoneVerifiedAt != nil,
+		Geofence: Geofence{
+			ID:   geofence.ID,
+			Name: geofence.Name,
+		},
+		MemberSince: user.CreatedAt,
+		Stats:       *stats,
+		Rating: Rating{
+			Count:   rating.Count,
+			Average: rating.Average,
+		},
+	}, nil
+}
+
+// getUserStats serves the stats from the cache, a broken cache only makes the profile slower
+func (m *userManager) getUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	cached, err := m.redis.GetUserStats(ctx, userID)
+	if err == nil {
+		return toUserStats(cached), nil
+	} else if !errors.Is(err, user_redis.ErrUserStatsNotFound) {
+		log.Printf("failed to get cached stats of user %s: %v\n", userID, err)
+	}
+
+	itemStats, err := m.itemPort.GetUserItemStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	responseStats, err := m.conversationPort.GetUserResponseStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats := user_redis.UserStats{
+		ActiveCount:         itemStats.ActiveCount,
+		SoldCount:           itemStats.SoldCount,
+		AverageResponseTime: responseStats.AverageResponseTime,
+		LastActiveAt:        latest(itemStats.LastItemUpdateAt, responseStats.LastMessagedAt),
+	}
+	if responseStats.ReceivedConversations > 0 {
+		rate := float64(responseStats.RespondedConversations) / float64(responseStats.ReceivedConversations)
+		stats.ResponseRate = &rate
+	}
+	if err := m.redis.SetUserStats(ctx, userID, stats, userStatsCacheTTL); err != nil {
+		log.Printf("failed to cache stats of user %s: %v\n", userID, err)
+	}
+	return toUserStats(&stats), nil
+}
+
+func toUserStats(stats *user_redis.UserStats) *UserStats {
+	return &UserStats{
+		ActiveCount:         stats.ActiveCount,
+		SoldCount:           stats.SoldCount,
+		AverageResponseTime: stats.AverageResponseTime,
+		ResponseRate:        stats.ResponseRate,
+		LastActiveAt:        stats.LastActiveAt,
+	}
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
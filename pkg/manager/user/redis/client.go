@@ -0,0 +1,28 @@
+package user_redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+type RedisClient interface {
+	SetUserStats(ctx context.Context, userID uuid.UUID, stats UserStats, ttl time.Duration) error
+	GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error)
+	// SetPendingUpload remembers an object name handed out to the user for an upload
+	SetPendingUpload(ctx context.Context, userID uuid.UUID, name string, ttl time.Duration) error
+	IsPendingUpload(ctx context.Context, userID uuid.UUID, name string) (bool, error)
+	DeletePendingUpload(ctx context.Context, userID uuid.UUID, name string) error
+}
+
+type redisClient struct {
+	client *redis.Client
+}
+
+func NewRedisClient(client *redis.Client) RedisClient {
+	return &redisClient{
+		client: client,
+	}
+}
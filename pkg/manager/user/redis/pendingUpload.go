@@ -0,0 +1,29 @@
+package user_redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func pendingUploadKey(userID uuid.UUID, name string) string {
+	return fmt.Sprintf("pending_upload:%s:%s", userID, name)
+}
+
+func (c *redisClient) SetPendingUpload(ctx context.Context, userID uuid.UUID, name string, ttl time.Duration) error {
+	return c.client.Set(ctx, pendingUploadKey(userID, name), 1, ttl).Err()
+}
+
+func (c *redisClient) IsPendingUpload(ctx context.Context, userID uuid.UUID, name string) (bool, error) {
+	count, err := c.client.Exists(ctx, pendingUploadKey(userID, name)).Result()
+	if err != nil {
+		return false, err
+	}
+	return count == 1, nil
+}
+
+func (c *redisClient) DeletePendingUpload(ctx context.Context, userID uuid.UUID, name string) error {
+	return c.client.Del(ctx, pendingUploadKey(userID, name)).Err()
+}
@@ -0,0 +1,50 @@
+package user_redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+var ErrUserStatsNotFound = fmt.Errorf("user stats not found")
+
+// UserStats are the derived statistics shown on a public profile,
+// they are too expensive to compute on every request.
+type UserStats struct {
+	ActiveCount         int64          `json:"active_count"`
+	SoldCount           int64          `json:"sold_count"`
+	AverageResponseTime *time.Duration `json:"average_response_time"`
+	ResponseRate        *float64       `json:"response_rate"`
+	LastActiveAt        *time.Time     `json:"last_active_at"`
+}
+
+func userStatsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("user_stats:%s", userID)
+}
+
+func (c *redisClient) SetUserStats(ctx context.Context, userID uuid.UUID, stats UserStats, ttl time.Duration) error {
+	value, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, userStatsKey(userID), value, ttl).Err()
+}
+
+func (c *redisClient) GetUserStats(ctx context.Context, userID uuid.UUID) (*UserStats, error) {
+	value, err := c.client.Get(ctx, userStatsKey(userID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrUserStatsNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var stats UserStats
+	if err := json.Unmarshal(value, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
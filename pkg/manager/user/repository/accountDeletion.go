@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type accountDeletionRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountDeletionRepository(db *gorm.DB) AccountDeletionRepository {
+	return &accountDeletionRepository{
+		db,
+	}
+}
+
+func (r *accountDeletionRepository) CreateOrGet(ctx context.Context, userID uuid.UUID) (*AccountDeletion, error) {
+	deletion := AccountDeletion{
+		UserID:    userID,
+		Status:    AccountDeletionStatusPending,
+		NextRunAt: time.Now(),
+	}
+	resp := r.db.Where("user_id = ?", userID).FirstOrCreate(&deletion)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &deletion, nil
+}
+
+func (r *accountDeletionRepository) ClaimNext(ctx context.Context, lease time.Duration) (*AccountDeletion, error) {
+	var deletion AccountDeletion
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		// a running job whose lease expired belongs to a worker that stopped, it is picked up again
+		resp := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_run_at <= ? AND (locked_until IS NULL OR locked_until < ?)",
+				[]AccountDeletionStatus{AccountDeletionStatusPending, AccountDeletionStatusRunning}, now, now).
+			Order("next_run_at").
+			First(&deletion)
+		if resp.Error != nil {
+			return resp.Error
+		}
+		lockedUntil := now.Add(lease)
+		deletion.Status = AccountDeletionStatusRunning
+		deletion.LockedUntil = &lockedUntil
+		deletion.Attempts++
+		return tx.Model(&AccountDeletion{}).Where("id = ?", deletion.ID).Updates(map[string]interface{}{
+			"status":       deletion.Status,
+			"locked_until": deletion.LockedUntil,
+			"attempts":     deletion.Attempts,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+func (r *accountDeletionRepository) SetStep(ctx context.Context, id uuid.UUID, step int) error {
+	return r.db.Model(&AccountDeletion{}).Where("id = ?", id).Update("step", step).Error
+}
+
+func (r *accountDeletionRepository) Complete(ctx context.Context, id uuid.UUID) error {
+	return r.db.Model(&AccountDeletion{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       AccountDeletionStatusCompleted,
+		"locked_until": nil,
+		"last_error":   "",
+		"completed_at": time.Now(),
+	}).Error
+}
+
+func (r *accountDeletionRepository) Retry(ctx context.Context, id uuid.UUID, lastError string, nextRunAt time.Time) error {
+	return r.db.Model(&AccountDeletion{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       AccountDeletionStatusPending,
+		"locked_until": nil,
+		"last_error":   lastError,
+		"next_run_at":  nextRunAt,
+	}).Error
+}
+
+func (r *accountDeletionRepository) Fail(ctx context.Context, id uuid.UUID, lastError string) error {
+	return r.db.Model(&AccountDeletion{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       AccountDeletionStatusFailed,
+		"locked_until": nil,
+		"last_error":   lastError,
+	}).Error
+}
+
+func (r *accountDeletionRepository) Migrate() error {
+	return r.db.AutoMigrate(&AccountDeletion{})
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{
+		db,
+	}
+}
+
+func (r *dataExportRepository) Create(ctx context.Context, export *DataExport) error {
+	resp := r.db.Create(export)
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return nil
+}
+
+func (r *dataExportRepository) GetExport(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*DataExport, error) {
+	var export DataExport
+	resp := r.db.Where("id = ? AND user_id = ?", id, userID).First(&export)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) GetActiveExport(ctx context.Context, userID uuid.UUID) (*DataExport, error) {
+	var export DataExport
+	resp := r.db.Where("user_id = ? AND status IN ?", userID, []DataExportStatus{DataExportStatusPending, DataExportStatusRunning}).
+		Order("created_at DESC").
+		First(&export)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) ClaimNext(ctx context.Context, lease time.Duration) (*DataExport, error) {
+	var export DataExport
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		// a running export whose lease expired belongs to a worker that stopped, it is picked up again
+		resp := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_run_at <= ? AND (locked_until IS NULL OR locked_until < ?)",
+				[]DataExportStatus{DataExportStatusPending, DataExportStatusRunning}, now, now).
+			Order("next_run_at").
+			First(&export)
+		if resp.Error != nil {
+			return resp.Error
+		}
+		lockedUntil := now.Add(lease)
+		export.Status = DataExportStatusRunning
+		export.LockedUntil = &lockedUntil
+		export.Attempts++
+		return tx.Model(&DataExport{}).Where("id = ?", export.ID).Updates(map[string]interface{}{
+			"status":       export.Status,
+			"locked_until": export.LockedUntil,
+			"attempts":     export.Attempts,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *dataExportRepository) Complete(ctx context.Context, id uuid.UUID, objectName string, expiresAt time.Time) error {
+	return r.db.Model(&DataExport{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       DataExportStatusCompleted,
+		"locked_until": nil,
+		"last_error":   "",
+		"object_name":  objectName,
+		"completed_at": time.Now(),
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+func (r *dataExportRepository) Retry(ctx context.Context, id uuid.UUID, lastError string, nextRunAt time.Time) error {
+	return r.db.Model(&DataExport{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       DataExportStatusPending,
+		"locked_until": nil,
+		"last_error":   lastError,
+		"next_run_at":  nextRunAt,
+	}).Error
+}
+
+func (r *dataExportRepository) Fail(ctx context.Context, id uuid.UUID, lastError string) error {
+	return r.db.Model(&DataExport{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       DataExportStatusFailed,
+		"locked_until": nil,
+		"last_error":   lastError,
+	}).Error
+}
+
+func (r *dataExportRepository) GetExpiredExports(ctx context.Context, limit int) ([]DataExport, error) {
+	var exports []DataExport = make([]DataExport, 0)
+	resp := r.db.Where("status = ? AND expires_at <= ?", DataExportStatusCompleted, time.Now()).
+		Limit(limit).
+		Find(&exports)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return exports, nil
+}
+
+func (r *dataExportRepository) SetExpired(ctx context.Context, id uuid.UUID) error {
+	return r.db.Model(&DataExport{}).Where("id = ?", id).Update("status", DataExportStatusExpired).Error
+}
+
+func (r *dataExportRepository) Migrate() error {
+	return r.db.AutoMigrate(&DataExport{})
+}
@@ -3,16 +3,27 @@ package repository
 import (
 	"context"
 	"ketalk-api/common"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type User struct {
-	ID       uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
-	Username string
-	Image    *string
-	Email    string
-	Password *string
+	ID            uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	Username      string
+	Image         *string
+	Email         string
+	Password      *string
+	EmailVerified bool        `gorm:"not null;default:false"`
+	Role          common.Role `gorm:"not null;default:user"`
+	// Phone is in E.164 format and only set once it is verified
+	Phone           *string `gorm:"uniqueIndex"`
+	PhoneVerifiedAt *time.Time
+	// SuspendedAt is set by a moderator, the suspension ends at SuspendedUntil or never when it is nil
+	SuspendedAt    *time.Time
+	SuspendedUntil *time.Time
+	// Locale is the language the user wants responses in, empty to follow the device
+	Locale common.Locale
 	common.CreatedUpdatedDeleted
 }
 
@@ -20,19 +31,184 @@ type Repository interface {
 	CreateUser(ctx context.Context, user *User) error
 	GetUser(ctx context.Context, userId uuid.UUID) (*User, error)
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByPhone(ctx context.Context, phone string) (*User, error)
 	UpdateUser(ctx context.Context, user *User) error
+	UpdatePassword(ctx context.Context, userId uuid.UUID, password string) error
+	SetEmailVerified(ctx context.Context, userId uuid.UUID) error
+	SetRole(ctx context.Context, userId uuid.UUID, role common.Role) error
+	SetPhone(ctx context.Context, userId uuid.UUID, phone string) error
+	// SetLocale clears the locale of the user when it is empty
+	SetLocale(ctx context.Context, userId uuid.UUID, locale common.Locale) error
+	SetSuspension(ctx context.Context, userId uuid.UUID, suspendedAt *time.Time, suspendedUntil *time.Time) error
+	SoftDeleteUser(ctx context.Context, userId uuid.UUID) error
 	MigrateUser() error
 }
 
+// UserGeofence is the neighborhood of a user, the past ones are kept soft deleted as the history
 type UserGeofence struct {
 	ID         uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
-	UserID     uuid.UUID `gorm:"type:uuid;"`
+	UserID     uuid.UUID `gorm:"type:uuid;index"`
 	GeofenceID uuid.UUID
+	// VerifiedAt is the last time the location of the user was resolved to the geofence
+	VerifiedAt *time.Time
 	common.CreatedDeleted
 }
 
 type UserGeofenceRepository interface {
 	GetUserGeofence(ctx context.Context, userID uuid.UUID) (*UserGeofence, error)
+	// GetUserGeofenceHistory returns the current and the past geofences of the user, newest first
+	GetUserGeofenceHistory(ctx context.Context, userID uuid.UUID) ([]UserGeofence, error)
 	Create(ctx context.Context, userGeofence *UserGeofence) error
+	// Replace moves the current geofence of the user to the history and creates the new one
+	Replace(ctx context.Context, userGeofence *UserGeofence) error
+	SetVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error
+	Migrate() error
+}
+
+type AccountDeletionStatus string
+
+const (
+	AccountDeletionStatusPending   AccountDeletionStatus = "pending"
+	AccountDeletionStatusRunning   AccountDeletionStatus = "running"
+	AccountDeletionStatusCompleted AccountDeletionStatus = "completed"
+	AccountDeletionStatusFailed    AccountDeletionStatus = "failed"
+)
+
+// AccountDeletion is the background job deleting an account, Step is the last step that completed
+// so a job that was interrupted resumes with the next one
+type AccountDeletion struct {
+	ID          uuid.UUID             `gorm:"primaryKey;default:gen_random_uuid()"`
+	UserID      uuid.UUID             `gorm:"type:uuid;uniqueIndex"`
+	Status      AccountDeletionStatus `gorm:"not null;default:pending;index"`
+	Step        int                   `gorm:"not null;default:0"`
+	Attempts    int                   `gorm:"not null;default:0"`
+	LastError   string
+	NextRunAt   time.Time `gorm:"index"`
+	LockedUntil *time.Time
+	CompletedAt *time.Time
+	common.CreatedUpdated
+}
+
+type AccountDeletionRepository interface {
+	// CreateOrGet creates the job of the user unless there is one already
+	CreateOrGet(ctx context.Context, userID uuid.UUID) (*AccountDeletion, error)
+	// ClaimNext locks the next due job for the lease duration, it returns gorm.ErrRecordNotFound when there is none
+	ClaimNext(ctx context.Context, lease time.Duration) (*AccountDeletion, error)
+	SetStep(ctx context.Context, id uuid.UUID, step int) error
+	Complete(ctx context.Context, id uuid.UUID) error
+	// Retry releases the job so it runs again at nextRunAt
+	Retry(ctx context.Context, id uuid.UUID, lastError string, nextRunAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, lastError string) error
+	Migrate() error
+}
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending   DataExportStatus = "pending"
+	DataExportStatusRunning   DataExportStatus = "running"
+	DataExportStatusCompleted DataExportStatus = "completed"
+	DataExportStatusFailed    DataExportStatus = "failed"
+	// DataExportStatusExpired is set once the archive of a completed export was removed
+	DataExportStatusExpired DataExportStatus = "expired"
+)
+
+// DataExport is the background job building the archive of everything stored about a user
+type DataExport struct {
+	ID          uuid.UUID        `gorm:"primaryKey;default:gen_random_uuid()"`
+	UserID      uuid.UUID        `gorm:"type:uuid;index"`
+	Status      DataExportStatus `gorm:"not null;default:pending;index"`
+	Attempts    int              `gorm:"not null;default:0"`
+	LastError   string
+	NextRunAt   time.Time `gorm:"index"`
+	LockedUntil *time.Time
+	// ObjectName is the archive in storage.ContainerExports
+	ObjectName  string
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time `gorm:"index"`
+	common.CreatedUpdated
+}
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *DataExport) error
+	GetExport(ctx context.Context, id uuid.UUID, userID uuid.UUID) (*DataExport, error)
+	// GetActiveExport returns the pending or running export of the user
+	GetActiveExport(ctx context.Context, userID uuid.UUID) (*DataExport, error)
+	// ClaimNext locks the next due export for the lease duration, it returns gorm.ErrRecordNotFound when there is none
+	ClaimNext(ctx context.Context, lease time.Duration) (*DataExport, error)
+	Complete(ctx context.Context, id uuid.UUID, objectName string, expiresAt time.Time) error
+	// Retry releases the export so it runs again at nextRunAt
+	Retry(ctx context.Context, id uuid.UUID, lastError string, nextRunAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, lastError string) error
+	GetExpiredExports(ctx context.Context, limit int) ([]DataExport, error)
+	SetExpired(ctx context.Context, id uuid.UUID) error
+	Migrate() error
+}
+
+// UserIdentity links an account of an identity provider to a user, a user can have one per provider
+type UserIdentity struct {
+	ID         uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	Provider   string    `gorm:"not null;uniqueIndex:idx_user_identity_external;uniqueIndex:idx_user_identity_user"`
+	ExternalID string    `gorm:"not null;uniqueIndex:idx_user_identity_external"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_identity_user"`
+	// Email is the email the provider shared when the identity was linked
+	Email string
+	common.CreatedUpdated
+}
+
+type UserIdentityRepository interface {
+	GetIdentity(ctx context.Context, provider string, externalID string) (*UserIdentity, error)
+	GetUserIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error)
+	Create(ctx context.Context, identity *UserIdentity) error
+	// Delete removes the identity of the provider while the user row is locked, so concurrent deletes
+	// see each other. check is given the user and all of their identities and can refuse with an error.
+	Delete(ctx context.Context, userID uuid.UUID, provider string, check func(user *User, identities []UserIdentity) error) error
+	Migrate() error
+}
+
+// UserBlock hides the items and messages of the blocked user from the blocker,
+// and no conversation can be started between them.
+type UserBlock struct {
+	ID        uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	BlockerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_block_pair"`
+	BlockedID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_user_block_pair;index"`
+	CreatedAt time.Time
+}
+
+type UserBlockRepository interface {
+	// Create does nothing when the user is already blocked
+	Create(ctx context.Context, block *UserBlock) error
+	Delete(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error
+	GetBlock(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (*UserBlock, error)
+	GetBlocks(ctx context.Context, blockerID uuid.UUID) ([]UserBlock, error)
+	// HasBlockBetween reports whether either of the users blocked the other one
+	HasBlockBetween(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error)
+	Migrate() error
+}
+
+// NotificationPreference is only stored once the user changed the defaults, everything is enabled without it
+type NotificationPreference struct {
+	ID     uuid.UUID `gorm:"primaryKey;default:gen_random_uuid()"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	// channels
+	Push  bool `gorm:"not null"`
+	Email bool `gorm:"not null"`
+	InApp bool `gorm:"not null"`
+	// event types
+	NewMessage     bool `gorm:"not null"`
+	PriceDrop      bool `gorm:"not null"`
+	ItemSold       bool `gorm:"not null"`
+	ReviewReceived bool `gorm:"not null"`
+	// QuietHoursStart and QuietHoursEnd are minutes since midnight in QuietHoursTimezone, both are nil without quiet hours
+	QuietHoursStart    *int
+	QuietHoursEnd      *int
+	QuietHoursTimezone string
+	common.CreatedUpdated
+}
+
+type NotificationPreferenceRepository interface {
+	GetPreference(ctx context.Context, userID uuid.UUID) (*NotificationPreference, error)
+	// Save creates the preference of the user or replaces the stored one
+	Save(ctx context.Context, preference *NotificationPreference) error
 	Migrate() error
 }
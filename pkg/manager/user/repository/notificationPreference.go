@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		db,
+	}
+}
+
+func (r *notificationPreferenceRepository) GetPreference(ctx context.Context, userID uuid.UUID) (*NotificationPreference, error) {
+	var preference NotificationPreference
+	resp := r.db.Where("user_id = ?", userID).First(&preference)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &preference, nil
+}
+
+func (r *notificationPreferenceRepository) Save(ctx context.Context, preference *NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"push",
+			"email",
+			"in_app",
+			"new_message",
+			"price_drop",
+			"item_sold",
+			"review_received",
+			"quiet_hours_start",
+			"quiet_hours_end",
+			"quiet_hours_timezone",
+			"updated_at",
+		}),
+	}).Create(preference).Error
+}
+
+func (r *notificationPreferenceRepository) Migrate() error {
+	return r.db.AutoMigrate(&NotificationPreference{})
+}
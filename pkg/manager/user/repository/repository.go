@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"ketalk-api/common"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -39,6 +41,10 @@ func (r *repository) GetUser(ctx context.Context, userId uuid.UUID) (*User, erro
 }
 
 func (r *repository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	// users who signed up with a phone number have no email
+	if email == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
 	var user User
 	resp := r.DB.Where("email = ?", email).First(&user)
 	if resp.Error != nil {
@@ -47,6 +53,15 @@ func (r *repository) GetUserByEmail(ctx context.Context, email string) (*User, e
 	return &user, nil
 }
 
+func (r *repository) GetUserByPhone(ctx context.Context, phone string) (*User, error) {
+	var user User
+	resp := r.DB.Where("phone = ?", phone).First(&user)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &user, nil
+}
+
 func (r *repository) UpdateUser(ctx context.Context, user *User) error {
 	res := r.Where("id = ?", user.ID).Updates(user)
 	if res.Error != nil {
@@ -55,6 +70,110 @@ func (r *repository) UpdateUser(ctx context.Context, user *User) error {
 	return nil
 }
 
+func (r *repository) UpdatePassword(ctx context.Context, userId uuid.UUID, password string) error {
+	res := r.Model(&User{}).Where("id = ?", userId).Update("password", password)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return fmt.Errorf("more than one row updated")
+	}
+	return nil
+}
+
+func (r *repository) SetEmailVerified(ctx context.Context, userId uuid.UUID) error {
+	res := r.Model(&User{}).Where("id = ?", userId).Update("email_verified", true)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return fmt.Errorf("more than one row updated")
+	}
+	return nil
+}
+
+func (r *repository) SetRole(ctx context.Context, userId uuid.UUID, role common.Role) error {
+	res := r.Model(&User{}).Where("id = ?", userId).Update("role", role)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return common.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) SetSuspension(ctx context.Context, userId uuid.UUID, suspendedAt *time.Time, suspendedUntil *time.Time) error {
+	res := r.Model(&User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+		"suspended_at":    suspendedAt,
+		"suspended_until": suspendedUntil,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return common.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) SetLocale(ctx context.Context, userId uuid.UUID, locale common.Locale) error {
+	return r.Model(&User{}).Where("id = ?", userId).Update("locale", locale).Error
+}
+
+func (r *repository) SetPhone(ctx context.Context, userId uuid.UUID, phone string) error {
+	res := r.Model(&User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+		"phone":             phone,
+		"phone_verified_at": time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return common.ErrRecordNotFound
+	}
+	return nil
+}
+
+// SoftDeleteUser marks the user as deleted and clears the personal data, the unique phone number and
+// the linked identities are released as well. It is safe to call again on a user that is already deleted.
+func (r *repository) SoftDeleteUser(ctx context.Context, userId uuid.UUID) error {
+	return r.Transaction(func(tx *gorm.DB) error {
+		res := tx.Unscoped().Model(&User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+			"username":          "Deleted user",
+			"email":             "",
+			"email_verified":    false,
+			"password":          nil,
+			"image":             nil,
+			"phone":             nil,
+			"phone_verified_at": nil,
+			"deleted_at":        gorm.Expr("COALESCE(deleted_at, ?)", time.Now()),
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return common.ErrRecordNotFound
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&UserIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userId).Delete(&NotificationPreference{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("blocker_id = ? OR blocked_id = ?", userId, userId).Delete(&UserBlock{}).Error
+	})
+}
+
 func (r *repository) MigrateUser() error {
-	return r.AutoMigrate(&User{})
+	hasEmailVerified := r.Migrator().HasColumn(&User{}, "email_verified")
+	if err := r.AutoMigrate(&User{}); err != nil {
+		return err
+	}
+	if !hasEmailVerified {
+		// only accounts created through a provider had their email verified by it, password
+		// accounts could register any email and stay unverified until they confirm it
+		return r.Model(&User{}).Where("password IS NULL").Update("email_verified", true).Error
+	}
+	return nil
 }
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userBlockRepository struct {
+	db *gorm.DB
+}
+
+func NewUserBlockRepository(db *gorm.DB) UserBlockRepository {
+	return &userBlockRepository{
+		db,
+	}
+}
+
+func (r *userBlockRepository) Create(ctx context.Context, block *UserBlock) error {
+	resp := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(block)
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return nil
+}
+
+func (r *userBlockRepository) Delete(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) error {
+	resp := r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&UserBlock{})
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if resp.RowsAffected != 1 {
+		return common.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *userBlockRepository) GetBlock(ctx context.Context, blockerID uuid.UUID, blockedID uuid.UUID) (*UserBlock, error) {
+	var block UserBlock
+	resp := r.db.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).First(&block)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &block, nil
+}
+
+func (r *userBlockRepository) GetBlocks(ctx context.Context, blockerID uuid.UUID) ([]UserBlock, error) {
+	var blocks []UserBlock = make([]UserBlock, 0)
+	resp := r.db.Where("blocker_id = ?", blockerID).Order("created_at DESC").Find(&blocks)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return blocks, nil
+}
+
+func (r *userBlockRepository) HasBlockBetween(ctx context.Context, userID uuid.UUID, otherUserID uuid.UUID) (bool, error) {
+	var count int64
+	resp := r.db.Model(&UserBlock{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherUserID, otherUserID, userID).
+		Count(&count)
+	if resp.Error != nil {
+		return false, resp.Error
+	}
+	return count > 0, nil
+}
+
+func (r *userBlockRepository) Migrate() error {
+	return r.db.AutoMigrate(&UserBlock{})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -34,6 +35,28 @@ func (r *userGeofenceRepository) Create(ctx context.Context, userGeofence *UserG
 	return nil
 }
 
+func (r *userGeofenceRepository) GetUserGeofenceHistory(ctx context.Context, userID uuid.UUID) ([]UserGeofence, error) {
+	var userGeofences []UserGeofence = make([]UserGeofence, 0)
+	resp := r.db.Unscoped().Where("user_id = ?", userID).Order("created_at DESC").Find(&userGeofences)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return userGeofences, nil
+}
+
+func (r *userGeofenceRepository) Replace(ctx context.Context, userGeofence *UserGeofence) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userGeofence.UserID).Delete(&UserGeofence{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(userGeofence).Error
+	})
+}
+
+func (r *userGeofenceRepository) SetVerified(ctx context.Context, id uuid.UUID, verifiedAt time.Time) error {
+	return r.db.Model(&UserGeofence{}).Where("id = ?", id).Update("verified_at", verifiedAt).Error
+}
+
 func (r *userGeofenceRepository) Migrate() error {
 	return r.db.AutoMigrate(&UserGeofence{})
 }
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"ketalk-api/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{
+		db,
+	}
+}
+
+func (r *userIdentityRepository) GetIdentity(ctx context.Context, provider string, externalID string) (*UserIdentity, error) {
+	var identity UserIdentity
+	resp := r.db.Where("provider = ? AND external_id = ?", provider, externalID).First(&identity)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return &identity, nil
+}
+
+func (r *userIdentityRepository) GetUserIdentities(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error) {
+	var identities []UserIdentity = make([]UserIdentity, 0)
+	resp := r.db.Where("user_id = ?", userID).Order("created_at").Find(&identities)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	return identities, nil
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *UserIdentity) error {
+	resp := r.db.Create(identity)
+	if resp.Error != nil {
+		return resp.Error
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) Delete(ctx context.Context, userID uuid.UUID, provider string, check func(user *User, identities []UserIdentity) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", userID).First(&user).Error; err != nil {
+			return err
+		}
+		var identities []UserIdentity
+		if err := tx.Where("user_id = ?", userID).Find(&identities).Error; err != nil {
+			return err
+		}
+		if err := check(&user, identities); err != nil {
+			return err
+		}
+		resp := tx.Where("user_id = ? AND provider = ?", userID, provider).Delete(&UserIdentity{})
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if resp.RowsAffected != 1 {
+			return common.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *userIdentityRepository) Migrate() error {
+	return r.db.AutoMigrate(&UserIdentity{})
+}
@@ -0,0 +1,58 @@
+package apple
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/pkg/provider/jwks"
+	"ketalk-api/pkg/provider/model"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+var ErrNotSupported = fmt.Errorf("not supported by apple")
+
+type Config struct {
+	// Audiences are the bundle id of the app and the services id used by the web sign in
+	Audiences []string `yaml:"audiences" env:"APPLE_AUDIENCES" env-separator:","`
+	// Issuer and JWKSURL are only overridden to point the client at a fake key server
+	Issuer  string `yaml:"issuer" env:"APPLE_ISSUER" env-default:"https://appleid.apple.com"`
+	JWKSURL string `yaml:"jwks_url" env:"APPLE_JWKS_URL" env-default:"https://appleid.apple.com/auth/keys"`
+}
+
+type appleClient struct {
+	cfg    Config
+	keySet *jwks.KeySet
+}
+
+func NewAppleClient(cfg Config) model.ProviderClient {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: time.Duration(5) * time.Second,
+			}).DialContext,
+			DisableKeepAlives: true,
+		},
+	}
+	return &appleClient{
+		cfg:    cfg,
+		keySet: jwks.NewKeySet(cfg.JWKSURL, client, 0),
+	}
+}
+
+func (c *appleClient) Name() model.ProviderName {
+	return model.ProviderNameApple
+}
+
+// QueryUserDetails is not supported because apple has no user info endpoint,
+// everything we get is in the id token.
+func (c *appleClient) QueryUserDetails(ctx context.Context, token model.Token) (*model.ProviderUserDetails, error) {
+	return nil, ErrNotSupported
+}
+
+// UpdateAccessToken is not supported because we never call apple apis on behalf of the user.
+func (c *appleClient) UpdateAccessToken(ctx context.Context, token model.Token) (*oauth2.Token, error) {
+	return nil, ErrNotSupported
+}
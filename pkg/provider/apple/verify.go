@@ -0,0 +1,89 @@
+package apple
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"ketalk-api/pkg/provider/model"
+	"strconv"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/exp/slices"
+)
+
+const privateRelayDomain = "@privaterelay.appleid.com"
+
+// flexibleBool accepts both true and "true", apple sends booleans as strings in some tokens
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseBool(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*b = flexibleBool(value)
+	return nil
+}
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Nonce          string       `json:"nonce"`
+	Email          string       `json:"email"`
+	EmailVerified  flexibleBool `json:"email_verified"`
+	IsPrivateEmail flexibleBool `json:"is_private_email"`
+}
+
+func (c *appleClient) VerifyIDToken(ctx context.Context, idToken string, nonce string) (*model.IdTokenUserDetails, error) {
+	var claims Claims
+	token, err := jwt.ParseWithClaims(idToken, &claims, c.keySet.Keyfunc(ctx), jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify apple id token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid apple id token")
+	}
+
+	if !slices.ContainsFunc(c.cfg.Audiences, func(aud string) bool { return claims.VerifyAudience(aud, true) }) {
+		return nil, fmt.Errorf("invalid audience")
+	}
+	if claims.Issuer != c.cfg.Issuer {
+		return nil, fmt.Errorf("invalid issuer: %v", claims.Issuer)
+	}
+	if !verifyNonce(claims.Nonce, nonce) {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("empty apple id")
+	}
+	// email is only present when the user shared it on the first authorization
+	if claims.Email == "" {
+		return nil, fmt.Errorf("empty email")
+	}
+	if !claims.EmailVerified {
+		return nil, fmt.Errorf("unverified email")
+	}
+
+	email := strings.TrimSpace(strings.ToLower(claims.Email))
+	// apple never sends the name in the id token, the app receives it on the first login only
+	return &model.IdTokenUserDetails{
+		ExternalID:     claims.Subject,
+		VerifiedEmail:  email,
+		IsPrivateEmail: bool(claims.IsPrivateEmail) || strings.HasSuffix(email, privateRelayDomain),
+	}, nil
+}
+
+// verifyNonce checks the nonce claim against the raw nonce the app generated.
+// Native apps pass the sha256 of the raw nonce to apple, so the claim holds its hex digest.
+func verifyNonce(claim string, rawNonce string) bool {
+	if claim == "" || rawNonce == "" {
+		return false
+	}
+	hash := sha256.Sum256([]byte(rawNonce))
+	hashed := hex.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(claim), []byte(hashed)) == 1 ||
+		subtle.ConstantTimeCompare([]byte(claim), []byte(rawNonce)) == 1
+}
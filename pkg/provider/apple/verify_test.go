@@ -0,0 +1,238 @@
+package apple
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"ketalk-api/pkg/provider/jwks"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testKeyID    = "test-key"
+	testAudience = "com.ketalk.app"
+	testIssuer   = "https://appleid.apple.com"
+	testNonce    = "raw-nonce"
+)
+
+// newFakeKeyServer serves the public part of key the way apple publishes its keys
+func newFakeKeyServer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+	set := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kid": testKeyID,
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func hashedNonce(nonce string) string {
+	hash := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newFakeKeyServer(t, key)
+	client := &appleClient{
+		cfg:    Config{Audiences: []string{"com.ketalk.web", testAudience}, Issuer: testIssuer},
+		keySet: jwks.NewKeySet(server.URL, server.Client(), 0),
+	}
+
+	validClaims := func() Claims {
+		return Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    testIssuer,
+				Subject:   "001234.abcdef",
+				Audience:  jwt.ClaimStrings{testAudience},
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(10 * time.Minute)),
+			},
+			Nonce:         hashedNonce(testNonce),
+			Email:         "User@Example.com",
+			EmailVerified: true,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		token       func() string
+		wantErr     bool
+		wantEmail   string
+		wantPrivate bool
+	}{
+		{
+			name:      "valid",
+			token:     func() string { return signToken(t, key, testKeyID, validClaims()) },
+			wantEmail: "user@example.com",
+		},
+		{
+			name: "raw nonce",
+			token: func() string {
+				claims := validClaims()
+				claims.Nonce = testNonce
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantEmail: "user@example.com",
+		},
+		{
+			name: "email verified as a string",
+			token: func() string {
+				claims := jwt.MapClaims{
+					"iss":              testIssuer,
+					"sub":              "001234.abcdef",
+					"aud":              testAudience,
+					"exp":              time.Now().Add(10 * time.Minute).Unix(),
+					"nonce":            hashedNonce(testNonce),
+					"email":            "abc@privaterelay.appleid.com",
+					"email_verified":   "true",
+					"is_private_email": "true",
+				}
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantEmail:   "abc@privaterelay.appleid.com",
+			wantPrivate: true,
+		},
+		{
+			name: "private relay email without the claim",
+			token: func() string {
+				claims := validClaims()
+				claims.Email = "abc@privaterelay.appleid.com"
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantEmail:   "abc@privaterelay.appleid.com",
+			wantPrivate: true,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims.Audience = jwt.ClaimStrings{"com.other.app"}
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims.Issuer = "https://example.com"
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "wrong nonce",
+			token: func() string {
+				claims := validClaims()
+				claims.Nonce = hashedNonce("other-nonce")
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "unverified email",
+			token: func() string {
+				claims := validClaims()
+				claims.EmailVerified = false
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name: "no email",
+			token: func() string {
+				claims := validClaims()
+				claims.Email = ""
+				return signToken(t, key, testKeyID, claims)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "signed by another key",
+			token:   func() string { return signToken(t, otherKey, testKeyID, validClaims()) },
+			wantErr: true,
+		},
+		{
+			name:    "unknown key id",
+			token:   func() string { return signToken(t, key, "other-key", validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "not signed with RS256",
+			token: func() string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims())
+				token.Header["kid"] = testKeyID
+				signed, err := token.SignedString([]byte("secret"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		details, err := client.VerifyIDToken(context.Background(), tt.token(), testNonce)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: VerifyIDToken succeeded, want an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: VerifyIDToken failed: %v", tt.name, err)
+			continue
+		}
+		if details.ExternalID != "001234.abcdef" || details.VerifiedEmail != tt.wantEmail || details.IsPrivateEmail != tt.wantPrivate {
+			t.Errorf("%s: VerifyIDToken = %+v, want email %q, private %v", tt.name, details, tt.wantEmail, tt.wantPrivate)
+		}
+	}
+}
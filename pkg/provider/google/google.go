@@ -36,6 +36,10 @@ func NewGoogleClient(cfg Config) model.ProviderClient {
 	}
 }
 
+func (c *googleClient) Name() model.ProviderName {
+	return model.ProviderNameGoogle
+}
+
 func (c *googleClient) Get(ctx context.Context, googleToken *string, endpoint string, result interface{}) error {
 	headers := c.headersApi(googleToken)
 	return c.handleHTTPRequest(ctx, http.MethodGet, endpoint, nil, result, headers)
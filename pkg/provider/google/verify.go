@@ -17,13 +17,14 @@ var issuers = []string{
 }
 
 type Claims struct {
+	Nonce         string `json:"nonce"`
 	Email         string `json:"email"`
 	EmailVerified bool   `json:"email_verified"`
 	FirstName     string `json:"given_name"`
 	LastName      string `json:"family_name"`
 }
 
-func (v *googleClient) VerifyIDToken(ctx context.Context, idToken string) (*model.IdTokenUserDetails, error) {
+func (v *googleClient) VerifyIDToken(ctx context.Context, idToken string, nonce string) (*model.IdTokenUserDetails, error) {
 	var claims Claims
 
 	payload, err := idtoken.Validate(ctx, idToken, v.cfg.Audience)
@@ -44,6 +45,9 @@ func (v *googleClient) VerifyIDToken(ctx context.Context, idToken string) (*mode
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal claims: %w", err)
 	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, fmt.Errorf("invalid nonce")
+	}
 	var info GoogleIdTokenUserInformation
 
 	info.ExternalID = payload.Subject
@@ -0,0 +1,185 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	defaultCacheDuration = time.Hour
+	// minRefreshInterval stops tokens with made up key ids from hammering the provider
+	minRefreshInterval = time.Minute
+)
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// KeySet fetches and caches the public keys published by an identity provider.
+// Keys are refreshed after the cache duration or when an unknown key id is seen.
+type KeySet struct {
+	url           string
+	client        *http.Client
+	cacheDuration time.Duration
+
+	lock      sync.RWMutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func NewKeySet(url string, client *http.Client, cacheDuration time.Duration) *KeySet {
+	if cacheDuration <= 0 {
+		cacheDuration = defaultCacheDuration
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &KeySet{
+		url:           url,
+		client:        client,
+		cacheDuration: cacheDuration,
+		keys:          make(map[string]interface{}),
+	}
+}
+
+// Keyfunc returns a jwt.Keyfunc resolving the verification key by the token kid.
+func (s *KeySet) Keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, fmt.Errorf("missing key id")
+		}
+		return s.GetKey(ctx, kid)
+	}
+}
+
+func (s *KeySet) GetKey(ctx context.Context, kid string) (interface{}, error) {
+	s.lock.RLock()
+	key, ok := s.keys[kid]
+	sinceFetch := time.Since(s.fetchedAt)
+	s.lock.RUnlock()
+	if ok && sinceFetch <= s.cacheDuration {
+		return key, nil
+	}
+	if !ok && sinceFetch < minRefreshInterval {
+		return nil, fmt.Errorf("unknown key id: %s", kid)
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key id: %s", kid)
+}
+
+func (s *KeySet) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code response: %+v", resp.StatusCode)
+	}
+
+	var set jsonWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// skip key types we don't support instead of failing the whole set
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	s.lock.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.lock.Unlock()
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
@@ -11,6 +11,7 @@ type ProviderName string
 const (
 	ProviderNameGoogle  ProviderName = "google"
 	ProviderNameOutlook ProviderName = "outlook"
+	ProviderNameApple   ProviderName = "apple"
 )
 
 type IdTokenUserDetails struct {
@@ -18,16 +19,19 @@ type IdTokenUserDetails struct {
 	FirstName     string
 	LastName      string
 	VerifiedEmail string
+	// IsPrivateEmail is set for relay addresses that hide the real email of the user
+	IsPrivateEmail bool
 }
 
 type ProviderUserDetails struct {
-	Email        string
-	FirstName    string
-	ExternalID   string
-	Image        string
-	Locale       string
-	ProviderName ProviderName
-	Token        Token
+	Email          string
+	IsPrivateEmail bool
+	FirstName      string
+	ExternalID     string
+	Image          string
+	Locale         string
+	ProviderName   ProviderName
+	Token          Token
 }
 
 type Token struct {
@@ -35,17 +39,19 @@ type Token struct {
 	RefreshToken string
 }
 
-type GoogleToken struct {
-	IdToken string
-	Token   Token
-}
-
 type ProviderToken struct {
-	GoogleToken *GoogleToken
+	ProviderName ProviderName
+	IdToken      string
+	// Nonce is the raw nonce the app used when requesting the id token
+	Nonce string
+	Token Token
+	// FirstName is sent by the app for providers which only share the name on the first login
+	FirstName string
 }
 
 type ProviderClient interface {
+	Name() ProviderName
 	QueryUserDetails(ctx context.Context, token Token) (*ProviderUserDetails, error)
-	VerifyIDToken(ctx context.Context, idToken string) (*IdTokenUserDetails, error)
+	VerifyIDToken(ctx context.Context, idToken string, nonce string) (*IdTokenUserDetails, error)
 	UpdateAccessToken(ctx context.Context, token Token) (*oauth2.Token, error)
 }
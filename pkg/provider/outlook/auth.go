@@ -0,0 +1,25 @@
+package outlook
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/pkg/provider/model"
+
+	"golang.org/x/oauth2"
+)
+
+func (c *outlookClient) UpdateAccessToken(ctx context.Context, token model.Token) (*oauth2.Token, error) {
+	config := oauth2.Config{
+		ClientID:     c.cfg.ID,
+		ClientSecret: c.cfg.Secret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  fmt.Sprintf("%s/%s/oauth2/v2.0/authorize", c.cfg.AuthorityURL, c.cfg.TenantID),
+			TokenURL: fmt.Sprintf("%s/%s/oauth2/v2.0/token", c.cfg.AuthorityURL, c.cfg.TenantID),
+		},
+		Scopes: []string{"openid", "email", "profile", "offline_access", "User.Read"},
+	}
+	tokenSource := config.TokenSource(ctx, &oauth2.Token{
+		RefreshToken: token.RefreshToken,
+	})
+	return tokenSource.Token()
+}
@@ -0,0 +1,34 @@
+package outlook
+
+import (
+	"ketalk-api/pkg/provider/model"
+	"strings"
+)
+
+type OutlookUserDetails struct {
+	ID                string `json:"id"`
+	GivenName         string `json:"givenName"`
+	DisplayName       string `json:"displayName"`
+	Mail              string `json:"mail"`
+	UserPrincipalName string `json:"userPrincipalName"`
+	PreferredLanguage string `json:"preferredLanguage"`
+}
+
+func (u *OutlookUserDetails) To(token model.Token) *model.ProviderUserDetails {
+	firstName := u.GivenName
+	if firstName == "" {
+		firstName = u.DisplayName
+	}
+	email := u.Mail
+	if email == "" {
+		email = u.UserPrincipalName
+	}
+	return &model.ProviderUserDetails{
+		ExternalID:   u.ID,
+		FirstName:    firstName,
+		Email:        strings.TrimSpace(strings.ToLower(email)),
+		Locale:       u.PreferredLanguage,
+		ProviderName: model.ProviderNameOutlook,
+		Token:        token,
+	}
+}
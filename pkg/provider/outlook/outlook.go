@@ -0,0 +1,66 @@
+package outlook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"ketalk-api/pkg/provider/jwks"
+	"ketalk-api/pkg/provider/model"
+	"net"
+	"net/http"
+	"time"
+)
+
+type Config struct {
+	ID       string `yaml:"client_id" env:"OUTLOOK_CLIENT_ID" env-default:""`
+	Secret   string `yaml:"client_secret" env:"OUTLOOK_CLIENT_SECRET" env-default:""`
+	TenantID string `yaml:"tenant_id" env:"OUTLOOK_TENANT_ID" env-default:"common"`
+	JWKSURL  string `yaml:"jwks_url" env:"OUTLOOK_JWKS_URL" env-default:"https://login.microsoftonline.com/common/discovery/v2.0/keys"`
+	// AuthorityURL is only overridden to point the client at a fake identity server
+	AuthorityURL string `yaml:"authority_url" env:"OUTLOOK_AUTHORITY_URL" env-default:"https://login.microsoftonline.com"`
+}
+
+type outlookClient struct {
+	cfg    Config
+	client *http.Client
+	keySet *jwks.KeySet
+}
+
+func NewOutlookClient(cfg Config) model.ProviderClient {
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: time.Duration(5) * time.Second,
+			}).DialContext,
+			DisableKeepAlives: true,
+		},
+	}
+	return &outlookClient{
+		cfg:    cfg,
+		client: client,
+		keySet: jwks.NewKeySet(cfg.JWKSURL, client, 0),
+	}
+}
+
+func (c *outlookClient) Name() model.ProviderName {
+	return model.ProviderNameOutlook
+}
+
+func (c *outlookClient) get(ctx context.Context, accessToken string, endpoint string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code response: %+v", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,15 @@
+package outlook
+
+import (
+	"context"
+	"ketalk-api/pkg/provider/model"
+)
+
+func (c *outlookClient) QueryUserDetails(ctx context.Context, token model.Token) (*model.ProviderUserDetails, error) {
+	var result OutlookUserDetails
+	err := c.get(ctx, token.AccessToken, "https://graph.microsoft.com/v1.0/me", &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.To(token), nil
+}
@@ -0,0 +1,98 @@
+package outlook
+
+import (
+	"context"
+	"fmt"
+	"ketalk-api/pkg/provider/model"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// consumerTenantID is the tenant of personal Microsoft accounts, whose emails are verified by Microsoft
+const consumerTenantID = "9188040d-6c67-4c5b-b112-36a304b66dad"
+
+type Claims struct {
+	jwt.RegisteredClaims
+	ObjectID          string `json:"oid"`
+	TenantID          string `json:"tid"`
+	Nonce             string `json:"nonce"`
+	Name              string `json:"name"`
+	GivenName         string `json:"given_name"`
+	FamilyName        string `json:"family_name"`
+	Email             string `json:"email"`
+	PreferredUsername string `json:"preferred_username"`
+	// EmailDomainOwnerVerified is only sent for work accounts whose domain is verified by the tenant
+	EmailDomainOwnerVerified bool `json:"xms_edov"`
+}
+
+func (c *outlookClient) VerifyIDToken(ctx context.Context, idToken string, nonce string) (*model.IdTokenUserDetails, error) {
+	var claims Claims
+	token, err := jwt.ParseWithClaims(idToken, &claims, c.keySet.Keyfunc(ctx), jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify outlook id token: %w", err)
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid outlook id token")
+	}
+
+	if !claims.VerifyAudience(c.cfg.ID, true) {
+		return nil, fmt.Errorf("invalid audience")
+	}
+	// multi tenant apps receive the issuer of the user's tenant
+	expectedIssuer := fmt.Sprintf("%s/%s/v2.0", c.cfg.AuthorityURL, claims.TenantID)
+	if claims.TenantID == "" || claims.Issuer != expectedIssuer {
+		return nil, fmt.Errorf("invalid issuer: %v", claims.Issuer)
+	}
+	if !isAllowedTenant(c.cfg.TenantID, claims.TenantID) {
+		return nil, fmt.Errorf("invalid tenant: %v", claims.TenantID)
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, fmt.Errorf("invalid nonce")
+	}
+
+	// the object id is only unique within the tenant
+	objectID := claims.ObjectID
+	if objectID == "" {
+		objectID = claims.Subject
+	}
+	if objectID == "" {
+		return nil, fmt.Errorf("empty outlook id")
+	}
+	externalID := fmt.Sprintf("%s:%s", claims.TenantID, objectID)
+
+	email := claims.Email
+	if email == "" {
+		email = claims.PreferredUsername
+	}
+	if email == "" {
+		return nil, fmt.Errorf("empty email")
+	}
+	if claims.TenantID != consumerTenantID && !claims.EmailDomainOwnerVerified {
+		return nil, fmt.Errorf("unverified email")
+	}
+
+	firstName := claims.GivenName
+	if firstName == "" {
+		firstName = claims.Name
+	}
+	return &model.IdTokenUserDetails{
+		ExternalID:    externalID,
+		FirstName:     firstName,
+		LastName:      claims.FamilyName,
+		VerifiedEmail: strings.TrimSpace(strings.ToLower(email)),
+	}, nil
+}
+
+func isAllowedTenant(configured string, tenantID string) bool {
+	switch configured {
+	case "common":
+		return true
+	case "organizations":
+		return tenantID != consumerTenantID
+	case "consumers":
+		return tenantID == consumerTenantID
+	default:
+		return tenantID == configured
+	}
+}
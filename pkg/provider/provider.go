@@ -3,51 +3,98 @@ package provider
 import (
 	"context"
 	"fmt"
+	"ketalk-api/common"
 	"ketalk-api/pkg/provider/model"
 
 	"golang.org/x/oauth2"
 )
 
+var ErrUnsupportedProvider = fmt.Errorf("%w: unsupported external identifier", common.ErrInvalidInput)
+
 type ProviderClient interface {
 	QueryUserDetails(ctx context.Context, externalIdentifier *model.ProviderToken) (*model.ProviderUserDetails, error)
 	VerifyIDToken(ctx context.Context, externalIdentifier *model.ProviderToken) (*model.ProviderUserDetails, error)
 	UpdateAccessToken(ctx context.Context, externalIdentifier *model.ProviderToken) (*oauth2.Token, error)
 }
 
+// providerClient dispatches to the registered provider by the provider name of the token,
+// so adding a provider only requires registering its client.
 type providerClient struct {
-	googleClient model.ProviderClient
+	providers map[model.ProviderName]model.ProviderClient
 }
 
-func NewProviderClient(googleClient model.ProviderClient) ProviderClient {
+func NewProviderClient(providers ...model.ProviderClient) ProviderClient {
+	registry := make(map[model.ProviderName]model.ProviderClient, len(providers))
+	for _, p := range providers {
+		registry[p.Name()] = p
+	}
 	return &providerClient{
-		googleClient: googleClient,
+		providers: registry,
 	}
 }
 
+func (p *providerClient) get(providerToken *model.ProviderToken) (model.ProviderClient, error) {
+	if providerToken == nil {
+		return nil, ErrUnsupportedProvider
+	}
+	client, ok := p.providers[providerToken.ProviderName]
+	if !ok {
+		return nil, ErrUnsupportedProvider
+	}
+	return client, nil
+}
+
 func (p *providerClient) QueryUserDetails(ctx context.Context, providerToken *model.ProviderToken) (*model.ProviderUserDetails, error) {
-	if providerToken.GoogleToken != nil {
-		return p.googleClient.QueryUserDetails(ctx, providerToken.GoogleToken.Token)
+	client, err := p.get(providerToken)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("unsupported external identifier")
+	return client.QueryUserDetails(ctx, providerToken.Token)
 }
 
 func (p *providerClient) VerifyIDToken(ctx context.Context, providerToken *model.ProviderToken) (*model.ProviderUserDetails, error) {
-	if providerToken != nil && providerToken.GoogleToken != nil {
-		_, err := p.googleClient.VerifyIDToken(ctx, providerToken.GoogleToken.IdToken)
-		if err != nil {
-			return nil, err
+	client, err := p.get(providerToken)
+	if err != nil {
+		return nil, err
+	}
+	idTokenDetails, err := client.VerifyIDToken(ctx, providerToken.IdToken, providerToken.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	if providerToken.Token.AccessToken == "" {
+		firstName := idTokenDetails.FirstName
+		if firstName == "" {
+			firstName = providerToken.FirstName
 		}
-		// TODO: Maybe not necessary to query user details here
-		// We are currently querying for signup/signin. We can query only for signup
-		// Moreover, verify id token already returns some of the user details
-		return p.QueryUserDetails(ctx, providerToken)
+		return &model.ProviderUserDetails{
+			Email:          idTokenDetails.VerifiedEmail,
+			IsPrivateEmail: idTokenDetails.IsPrivateEmail,
+			FirstName:      firstName,
+			ExternalID:     idTokenDetails.ExternalID,
+			ProviderName:   providerToken.ProviderName,
+			Token:          providerToken.Token,
+		}, nil
+	}
+	// TODO: Maybe not necessary to query user details here
+	// We are currently querying for signup/signin. We can query only for signup
+	// Moreover, verify id token already returns some of the user details
+	userDetails, err := client.QueryUserDetails(ctx, providerToken.Token)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("unsupported external identifier")
+	// the access token only adds profile fields, the user is identified by the verified id token the same way
+	// with or without it. The ids of the profile apis don't always match the id token, e.g. for Microsoft accounts.
+	userDetails.ExternalID = idTokenDetails.ExternalID
+	// email is taken from the id token because it is the verified one
+	userDetails.Email = idTokenDetails.VerifiedEmail
+	userDetails.IsPrivateEmail = idTokenDetails.IsPrivateEmail
+	return userDetails, nil
 }
 
 func (p *providerClient) UpdateAccessToken(ctx context.Context, providerToken *model.ProviderToken) (*oauth2.Token, error) {
-	if providerToken != nil && providerToken.GoogleToken != nil {
-		return p.googleClient.UpdateAccessToken(ctx, providerToken.GoogleToken.Token)
+	client, err := p.get(providerToken)
+	if err != nil {
+		return nil, err
 	}
-	return nil, fmt.Errorf("unsupported external identifier")
+	return client.UpdateAccessToken(ctx, providerToken.Token)
 }
@@ -35,6 +35,8 @@ func InitDB(ctx context.Context, cfg ConfigPostgres) (*gorm.DB, error) {
 			TablePrefix:   fmt.Sprintf("%s.", cfg.GetSchema()),
 			SingularTable: true,
 		},
+		// unique violations come back as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 	db.Logger = db.Logger.LogMode(logger.Info)
 	if err != nil {
@@ -0,0 +1,19 @@
+package sms
+
+import (
+	"context"
+	"log"
+)
+
+// logSmsSender prints every message to the log instead of sending it.
+// It is meant for local development only.
+type logSmsSender struct{}
+
+func NewLogSmsSender() SmsSender {
+	return &logSmsSender{}
+}
+
+func (s *logSmsSender) Send(ctx context.Context, message Message) error {
+	log.Printf("sms to %s: %s\n", message.To, message.Body)
+	return nil
+}
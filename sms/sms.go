@@ -0,0 +1,32 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	ProviderLog = "log"
+)
+
+type Message struct {
+	To   string
+	Body string
+}
+
+type SmsSender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+type Config struct {
+	Provider string `yaml:"provider" env:"SMS_PROVIDER" env-default:"log"`
+}
+
+func NewSmsSender(cfg Config) (SmsSender, error) {
+	switch cfg.Provider {
+	case ProviderLog:
+		return NewLogSmsSender(), nil
+	default:
+		return nil, fmt.Errorf("unsupported sms provider: %s", cfg.Provider)
+	}
+}
@@ -2,7 +2,10 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"strings"
 	"time"
@@ -13,13 +16,34 @@ import (
 const (
 	ContainerProfiles = "profiles"
 	ContainerItems    = "items"
+	ContainerExports  = "exports"
 )
 
+// presignedUrlExpiry is the expiry of upload and image links
+const presignedUrlExpiry = 24 * time.Hour
+
+var ErrObjectNotFound = fmt.Errorf("object not found")
+
+// ObjectInfo is the metadata of a stored object
+type ObjectInfo struct {
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
 type Storage interface {
 	GeneratePresignedUrlToUpload(ctx context.Context, imageUrl, containerName string) (string, error)
-	// GeneratePresignedUrlToRead(imageUrl, containerName string) (string, error)
+	// GeneratePresignedUrlToRead returns a link to download a private object, valid for expiry
+	GeneratePresignedUrlToRead(ctx context.Context, name, containerName string, expiry time.Duration) (string, error)
 	GetURLToRead(imageUrl, containerName string) string
 	GetUserImage(image string) string
+	// DeleteObjects deletes every object in the container whose name starts with prefix
+	DeleteObjects(ctx context.Context, prefix, containerName string) error
+	// GetObject returns the content of the object, the caller closes it
+	GetObject(ctx context.Context, name, containerName string) (io.ReadCloser, error)
+	PutObject(ctx context.Context, name, containerName string, body io.Reader, size int64, contentType string) error
+	// StatObject returns the metadata of the object without its content, ErrObjectNotFound when there is none
+	StatObject(ctx context.Context, name, containerName string) (*ObjectInfo, error)
 }
 
 type AzureBlobStorageConfig struct {
@@ -40,17 +64,17 @@ func NewAzureBlobStorage(cfg AzureBlobStorageConfig) Storage {
 }
 
 func (az *azureBlobStorage) GeneratePresignedUrlToUpload(ctx context.Context, imageUrl, containerName string) (string, error) {
-	return az.generatePresignedUrl(imageUrl, containerName, azblob.BlobSASPermissions{Write: true, Permissions: true})
+	return az.generatePresignedUrl(imageUrl, containerName, azblob.BlobSASPermissions{Write: true, Permissions: true}, presignedUrlExpiry)
 }
 
-// func (az *azureBlobStorage) GeneratePresignedUrlToRead(imageUrl, containerName string) (string, error) {
-// 	return az.generatePresignedUrl(imageUrl, containerName, azblob.BlobSASPermissions{Read: true, Permissions: true})
-// }
+func (az *azureBlobStorage) GeneratePresignedUrlToRead(ctx context.Context, name, containerName string, expiry time.Duration) (string, error) {
+	return az.generatePresignedUrl(name, containerName, azblob.BlobSASPermissions{Read: true}, expiry)
+}
 
 func (az *azureBlobStorage) GetURLToRead(imageUrl, containerName string) string {
 	// return fmt.Sprintf("https://%s/%s/%s", az.BlobUrl, containerName, imageUrl)
 	// TODO: use front door url
-	url, err := az.generatePresignedUrl(imageUrl, containerName, azblob.BlobSASPermissions{Read: true, Permissions: true})
+	url, err := az.generatePresignedUrl(imageUrl, containerName, azblob.BlobSASPermissions{Read: true, Permissions: true}, presignedUrlExpiry)
 	if err != nil {
 		fmt.Println("Error generating presigned url", err)
 	}
@@ -58,7 +82,7 @@ func (az *azureBlobStorage) GetURLToRead(imageUrl, containerName string) string
 	// return fmt.Sprintf("http://%s/%s/%s", az.FrontDoorUrl, containerName, imageUrl)
 }
 
-func (az *azureBlobStorage) generatePresignedUrl(imageName, containerName string, accessPolicy azblob.BlobSASPermissions) (string, error) {
+func (az *azureBlobStorage) generatePresignedUrl(imageName, containerName string, accessPolicy azblob.BlobSASPermissions, expiry time.Duration) (string, error) {
 	credential, err := azblob.NewSharedKeyCredential(az.AccountName, az.AccountKey)
 	if err != nil {
 		return "", err
@@ -77,12 +101,11 @@ func (az *azureBlobStorage) generatePresignedUrl(imageName, containerName string
 	blobURL := containerURL.NewBlobURL(imageName)
 
 	start := time.Now()
-	expiry := start.Add(24 * time.Hour)
 
 	sasQueryParams, err := azblob.BlobSASSignatureValues{
 		Protocol:      azblob.SASProtocolHTTPS,
 		StartTime:     start.UTC(),
-		ExpiryTime:    expiry.UTC(),
+		ExpiryTime:    start.Add(expiry).UTC(),
 		ContainerName: containerName,
 		BlobName:      imageName,
 		Permissions:   accessPolicy.String(),
@@ -103,3 +126,84 @@ func (az *azureBlobStorage) GetUserImage(image string) string {
 	}
 	return az.GetURLToRead(image, ContainerProfiles)
 }
+
+func (az *azureBlobStorage) containerURL(containerName string) (azblob.ContainerURL, error) {
+	credential, err := azblob.NewSharedKeyCredential(az.AccountName, az.AccountKey)
+	if err != nil {
+		return azblob.ContainerURL{}, err
+	}
+	serviceURL := azblob.NewServiceURL(
+		url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.blob.core.windows.net", az.AccountName),
+		},
+		azblob.NewPipeline(credential, azblob.PipelineOptions{}),
+	)
+	return serviceURL.NewContainerURL(containerName), nil
+}
+
+func (az *azureBlobStorage) DeleteObjects(ctx context.Context, prefix, containerName string) error {
+	containerURL, err := az.containerURL(containerName)
+	if err != nil {
+		return err
+	}
+
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		segment, err := containerURL.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{Prefix: prefix})
+		if err != nil {
+			return err
+		}
+		for _, blob := range segment.Segment.BlobItems {
+			blobURL := containerURL.NewBlobURL(blob.Name)
+			if _, err := blobURL.Delete(ctx, azblob.DeleteSnapshotsOptionInclude, azblob.BlobAccessConditions{}); err != nil {
+				return err
+			}
+		}
+		marker = segment.NextMarker
+	}
+	return nil
+}
+
+func (az *azureBlobStorage) GetObject(ctx context.Context, name, containerName string) (io.ReadCloser, error) {
+	containerURL, err := az.containerURL(containerName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := containerURL.NewBlobURL(name).Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body(azblob.RetryReaderOptions{MaxRetryRequests: 3}), nil
+}
+
+func (az *azureBlobStorage) PutObject(ctx context.Context, name, containerName string, body io.Reader, size int64, contentType string) error {
+	containerURL, err := az.containerURL(containerName)
+	if err != nil {
+		return err
+	}
+	_, err = azblob.UploadStreamToBlockBlob(ctx, body, containerURL.NewBlockBlobURL(name), azblob.UploadStreamToBlockBlobOptions{
+		BlobHTTPHeaders: azblob.BlobHTTPHeaders{ContentType: contentType},
+	})
+	return err
+}
+
+func (az *azureBlobStorage) StatObject(ctx context.Context, name, containerName string) (*ObjectInfo, error) {
+	containerURL, err := az.containerURL(containerName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := containerURL.NewBlobURL(name).GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		// a HEAD response has no body, so the status tells a missing blob apart
+		var storageErr azblob.StorageError
+		if errors.As(err, &storageErr) && storageErr.Response() != nil && storageErr.Response().StatusCode == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return &ObjectInfo{
+		Size:         resp.ContentLength(),
+		ContentType:  resp.ContentType(),
+		LastModified: resp.LastModified(),
+	}, nil
+}
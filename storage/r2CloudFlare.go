@@ -2,14 +2,20 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 )
 
 type r2CloudFlare struct {
@@ -64,6 +70,19 @@ func (r *r2CloudFlare) GeneratePresignedUrlToUpload(ctx context.Context, imageUr
 	return presignResult.URL, nil
 }
 
+func (r *r2CloudFlare) GeneratePresignedUrlToRead(ctx context.Context, name, containerName string, expiry time.Duration) (string, error) {
+	presignedClient := s3.NewPresignClient(r.client)
+	key := r.generateKey(name, containerName)
+	presignResult, err := presignedClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: &r.cfg.Bucket,
+		Key:    &key,
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", err
+	}
+	return presignResult.URL, nil
+}
+
 func (r *r2CloudFlare) GetURLToRead(imageUrl, containerName string) string {
 	key := r.generateKey(imageUrl, containerName)
 	return fmt.Sprintf("%s/%s", r.cfg.PublicR2Url, key)
@@ -75,3 +94,80 @@ func (r *r2CloudFlare) GetUserImage(image string) string {
 	}
 	return r.GetURLToRead(image, ContainerProfiles)
 }
+
+func (r *r2CloudFlare) DeleteObjects(ctx context.Context, prefix, containerName string) error {
+	keyPrefix := r.generateKey(prefix, containerName)
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: &r.cfg.Bucket,
+		Prefix: &keyPrefix,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+		objects := make([]types.ObjectIdentifier, len(page.Contents))
+		for i, object := range page.Contents {
+			objects[i] = types.ObjectIdentifier{Key: object.Key}
+		}
+		// a page has at most 1000 keys which is also the limit of a single delete
+		resp, err := r.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: &r.cfg.Bucket,
+			Delete: &types.Delete{Objects: objects},
+		})
+		if err != nil {
+			return err
+		}
+		if len(resp.Errors) > 0 {
+			return fmt.Errorf("failed to delete %d objects, first error: %s", len(resp.Errors), aws.ToString(resp.Errors[0].Message))
+		}
+	}
+	return nil
+}
+
+func (r *r2CloudFlare) GetObject(ctx context.Context, name, containerName string) (io.ReadCloser, error) {
+	key := r.generateKey(name, containerName)
+	resp, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &r.cfg.Bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (r *r2CloudFlare) PutObject(ctx context.Context, name, containerName string, body io.Reader, size int64, contentType string) error {
+	key := r.generateKey(name, containerName)
+	_, err := r.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        &r.cfg.Bucket,
+		Key:           &key,
+		Body:          body,
+		ContentLength: aws.Int64(size),
+		ContentType:   &contentType,
+	})
+	return err
+}
+
+func (r *r2CloudFlare) StatObject(ctx context.Context, name, containerName string) (*ObjectInfo, error) {
+	key := r.generateKey(name, containerName)
+	resp, err := r.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &r.cfg.Bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		var responseErr *awshttp.ResponseError
+		if errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == http.StatusNotFound {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return &ObjectInfo{
+		Size:         aws.ToInt64(resp.ContentLength),
+		ContentType:  aws.ToString(resp.ContentType),
+		LastModified: aws.ToTime(resp.LastModified),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Codes follow RFC 6238 with the defaults every authenticator app supports:
+// SHA1, 6 digits and a 30 second period.
+const (
+	Digits     = 6
+	Period     = 30 * time.Second
+	secretSize = 20
+	// skew is the number of periods a code is still accepted before and after, for clock drift
+	skew = 1
+)
+
+var ErrInvalidSecret = fmt.Errorf("invalid totp secret")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI is the otpauth uri shown as a QR code to add the account to an authenticator app.
+func ProvisioningURI(secret, issuer, account string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// Validate checks the code against the periods around t. It returns the period the code belongs to,
+// callers store it to reject the same code or an older one being used again.
+func Validate(secret, code string, t time.Time) (int64, bool, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false, ErrInvalidSecret
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return 0, false, nil
+	}
+	step := t.Unix() / int64(Period.Seconds())
+	for i := -skew; i <= skew; i++ {
+		expected := generate(key, step+int64(i))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step + int64(i), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// Generate returns the code of the period of t, what an authenticator app shows at that time.
+func Generate(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", ErrInvalidSecret
+	}
+	return generate(key, t.Unix()/int64(Period.Seconds())), nil
+}
+
+func generate(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	// dynamic truncation, RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
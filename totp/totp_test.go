@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed of the test vectors in RFC 6238 appendix B, "12345678901234567890" base32 encoded
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerate(t *testing.T) {
+	// the RFC vectors have 8 digits, the 6 digit codes are their last 6
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := Generate(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Generate at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateStep(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := now.Unix() / int64(Period.Seconds())
+	tests := []struct {
+		name     string
+		codeAt   time.Time
+		wantStep int64
+		wantOk   bool
+	}{
+		{name: "current period", codeAt: now, wantStep: step, wantOk: true},
+		{name: "previous period for clock drift", codeAt: now.Add(-Period), wantStep: step - 1, wantOk: true},
+		{name: "next period for clock drift", codeAt: now.Add(Period), wantStep: step + 1, wantOk: true},
+		{name: "two periods ago", codeAt: now.Add(-2 * Period)},
+		{name: "two periods ahead", codeAt: now.Add(2 * Period)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Generate(rfcSecret, tt.codeAt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotStep, ok, err := Validate(rfcSecret, code, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok != tt.wantOk || gotStep != tt.wantStep {
+				t.Errorf("Validate = %d, %v, want %d, %v", gotStep, ok, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	if _, _, err := Validate("not base32!", "123456", time.Now()); err != ErrInvalidSecret {
+		t.Errorf("err = %v, want %v", err, ErrInvalidSecret)
+	}
+	if _, ok, _ := Validate(rfcSecret, "12345", time.Now()); ok {
+		t.Error("a code of the wrong length was accepted")
+	}
+}